@@ -22,12 +22,15 @@ import (
 	"fmt"
 	"os"
 	"time"
+	// @note: embeds the time zone database, used to evaluate the apply windows
+	_ "time/tzdata"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/appvia/terraform-controller/pkg/server"
+	"github.com/appvia/terraform-controller/pkg/utils/notifications"
 	"github.com/appvia/terraform-controller/pkg/version"
 )
 
@@ -53,19 +56,35 @@ func main() {
 
 	flags := cmd.Flags()
 	flags.Bool("verbose", false, "Enable verbose logging")
+	flags.BoolVar(&config.EnableLeaderElection, "enable-leader-election", false, "Indicates the controllers are only run by the elected leader, required when running multiple replicas")
+	flags.BoolVar(&config.EnableModulePinning, "enable-module-pinning", true, "Indicates the controller resolves github and registry modules to an immutable revision")
+	flags.BoolVar(&config.EnableStateLocking, "enable-state-locking", true, "Indicates terraform acquires the state lock, preventing concurrent changes to the same state")
 	flags.BoolVar(&config.EnableTerraformVersions, "enable-terraform-versions", true, "Indicates the terraform version can be overridden by configurations")
 	flags.BoolVar(&config.EnableWatchers, "enable-watchers", true, "Indicates we create watcher jobs in the configuration namespaces")
 	flags.BoolVar(&config.EnableWebhook, "enable-webhook", true, "Indicates we should register the webhooks")
 	flags.BoolVar(&config.RegisterCRDs, "register-crds", true, "Indicates the controller to register its own CRDs")
 	flags.DurationVar(&config.DriftControllerInterval, "drift-controller-interval", 5*time.Minute, "Is the check interval for the controller to search for configurations which should be checked for drift")
-	flags.DurationVar(&config.DriftInterval, "drift-interval", 3*time.Hour, "The minimum duration the controller will wait before triggering a drift check")
+	flags.DurationVar(&config.DriftInterval, "drift-interval", 3*time.Hour, "The minimum duration the controller will wait before triggering a drift check, unless a drift schedule is defined")
+	flags.DurationVar(&config.ExpiryWarning, "expiry-warning", time.Hour, "The duration before a configuration with a ttl expires a warning event is raised")
+	flags.DurationVar(&config.NotificationBackoff, "notification-backoff", notifications.DefaultBackoff, "The delay before a failed notification is retried, doubled on each attempt")
+	flags.DurationVar(&config.RetryBackoff, "retry-backoff", 30*time.Second, "The default delay before a failed plan or apply is retried, doubled on each attempt")
+	flags.DurationVar(&config.StateLockTimeout, "state-lock-timeout", 5*time.Minute, "The duration terraform waits to acquire the state lock before failing")
 	flags.DurationVar(&config.ResyncPeriod, "resync-period", 5*time.Hour, "The resync period for the controller")
 	flags.Float64Var(&config.DriftThreshold, "drift-threshold", 0.10, "The maximum percentage of configurations that can be run drift detection at any one time")
 	flags.IntVar(&config.APIServerPort, "apiserver-port", 10080, "The port the apiserver should be listening on")
+	flags.IntVar(&config.MaxConcurrentJobs, "max-concurrent-jobs", 0, "The maximum number of terraform plan and apply jobs running at any one time, zero is unlimited")
+	flags.IntVar(&config.MaxConcurrentReconciles, "max-concurrent-reconciles", 10, "The maximum number of configurations which can be reconciled concurrently")
 	flags.IntVar(&config.MetricsPort, "metrics-port", 9090, "The port the metric endpoint binds to")
+	flags.IntVar(&config.NotificationAttempts, "notification-attempts", notifications.DefaultAttempts, "The maximum number of attempts at delivering an event to a notification sink")
+	flags.IntVar(&config.RetryMaxAttempts, "retry-max-attempts", 1, "The default maximum number of attempts at a failed plan or apply for a generation, one disables retries")
+	flags.IntVar(&config.RevisionHistoryLimit, "revision-history-limit", 10, "The maximum number of configuration revisions retained per configuration")
 	flags.IntVar(&config.WebhookPort, "webhooks-port", 10081, "The port the webhook endpoint binds to")
 	flags.StringSliceVar(&config.ExecutorSecrets, "executor-secret", []string{}, "Name of a secret in controller namespace which should be added to the job")
+	flags.StringVar(&config.BackendConfig, "backend-config", "", "The JSON encoded configuration for the default terraform state backend, i.e. bucket or region")
+	flags.StringVar(&config.BackendSecret, "backend-secret", "", "Name of the secret in controller namespace containing the credentials for the default backend")
+	flags.StringVar(&config.BackendType, "backend-type", "kubernetes", "The default terraform state backend (kubernetes, s3, gcs, azurerm or http)")
 	flags.StringVar(&config.ExecutorImage, "executor-image", "ghcr.io/appvia/terraform-executor:latest", "The image to use for the executor")
+	flags.StringVar(&config.GitHubToken, "github-token", os.Getenv("GITHUB_TOKEN"), "An optional github token used when resolving the revision of github modules")
 	flags.StringVar(&config.InfracostsImage, "infracost-image", "infracosts/infracost:latest", "The image to use for the infracosts")
 	flags.StringVar(&config.InfracostsSecretName, "cost-secret", "", "Name of the secret on the controller namespace containing your infracost token")
 	flags.StringVar(&config.Namespace, "namespace", os.Getenv("KUBE_NAMESPACE"), "The namespace the controller is running in and where jobs will run")
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -52,8 +53,27 @@ func NewConfiguration(namespace, name string) *Configuration {
 const (
 	// ApplyAnnotation is the annotation used to mark a resource as a plan rather than apply
 	ApplyAnnotation = "terraform.appvia.io/apply"
+	// ApprovedByAnnotation is the annotation used to record the identity which approved the apply
+	ApprovedByAnnotation = "terraform.appvia.io/approved-by"
+	// DestroyApprovedAnnotation is the annotation used to approve the terraform destroy of a configuration
+	// with the RequireApproval deletion policy
+	DestroyApprovedAnnotation = "terraform.appvia.io/destroy-approved"
+	// DestroyApprovedByAnnotation is the annotation used to record the identity which approved the destroy
+	DestroyApprovedByAnnotation = "terraform.appvia.io/destroy-approved-by"
 	// DriftAnnotation is the annotation used to mark a resource for drift detection
 	DriftAnnotation = "terraform.appvia.io/drift"
+	// ExpiresAtAnnotation is the annotation used to set the time (RFC3339) the configuration expires
+	// and is deleted. This takes precedence over the spec.ttl, and can be used to extend the expiry
+	ExpiresAtAnnotation = "terraform.appvia.io/expires-at"
+	// RetryAnnotation is the annotation used to request a new plan, and apply if approved, for the
+	// current generation. The value is a timestamp, changing it triggers another run
+	RetryAnnotation = "terraform.appvia.io/retry"
+	// UnlockAnnotation is the annotation used to request the terraform state lock with the given
+	// lock id is force released
+	UnlockAnnotation = "terraform.appvia.io/unlock"
+	// UnlockRequestedByAnnotation is the annotation used to record the identity which requested
+	// the state lock is released
+	UnlockRequestedByAnnotation = "terraform.appvia.io/unlock-requested-by"
 	// ReconcileAnnotation is the label used control reconciliation
 	ReconcileAnnotation = "terraform.appvia.io/reconcile"
 	// OrphanAnnotation is the label used to orphan a configuration
@@ -63,10 +83,26 @@ const (
 )
 
 const (
+	// TerraformDriftConfigSecretKey is the key holding the compressed json output of the terraform plan run
+	// alongside the refresh-only plan, used to classify the drift
+	TerraformDriftConfigSecretKey = "config.json"
+	// TerraformDriftExitCodeSecretKey is the key holding the exit code of the refresh-only terraform plan
+	TerraformDriftExitCodeSecretKey = "exitcode"
+	// TerraformDriftSecretKey is the key used by the secret holding the drift report
+	TerraformDriftSecretKey = "drift.json"
+	// TerraformPlanSecretKey is the key used by the secret holding the compressed terraform plan
+	TerraformPlanSecretKey = "plan.out"
+	// TerraformPlanJSONSecretKey is the key used by the secret holding the compressed json output of the plan
+	TerraformPlanJSONSecretKey = "plan.json"
 	// TerraformStateSecretKey is the key used by the terraform state secret
 	TerraformStateSecretKey = "tfstate"
+	// ImportStateSecretKey is the key in the spec.importState secret holding the terraform state
+	ImportStateSecretKey = "terraform.tfstate"
 )
 
+// DefaultWorkspace is the terraform workspace used when the configuration does not define one
+const DefaultWorkspace = "default"
+
 const (
 	// CheckovJobTemplateConfigMapKey is the key name for the job template in the configmap
 	CheckovJobTemplateConfigMapKey = "checkov.yaml"
@@ -76,6 +112,8 @@ const (
 	TerraformVariablesConfigMapKey = "variables.tfvars.json"
 	// TerraformProviderConfigMapKey is the key name for the terraform variables in the configmap
 	TerraformProviderConfigMapKey = "provider.tf"
+	// TerraformImportsConfigMapKey is the key name for the terraform import blocks in the configmap
+	TerraformImportsConfigMapKey = "imports.tf"
 	// TerraformJobTemplateConfigMapKey is the key name for the job template in the configmap
 	TerraformJobTemplateConfigMapKey = "job.yaml"
 )
@@ -91,6 +129,16 @@ const (
 	ConfigurationNamespaceLabel = "terraform.appvia.io/namespace"
 	// ConfigurationStageLabel is the label used to identify a configuration stage
 	ConfigurationStageLabel = "terraform.appvia.io/stage"
+	// ConfigurationUpstreamLabel is the label used to identify the upstream outputs used by a job
+	ConfigurationUpstreamLabel = "terraform.appvia.io/upstream"
+	// ConfigurationAttemptLabel is the label used to identify the attempt at running a stage
+	ConfigurationAttemptLabel = "terraform.appvia.io/attempt"
+	// ConfigurationDriftLabel is the label used to identify the drift check a job was run for, or remediates
+	ConfigurationDriftLabel = "terraform.appvia.io/drift"
+	// ConfigurationLockLabel is the label used to identify the state lock released by an unlock job
+	ConfigurationLockLabel = "terraform.appvia.io/lock-id"
+	// ConfigurationResetLabel is the label used to identify the plan reset a job was created after
+	ConfigurationResetLabel = "terraform.appvia.io/reset"
 )
 
 const (
@@ -98,8 +146,14 @@ const (
 	StageTerraformApply = "apply"
 	// StageTerraformDestroy is the stage for a terraform destroy
 	StageTerraformDestroy = "destroy"
+	// StageTerraformDestroyPlan is the stage for a terraform plan -destroy, run before the destroy is approved
+	StageTerraformDestroyPlan = "destroy-plan"
+	// StageTerraformDrift is the stage for a terraform plan -refresh-only, used to detect drift
+	StageTerraformDrift = "drift"
 	// StageTerraformPlan is the stage for a terraform plan
 	StageTerraformPlan = "plan"
+	// StageTerraformUnlock is the stage for force releasing the terraform state lock
+	StageTerraformUnlock = "unlock"
 	// StageTerraformVerify is the stage for a verify
 	StageTerraformVerify = "verify"
 )
@@ -111,6 +165,51 @@ var ConfigurationGVK = schema.GroupVersionKind{
 	Kind:    ConfigurationKind,
 }
 
+// DeletionPolicy defines what happens to the resources when the configuration is deleted
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete indicates the resources are destroyed when the configuration is deleted
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan indicates the resources are left in place when the configuration is deleted
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+	// DeletionPolicyRequireApproval indicates a terraform plan -destroy is produced when the configuration
+	// is deleted, and the resources are only destroyed once approved via the destroy-approved annotation
+	DeletionPolicyRequireApproval DeletionPolicy = "RequireApproval"
+)
+
+// DriftRemediation defines the action taken when drift is detected on the configuration
+type DriftRemediation string
+
+const (
+	// DriftRemediationNone indicates drift is only reported
+	DriftRemediationNone DriftRemediation = "none"
+	// DriftRemediationAuto indicates drift is remediated by automatically applying the current generation
+	DriftRemediationAuto DriftRemediation = "auto"
+	// DriftRemediationApproval indicates the apply remediating the drift waits for an approval via the
+	// apply annotation
+	DriftRemediationApproval DriftRemediation = "approval"
+)
+
+const (
+	// RemediationPending indicates the drift is waiting to be remediated by a terraform apply
+	RemediationPending = "Pending"
+	// RemediationApplied indicates the drift has been remediated by a terraform apply
+	RemediationApplied = "Applied"
+)
+
+// ModuleUpdatePolicy defines which newer versions of a module should be reported
+type ModuleUpdatePolicy string
+
+const (
+	// ModuleUpdatePolicyNone indicates no checks for newer versions are performed
+	ModuleUpdatePolicyNone ModuleUpdatePolicy = "none"
+	// ModuleUpdatePolicyPatch indicates newer patch versions of the module are reported
+	ModuleUpdatePolicyPatch ModuleUpdatePolicy = "patch"
+	// ModuleUpdatePolicyMinor indicates newer minor or patch versions of the module are reported
+	ModuleUpdatePolicyMinor ModuleUpdatePolicy = "minor"
+)
+
 // ProviderReference is the reference to the provider which is used to create
 // the configuration
 type ProviderReference struct {
@@ -168,8 +267,13 @@ func (w *WriteConnectionSecret) KeysMap() (map[string]string, error) {
 	return keys, nil
 }
 
-// ValueFromSource defines a value which is taken from a secret
+// ValueFromSource defines a value which is taken from a secret or the output of another configuration
 type ValueFromSource struct {
+	// Configuration is the name of a configuration in the same namespace, the key is the name
+	// of the terraform output. The configuration will wait for the referenced configuration to
+	// be ready before running.
+	// +kubebuilder:validation:Optional
+	Configuration string `json:"configuration,omitempty"`
 	// Optional indicates the secret can be optional, i.e if the secret does not exist, or the key is
 	// not contained in the secret, we ignore the error
 	// +kubebuilder:validation:Optional
@@ -178,18 +282,159 @@ type ValueFromSource struct {
 	// +kubebuilder:validation:Required
 	Key string `json:"key"`
 	// Secret is the name of the secret in the configuration namespace
+	// +kubebuilder:validation:Optional
+	Secret string `json:"secret,omitempty"`
+}
+
+// ApplyWindow defines a recurring window of time during which a configuration may be applied
+type ApplyWindow struct {
+	// Schedule is a cron expression (minute hour day-of-month month day-of-week) defining when
+	// the window opens, i.e. "0 9 * * mon-thu" opens the window at 09:00 Monday to Thursday
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+	// Duration is how long the window remains open once opened, i.e. 8h
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone is the IANA time zone the schedule is evaluated in i.e. Europe/London, defaults
+	// to UTC
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// DriftSchedule defines how often drift detection is run, either on a cron schedule or a fixed
+// interval. The checks are spread out by a jitter derived from the configuration uid
+type DriftSchedule struct {
+	// Interval is the time between drift checks i.e. 1h or 168h, this cannot be used with
+	// the schedule
+	// +kubebuilder:validation:Optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Schedule is a cron expression (minute hour day-of-month month day-of-week) defining when
+	// drift is checked, i.e. "0 * * * *" checks every hour
+	// +kubebuilder:validation:Optional
+	Schedule string `json:"schedule,omitempty"`
+	// TimeZone is the IANA time zone the schedule is evaluated in i.e. Europe/London, defaults
+	// to UTC
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// GetInterval returns the interval of the drift schedule, or zero if not defined
+func (d *DriftSchedule) GetInterval() time.Duration {
+	if d.Interval == nil {
+		return 0
+	}
+
+	return d.Interval.Duration
+}
+
+// Retry defines the retry policy for failed terraform plan and apply jobs
+type Retry struct {
+	// MaxAttempts is the maximum number of attempts at running a stage for a generation,
+	// including the first. A value of one disables retries
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// Backoff is the delay before the first retry, which is doubled on each subsequent
+	// attempt, i.e. 30s
+	// +kubebuilder:validation:Optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+}
+
+// Operations are one-shot terraform operations included in the next plan and apply, i.e. to recover
+// a broken resource
+type Operations struct {
+	// Replace is a collection of resource addresses terraform should replace, regardless of
+	// any changes, i.e. aws_instance.this (terraform plan -replace)
+	// +kubebuilder:validation:Optional
+	Replace []string `json:"replace,omitempty"`
+	// Target is a collection of resource or module addresses terraform should limit the
+	// plan to, i.e. module.db (terraform plan -target)
+	// +kubebuilder:validation:Optional
+	Target []string `json:"target,omitempty"`
+}
+
+// DestroyProtection defines which resources require approval before being destroyed or replaced
+type DestroyProtection struct {
+	// AllResources indicates any resource being destroyed or replaced requires approval
+	// +kubebuilder:validation:Optional
+	AllResources bool `json:"allResources,omitempty"`
+	// ResourceTypes is a collection of terraform resource types i.e. aws_db_instance, which
+	// require approval before being destroyed or replaced
+	// +kubebuilder:validation:Optional
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+}
+
+// IsEnabled returns true if the destroy protection is protecting any resources
+func (d *DestroyProtection) IsEnabled() bool {
+	return d.AllResources || len(d.ResourceTypes) > 0
+}
+
+// IsProtected returns true if the resource type is protected
+func (d *DestroyProtection) IsProtected(resourceType string) bool {
+	if d.AllResources {
+		return true
+	}
+	for _, x := range d.ResourceTypes {
+		if x == resourceType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Import defines an existing resource which should be imported into the terraform state
+type Import struct {
+	// Address is the terraform resource address the resource is imported into i.e.
+	// aws_s3_bucket.this or module.vpc.aws_vpc.this
+	// +kubebuilder:validation:Required
+	Address string `json:"address"`
+	// ID is the provider specific identifier of the existing resource
 	// +kubebuilder:validation:Required
-	Secret string `json:"secret"`
+	ID string `json:"id"`
 }
 
 // ConfigurationSpec defines the desired state of a terraform
 // +k8s:openapi-gen=true
 type ConfigurationSpec struct {
+	// ApplyWindow restricts when an approved terraform apply is permitted to run. Outside of the
+	// window the apply is held until the window next opens, plans are not affected. This takes
+	// precedence over any window defined by a policy.
+	// +kubebuilder:validation:Optional
+	ApplyWindow *ApplyWindow `json:"applyWindow,omitempty"`
 	// Auth is used to configure any options required when the source of the terraform
 	// module is private or requires credentials to retrieve. This could be SSH keys or git
 	// user/pass or AWS credentials for an s3 bucket.
 	// +kubebuilder:validation:Optional
 	Auth *v1.SecretReference `json:"auth,omitempty"`
+	// DeletionPolicy defines what happens to the resources when the configuration is deleted, i.e.
+	// Delete, Orphan or RequireApproval, defaulting to Delete. RequireApproval produces a terraform
+	// plan -destroy and waits for the terraform.appvia.io/destroy-approved annotation before the
+	// resources are destroyed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Delete;Orphan;RequireApproval
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// DependsOn is a collection of configuration names within the same namespace which must be
+	// ready before this configuration is planned. The configuration will also not be destroyed
+	// while other configurations still depend on it.
+	// +kubebuilder:validation:Optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// DestroyProtection provides the ability to require a manual approval when the terraform
+	// plan destroys or replaces resources, regardless of auto approval being enabled.
+	// +kubebuilder:validation:Optional
+	DestroyProtection *DestroyProtection `json:"destroyProtection,omitempty"`
+	// DriftRemediation defines the action taken when drift detection finds changes terraform
+	// would make, i.e. none, auto or approval, defaulting to none. In auto the current generation
+	// is applied, subject to any apply window and policy. In approval the terraform apply
+	// annotation is set to false, and the apply waits for an approval.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=none;auto;approval
+	DriftRemediation DriftRemediation `json:"driftRemediation,omitempty"`
+	// DriftSchedule defines how often drift detection is run on the configuration, overriding
+	// any schedule defined by a policy and the controller default. This is only used when drift
+	// detection is enabled.
+	// +kubebuilder:validation:Optional
+	DriftSchedule *DriftSchedule `json:"driftSchedule,omitempty"`
 	// EnableAutoApproval when enabled indicates the configuration does not need to be
 	// manually approved. On a change to the configuration, the controller will automatically
 	// approve the configuration. Note it still needs to adhere to any checks or policies.
@@ -199,15 +444,49 @@ type ConfigurationSpec struct {
 	// for any drift between the expected and current state. If any drift is detected the
 	// status is changed and a kubernetes event raised.
 	EnableDriftDetection bool `json:"enableDriftDetection,omitempty"`
+	// ImportState is a reference to a secret in the configuration namespace containing an
+	// existing terraform state under the terraform.tfstate key. The state is copied into the
+	// configuration state before the first plan, and is ignored once a state exists. Any
+	// resources created by the plan while this is set require an approval.
+	// +kubebuilder:validation:Optional
+	ImportState *v1.SecretReference `json:"importState,omitempty"`
+	// Imports is a collection of existing resources which should be imported into the state
+	// of the configuration. These are rendered as terraform import blocks (requires terraform
+	// 1.5 or above), and the configuration will not be applied if the plan attempts to create
+	// any of the addresses instead.
+	// +kubebuilder:validation:Optional
+	Imports []Import `json:"imports,omitempty"`
 	// Module is the URL to the source of the terraform module. The format of the URL is
 	// a direct implementation of terraform's module reference. Please see the following
 	// repository for more details https://github.com/hashicorp/go-getter
 	// +kubebuilder:validation:Required
 	Module string `json:"module"`
+	// ModuleUpdatePolicy indicates the controller should report newer versions of the module
+	// which have been released, i.e. none, patch or minor. Note the module must reference a
+	// semantic version tag or registry version.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=none;patch;minor
+	ModuleUpdatePolicy ModuleUpdatePolicy `json:"moduleUpdatePolicy,omitempty"`
+	// Operations are one-shot replace and target operations included in the next terraform
+	// plan. The apply always requires an approval, and the operations are removed once the
+	// apply has completed successfully.
+	// +kubebuilder:validation:Optional
+	Operations *Operations `json:"operations,omitempty"`
+	// PlanOnly when enabled runs the configuration in a read-only mode. Terraform plans are
+	// produced on changes to the configuration and drift, but the plan is never applied,
+	// regardless of the approval. The status reports if applying the plan would result in
+	// any changes.
+	// +kubebuilder:validation:Optional
+	PlanOnly bool `json:"planOnly,omitempty"`
 	// ProviderRef is the reference to the provider which should be used to execute this
 	// configuration.
 	// +kubebuilder:validation:Required
 	ProviderRef *ProviderReference `json:"providerRef"`
+	// Retry defines how failed terraform plan and apply jobs are retried for the same
+	// generation, defaulting to the controller policy. Failures caused by misconfigured
+	// provider credentials are never retried.
+	// +kubebuilder:validation:Optional
+	Retry *Retry `json:"retry,omitempty"`
 	// WriteConnectionSecretToRef is the name for a secret. On execution of the terraform module
 	// any module outputs are written to this secret. The outputs are automatically uppercased
 	// and ready to be consumed as environment variables.
@@ -224,11 +503,24 @@ type ConfigurationSpec struct {
 	// is taken from a secret
 	// +kubebuilder:validation:Optional
 	ValueFrom []ValueFromSource `json:"valueFrom,omitempty"`
+	// TTL is the time to live of the configuration from the time it was created. Once expired the
+	// configuration is deleted, destroying the resources, i.e. ephemeral preview environments. The
+	// expiry can be extended via the terraform.appvia.io/expires-at annotation.
+	// +kubebuilder:validation:Optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
 	// TerraformVersion provides the ability to override the default terraform version. Before
 	// changing this field its best to consult with platform administrator. As the
 	// value of this field is used to change the tag of the terraform container image.
 	// +kubebuilder:validation:Optional
 	TerraformVersion string `json:"terraformVersion,omitempty"`
+	// Workspace is the name of the terraform workspace the configuration is executed within,
+	// defaulting to the default workspace. The workspace forms part of the state location and
+	// permits multiple copies of the same stack, or existing workspace based stacks, to be
+	// managed. The workspace cannot be changed once set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Workspace string `json:"workspace,omitempty"`
 }
 
 // +kubebuilder:webhook:name=configurations.terraform.appvia.io,mutating=false,path=/validate/terraform.appvia.io/configurations,verbs=create;update,groups="terraform.appvia.io",resources=configurations,versions=v1alpha1,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1
@@ -243,6 +535,7 @@ type ConfigurationSpec struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Module",type="string",JSONPath=".spec.module"
 // +kubebuilder:printcolumn:name="Secret",type="string",JSONPath=".spec.writeConnectionSecretToRef.name"
+// +kubebuilder:printcolumn:name="Plan",type="string",JSONPath=".status.plan.summary"
 // +kubebuilder:printcolumn:name="Estimated",type="string",JSONPath=".status.costs.monthly"
 // +kubebuilder:printcolumn:name="Synchronized",type="string",JSONPath=".status.resourceStatus"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
@@ -268,6 +561,132 @@ type CostStatus struct {
 	Monthly string `json:"monthly,omitempty"`
 }
 
+// PlanResource is a resource affected by the terraform plan
+type PlanResource struct {
+	// Action is the action terraform will perform on the resource i.e. create, update, delete, replace
+	// or import
+	Action string `json:"action"`
+	// Address is the terraform address of the resource
+	Address string `json:"address"`
+}
+
+// DriftResource is a resource which has drifted from the configuration
+type DriftResource struct {
+	// Action is the action terraform would perform on the resource i.e. create, update, delete
+	// or replace
+	Action string `json:"action"`
+	// Address is the terraform address of the resource
+	Address string `json:"address"`
+	// Kind indicates if the drift is a config change, which applying the configuration would
+	// correct, or a refresh-only change, which only updates the state
+	Kind string `json:"kind"`
+}
+
+// DriftStatus is a summary of the last drift detection for the configuration
+type DriftStatus struct {
+	// CheckedAt is the time the drift detection was last performed
+	// +kubebuilder:validation:Optional
+	CheckedAt *metav1.Time `json:"checkedAt,omitempty"`
+	// ConfigChanges is the number of resources which applying the configuration would change
+	ConfigChanges int `json:"configChanges"`
+	// RefreshOnlyChanges is the number of resources changed outside of terraform which applying
+	// the configuration would not change
+	RefreshOnlyChanges int `json:"refreshOnlyChanges"`
+	// Remediation is the state of the remediation of the drift i.e. Pending or Applied, only
+	// set when spec.driftRemediation is enabled
+	// +kubebuilder:validation:Optional
+	Remediation string `json:"remediation,omitempty"`
+	// ReportSecret is the name of the secret in the configuration namespace containing the
+	// full drift report, including the attributes which have changed
+	// +kubebuilder:validation:Optional
+	ReportSecret string `json:"reportSecret,omitempty"`
+	// Resources is a list of the drifted resources. Note the list is capped in size, with
+	// truncated indicating resources have been omitted.
+	// +kubebuilder:validation:Optional
+	Resources []DriftResource `json:"resources,omitempty"`
+	// Summary is a human readable summary of the drift i.e. 1 config change, 2 refresh-only changes
+	// +kubebuilder:validation:Optional
+	Summary string `json:"summary,omitempty"`
+	// Truncated indicates the list of resources was capped
+	// +kubebuilder:validation:Optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// PlanStatus is a summary of the last terraform plan for the configuration
+type PlanStatus struct {
+	// Add is the number of resources which will be created
+	Add int `json:"add"`
+	// Change is the number of resources which will be updated in-place
+	Change int `json:"change"`
+	// Destroy is the number of resources which will be destroyed
+	Destroy int `json:"destroy"`
+	// Generation is the generation of the configuration the plan was produced for
+	// +kubebuilder:validation:Optional
+	Generation int64 `json:"generation,omitempty"`
+	// HasChanges indicates applying the plan would result in changes to the resources
+	// +kubebuilder:validation:Optional
+	HasChanges bool `json:"hasChanges,omitempty"`
+	// Import is the number of existing resources which will be imported
+	// +kubebuilder:validation:Optional
+	Import int `json:"import,omitempty"`
+	// Replace is the number of resources which will be destroyed and recreated
+	Replace int `json:"replace"`
+	// Resources is a list of the resources affected by the plan. Note the list is capped
+	// in size, with truncated indicating resources have been omitted.
+	// +kubebuilder:validation:Optional
+	Resources []PlanResource `json:"resources,omitempty"`
+	// Summary is a human readable summary of the plan i.e. 1 to add, 3 to destroy
+	// +kubebuilder:validation:Optional
+	Summary string `json:"summary,omitempty"`
+	// Truncated indicates the list of resources was capped
+	// +kubebuilder:validation:Optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ModuleStatus is the resolved source of the terraform module
+type ModuleStatus struct {
+	// Revision is the immutable revision the module resolved to, i.e. a commit sha or
+	// registry version
+	Revision string `json:"revision"`
+	// Source is the source of the module pinned to the revision
+	Source string `json:"source"`
+	// Update is a newer version of the module permitted by the module update policy
+	// +kubebuilder:validation:Optional
+	Update string `json:"update,omitempty"`
+}
+
+// RetryStatus is the status of the attempts at running a stage
+type RetryStatus struct {
+	// Stage is the stage being retried, i.e. plan or apply
+	Stage string `json:"stage"`
+	// Attempt is the current attempt at running the stage for the generation
+	Attempt int `json:"attempt"`
+	// MaxAttempts is the maximum number of attempts permitted
+	MaxAttempts int `json:"maxAttempts"`
+	// NextAttemptTime is when the next attempt will be made, if any
+	// +kubebuilder:validation:Optional
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+}
+
+// StateLockStatus is the holder of the terraform state lock, as reported by terraform
+type StateLockStatus struct {
+	// ID is the identifier of the lock, used to force release the lock
+	ID string `json:"id"`
+	// Operation is the terraform operation which acquired the lock, i.e. OperationTypeApply
+	// +kubebuilder:validation:Optional
+	Operation string `json:"operation,omitempty"`
+	// Who is the identity which acquired the lock, i.e. user@hostname
+	// +kubebuilder:validation:Optional
+	Who string `json:"who,omitempty"`
+	// Created is when the lock was acquired
+	// +kubebuilder:validation:Optional
+	Created string `json:"created,omitempty"`
+	// FailedUnlocks is the number of failed attempts at force releasing the lock, each request to
+	// release the lock is run as a new attempt
+	// +kubebuilder:validation:Optional
+	FailedUnlocks int `json:"failedUnlocks,omitempty"`
+}
+
 // ResourceStatus is the status of the resources
 type ResourceStatus string
 
@@ -290,9 +709,34 @@ type ConfigurationStatus struct {
 	// when the integration has been configured by the administrator.
 	// +kubebuilder:validation:Optional
 	Costs *CostStatus `json:"costs,omitempty"`
+	// Drift is a summary of the last drift detection, the full report is held in a secret in
+	// the configuration namespace
+	// +kubebuilder:validation:Optional
+	Drift *DriftStatus `json:"drift,omitempty"`
 	// DriftTimestamp is the timestamp of the last drift detection
 	// +kubebuilder:validation:Optional
 	DriftTimestamp string `json:"driftTimestamp,omitempty"`
+	// ExpiresAt is the time the configuration expires and will be deleted
+	// +kubebuilder:validation:Optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// Module is the source of the module resolved to an immutable revision, the revision is
+	// used by the plan and apply, with the configuration planned again when it changes
+	// +kubebuilder:validation:Optional
+	Module *ModuleStatus `json:"module,omitempty"`
+	// NextDriftCheck is the time the next drift check on the configuration is scheduled
+	// +kubebuilder:validation:Optional
+	NextDriftCheck *metav1.Time `json:"nextDriftCheck,omitempty"`
+	// Operations are the replace and target operations included in the current plan, these
+	// are cleared once applied
+	// +kubebuilder:validation:Optional
+	Operations *Operations `json:"operations,omitempty"`
+	// Plan is a summary of the changes in the last terraform plan
+	// +kubebuilder:validation:Optional
+	Plan *PlanStatus `json:"plan,omitempty"`
+	// PlanResets is the number of times a saved plan has been discarded as missing or stale, the
+	// jobs of the replacement plan are labelled with it so they are never confused with the old
+	// +kubebuilder:validation:Optional
+	PlanResets int `json:"planResets,omitempty"`
 	// Resources is the number of managed cloud resources which are currently under management.
 	// This field is taken from the terraform state itself.
 	// +kubebuilder:validation:Optional
@@ -300,10 +744,23 @@ type ConfigurationStatus struct {
 	// ResourceStatus indicates the status of the resources and if the resources are insync with the
 	// configuration
 	ResourceStatus ResourceStatus `json:"resourceStatus,omitempty"`
+	// Retry is the status of the attempts at running the current stage, when failed jobs are
+	// being retried
+	// +kubebuilder:validation:Optional
+	Retry *RetryStatus `json:"retry,omitempty"`
+	// StateLock is the holder of the terraform state lock when the last job failed to acquire
+	// it, the lock can be force released using the unlock annotation
+	// +kubebuilder:validation:Optional
+	StateLock *StateLockStatus `json:"stateLock,omitempty"`
 	// TerraformVersion is the version of terraform which was last used to run this
 	// configuration
 	// +kubebuilder:validation:Optional
 	TerraformVersion string `json:"terraformVersion,omitempty"`
+	// UpstreamChecksum is a checksum of the outputs taken from other configurations
+	// (spec.valueFrom[].configuration) and the resolved module revision when the
+	// configuration was last applied
+	// +kubebuilder:validation:Optional
+	UpstreamChecksum string `json:"upstreamChecksum,omitempty"`
 }
 
 // GetNamespacedName returns the namespaced resource type
@@ -342,6 +799,111 @@ func (c *Configuration) HasVariables() bool {
 	return true
 }
 
+// HasUpstreams returns true if the configuration consumes the outputs of other configurations
+func (c *Configuration) HasUpstreams() bool {
+	for _, x := range c.Spec.ValueFrom {
+		if x.Configuration != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetDependencies returns the names of the configurations this configuration depends upon, either
+// via spec.dependsOn or by consuming their outputs in spec.valueFrom
+func (c *Configuration) GetDependencies() []string {
+	var list []string
+
+	seen := make(map[string]bool)
+	for _, x := range c.Spec.DependsOn {
+		if !seen[x] {
+			list = append(list, x)
+			seen[x] = true
+		}
+	}
+	for _, x := range c.Spec.ValueFrom {
+		if x.Configuration != "" && !seen[x.Configuration] {
+			list = append(list, x.Configuration)
+			seen[x.Configuration] = true
+		}
+	}
+
+	return list
+}
+
+// IsDependentOn returns true if the configuration depends on the named configuration
+func (c *Configuration) IsDependentOn(name string) bool {
+	for _, x := range c.GetDependencies() {
+		if x == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasImports returns true if the configuration is importing any existing resources
+func (c *Configuration) HasImports() bool {
+	return len(c.Spec.Imports) > 0
+}
+
+// HasOperations returns true if the configuration has any replace or target operations
+func (c *Configuration) HasOperations() bool {
+	return c.Spec.Operations != nil && (len(c.Spec.Operations.Replace) > 0 || len(c.Spec.Operations.Target) > 0)
+}
+
+// GetExpiryTime returns the time the configuration expires, with the expires-at annotation taking
+// precedence over the spec.ttl. The boolean is false when the configuration does not expire
+func (c *Configuration) GetExpiryTime() (time.Time, bool, error) {
+	if value := c.GetAnnotations()[ExpiresAtAnnotation]; value != "" {
+		expires, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%s annotation: %q is not a valid RFC3339 time", ExpiresAtAnnotation, value)
+		}
+
+		return expires, true, nil
+	}
+	if c.Spec.TTL == nil {
+		return time.Time{}, false, nil
+	}
+
+	return c.GetCreationTimestamp().Add(c.Spec.TTL.Duration), true, nil
+}
+
+// GetDeletionPolicy returns the deletion policy of the configuration, defaulting to Delete
+func (c *Configuration) GetDeletionPolicy() DeletionPolicy {
+	if c.Spec.DeletionPolicy == "" {
+		return DeletionPolicyDelete
+	}
+
+	return c.Spec.DeletionPolicy
+}
+
+// GetDriftRemediation returns the drift remediation of the configuration, defaulting to none
+func (c *Configuration) GetDriftRemediation() DriftRemediation {
+	if c.Spec.DriftRemediation == "" {
+		return DriftRemediationNone
+	}
+
+	return c.Spec.DriftRemediation
+}
+
+// IsRemediatingDrift returns true if the drift detected on the configuration is waiting to be remediated
+func (c *Configuration) IsRemediatingDrift() bool {
+	return c.Status.Drift != nil && c.Status.Drift.Remediation == RemediationPending
+}
+
+// IsOrphaned returns true if the resources are left in place when the configuration is deleted
+func (c *Configuration) IsOrphaned() bool {
+	return c.GetAnnotations()[OrphanAnnotation] == "true" || c.GetDeletionPolicy() == DeletionPolicyOrphan
+}
+
+// IsDestroyApproved returns true if the terraform destroy has been approved
+func (c *Configuration) IsDestroyApproved() bool {
+	return c.GetAnnotations()[DestroyApprovedAnnotation] == "true"
+}
+
 // HasApproval returns true if the configuration has an approval
 func (c *Configuration) HasApproval() bool {
 	return c.GetAnnotations()[ApplyAnnotation] == "true"
@@ -357,9 +919,23 @@ func (c *Configuration) GetTerraformConfigSecretName() string {
 	return fmt.Sprintf("config-%s", string(c.GetUID()))
 }
 
+// GetWorkspace returns the terraform workspace the configuration is executed within
+func (c *Configuration) GetWorkspace() string {
+	if c.Spec.Workspace == "" {
+		return DefaultWorkspace
+	}
+
+	return c.Spec.Workspace
+}
+
 // GetTerraformStateSecretName returns the name of the secret holding the terraform state
 func (c *Configuration) GetTerraformStateSecretName() string {
-	return fmt.Sprintf("tfstate-default-%s", string(c.GetUID()))
+	return fmt.Sprintf("tfstate-%s-%s", c.GetWorkspace(), string(c.GetUID()))
+}
+
+// GetTerraformPlanSecretName returns the name of the secret holding the saved terraform plan
+func (c *Configuration) GetTerraformPlanSecretName() string {
+	return fmt.Sprintf("tfplan-%s", string(c.GetUID()))
 }
 
 // GetTerraformPolicySecretName returns the name of the secret holding the terraform state
@@ -367,6 +943,16 @@ func (c *Configuration) GetTerraformPolicySecretName() string {
 	return fmt.Sprintf("policy-%s", string(c.GetUID()))
 }
 
+// GetTerraformDriftSecretName returns the name of the secret holding the drift report
+func (c *Configuration) GetTerraformDriftSecretName() string {
+	return fmt.Sprintf("drift-%s", string(c.GetUID()))
+}
+
+// GetTerraformDriftPlanSecretName returns the name of the secret holding the refresh-only plan of the drift check
+func (c *Configuration) GetTerraformDriftPlanSecretName() string {
+	return fmt.Sprintf("tfdrift-%s", string(c.GetUID()))
+}
+
 // GetTerraformCostSecretName returns the name which should be used for the costs report
 func (c *Configuration) GetTerraformCostSecretName() string {
 	return fmt.Sprintf("costs-%s", string(c.GetUID()))
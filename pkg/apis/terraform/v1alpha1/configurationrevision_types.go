@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ConfigurationRevisionKind is the kind for a ConfigurationRevision
+const ConfigurationRevisionKind = "ConfigurationRevision"
+
+// ConfigurationRevisionGVK is the GVK for a ConfigurationRevision
+var ConfigurationRevisionGVK = schema.GroupVersionKind{
+	Group:   GroupVersion.Group,
+	Version: GroupVersion.Version,
+	Kind:    ConfigurationRevisionKind,
+}
+
+// NewConfigurationRevision returns an empty configuration revision
+func NewConfigurationRevision(namespace, name string) *ConfigurationRevision {
+	return &ConfigurationRevision{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       ConfigurationRevisionKind,
+			APIVersion: SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+}
+
+// GetConfigurationRevisionName returns the name of the revision for the configuration generation
+func GetConfigurationRevisionName(name string, generation int64) string {
+	return fmt.Sprintf("%s-%d", name, generation)
+}
+
+// RevisionOutcome is the outcome of a configuration revision
+type RevisionOutcome string
+
+const (
+	// RevisionInProgress indicates the revision is still being planned or applied
+	RevisionInProgress RevisionOutcome = "InProgress"
+	// RevisionActionRequired indicates the revision is waiting on an approval or a change
+	RevisionActionRequired RevisionOutcome = "ActionRequired"
+	// RevisionFailed indicates the revision failed to plan or apply
+	RevisionFailed RevisionOutcome = "Failed"
+	// RevisionSucceeded indicates the revision was successfully applied
+	RevisionSucceeded RevisionOutcome = "Succeeded"
+)
+
+// ConfigurationRevisionSpec is a snapshot of the configuration at a given generation
+type ConfigurationRevisionSpec struct {
+	// Configuration is a copy of the configuration specification at the generation, used to
+	// rollback the configuration to this revision
+	// +kubebuilder:validation:Required
+	Configuration ConfigurationSpec `json:"configuration"`
+	// Generation is the generation of the configuration the revision was recorded for
+	// +kubebuilder:validation:Required
+	Generation int64 `json:"generation"`
+	// Module is the source of the terraform module used by the revision
+	// +kubebuilder:validation:Optional
+	Module string `json:"module,omitempty"`
+	// VariablesHash is a hash of the variables used by the revision
+	// +kubebuilder:validation:Optional
+	VariablesHash string `json:"variablesHash,omitempty"`
+}
+
+// ConfigurationRevisionStatus is the recorded outcome of the revision
+type ConfigurationRevisionStatus struct {
+	// ApprovedBy is the identity who approved the terraform apply, or 'auto' when the configuration
+	// has auto approval enabled
+	// +kubebuilder:validation:Optional
+	ApprovedBy string `json:"approvedBy,omitempty"`
+	// CompletionTime is the time the revision was either applied or failed
+	// +kubebuilder:validation:Optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Costs is the predicted cost of the revision
+	// +kubebuilder:validation:Optional
+	Costs *CostStatus `json:"costs,omitempty"`
+	// Module is the revision the terraform module resolved to, used to rollback the configuration to
+	// the same version of the module
+	// +kubebuilder:validation:Optional
+	Module *ModuleStatus `json:"module,omitempty"`
+	// Outcome is the current outcome of the revision
+	// +kubebuilder:validation:Optional
+	Outcome RevisionOutcome `json:"outcome,omitempty"`
+	// Plan is a summary of the terraform plan for the revision
+	// +kubebuilder:validation:Optional
+	Plan *PlanStatus `json:"plan,omitempty"`
+	// Policy is the result of the security policy checks i.e. Passed or Failed
+	// +kubebuilder:validation:Optional
+	Policy string `json:"policy,omitempty"`
+	// StartTime is the time the controller started to reconcile the revision
+	// +kubebuilder:validation:Optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ConfigurationRevision is a historic record of a generation of a configuration
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=configurationrevisions,categories={terraform}
+// +kubebuilder:printcolumn:name="Configuration",type="string",JSONPath=".metadata.labels.terraform\\.appvia\\.io/configuration"
+// +kubebuilder:printcolumn:name="Generation",type="integer",JSONPath=".spec.generation"
+// +kubebuilder:printcolumn:name="Plan",type="string",JSONPath=".status.plan.summary"
+// +kubebuilder:printcolumn:name="Approved By",type="string",JSONPath=".status.approvedBy"
+// +kubebuilder:printcolumn:name="Outcome",type="string",JSONPath=".status.outcome"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type ConfigurationRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ConfigurationRevisionSpec   `json:"spec,omitempty"`
+	Status ConfigurationRevisionStatus `json:"status,omitempty"`
+}
+
+// IsCompleted returns true if the revision has either been applied or failed
+func (c *ConfigurationRevision) IsCompleted() bool {
+	return c.Status.Outcome == RevisionSucceeded || c.Status.Outcome == RevisionFailed
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ConfigurationRevisionList contains a list of configuration revisions
+type ConfigurationRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ConfigurationRevision `json:"items"`
+}
@@ -27,6 +27,10 @@ import (
 // Constraints defined a collection of constraints which can be applied against
 // the terraform configurations
 type Constraints struct {
+	// ApplyWindow provides the ability to restrict when the selected configurations are permitted
+	// to apply, unless the configuration defines its own apply window
+	// +kubebuilder:validation:Optional
+	ApplyWindow *ApplyWindowConstraint `json:"applyWindow,omitempty"`
 	// Modules provides the ability to control the source for all terraform modules. Allowing
 	// platform teams to control where the modules can be downloaded from.
 	// +kubebuilder:validation:Optional
@@ -36,6 +40,177 @@ type Constraints struct {
 	// labels
 	// +kubebuilder:validation:Optional
 	Checkov *PolicyConstraint `json:"checkov,omitempty"`
+	// Concurrency provides the ability to limit the number of terraform plan and apply jobs
+	// which can run at the same time for the configurations in each of the selected namespaces
+	// +kubebuilder:validation:Optional
+	Concurrency *ConcurrencyConstraint `json:"concurrency,omitempty"`
+	// DestroyProtection provides the ability to require approval when the terraform plan of
+	// the selected configurations destroys or replaces resources, even when the configuration
+	// has auto approval enabled.
+	// +kubebuilder:validation:Optional
+	DestroyProtection *DestroyProtectionConstraint `json:"destroyProtection,omitempty"`
+	// DriftSchedule provides the ability to define how often drift detection is run on the
+	// selected configurations, unless the configuration defines its own schedule
+	// +kubebuilder:validation:Optional
+	DriftSchedule *DriftScheduleConstraint `json:"driftSchedule,omitempty"`
+	// Notifications is a collection of sinks which the lifecycle events of the selected
+	// configurations, i.e. a plan waiting on approval or drift being detected, are sent to
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	Notifications []NotificationConstraint `json:"notifications,omitempty"`
+}
+
+// NotificationEvent is a lifecycle event of a configuration which can be notified
+// +kubebuilder:validation:Enum=ApplyFailed;ApplySucceeded;CostIncreased;DriftDetected;PlanAwaitingApproval;PolicyFailed
+type NotificationEvent string
+
+const (
+	// NotificationApplyFailed is sent when the terraform apply has failed
+	NotificationApplyFailed NotificationEvent = "ApplyFailed"
+	// NotificationApplySucceeded is sent when the terraform apply has completed
+	NotificationApplySucceeded NotificationEvent = "ApplySucceeded"
+	// NotificationCostIncreased is sent when the estimated monthly cost of the configuration has increased
+	NotificationCostIncreased NotificationEvent = "CostIncreased"
+	// NotificationDriftDetected is sent when the drift detection has found changes
+	NotificationDriftDetected NotificationEvent = "DriftDetected"
+	// NotificationPlanAwaitingApproval is sent when the terraform plan is waiting on approval
+	NotificationPlanAwaitingApproval NotificationEvent = "PlanAwaitingApproval"
+	// NotificationPolicyFailed is sent when the security policy checks have failed
+	NotificationPolicyFailed NotificationEvent = "PolicyFailed"
+)
+
+// NotificationFormat is the format of the payload sent to a notification sink
+// +kubebuilder:validation:Enum=cloudevents;json;slack
+type NotificationFormat string
+
+const (
+	// NotificationFormatCloudEvents sends the event as a cloudevent in structured mode
+	NotificationFormatCloudEvents NotificationFormat = "cloudevents"
+	// NotificationFormatJSON sends the event as a generic json document
+	NotificationFormatJSON NotificationFormat = "json"
+	// NotificationFormatSlack sends the event as a slack compatible incoming webhook message
+	NotificationFormatSlack NotificationFormat = "slack"
+)
+
+// NotificationURLSecretKey is the key in the notification secret holding the url of the sink
+const NotificationURLSecretKey = "url"
+
+// NotificationConstraint defines a sink the lifecycle events of the selected configurations are sent to
+type NotificationConstraint struct {
+	// Name is the name of the sink, unique within the policy
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Events is the collection of events sent to the sink, by leaving this field empty all events
+	// are sent
+	// +kubebuilder:validation:Optional
+	Events []NotificationEvent `json:"events,omitempty"`
+	// Format is the format of the payload posted to the sink, either json, slack or cloudevents
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=json
+	Format NotificationFormat `json:"format,omitempty"`
+	// SecretRef is a reference to a secret holding the url of the sink under the url key, this
+	// should be used when the url holds a token, i.e. a slack webhook. When the namespace is not
+	// defined the secret is taken from the controller namespace
+	// +kubebuilder:validation:Optional
+	SecretRef *v1.SecretReference `json:"secretRef,omitempty"`
+	// Selector is the selector on the namespace or labels on the configuration. By leaving this
+	// fields empty you can implicitly selecting all configurations.
+	// +kubebuilder:validation:Optional
+	Selector *Selector `json:"selector,omitempty"`
+	// URL is the http endpoint the events are posted to
+	// +kubebuilder:validation:Optional
+	URL string `json:"url,omitempty"`
+}
+
+// HasEvent returns true if the event should be sent to the sink
+func (n *NotificationConstraint) HasEvent(event NotificationEvent) bool {
+	if len(n.Events) == 0 {
+		return true
+	}
+	for _, x := range n.Events {
+		if x == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetFormat returns the format of the payload, defaulting to json
+func (n *NotificationConstraint) GetFormat() NotificationFormat {
+	if n.Format == "" {
+		return NotificationFormatJSON
+	}
+
+	return n.Format
+}
+
+// ApplyWindowConstraint defines the default apply window for the selected configurations
+type ApplyWindowConstraint struct {
+	ApplyWindow `json:",inline"`
+	// Selector is the selector on the namespace or labels on the configuration. By leaving this
+	// fields empty you can implicitly selecting all configurations.
+	// +kubebuilder:validation:Optional
+	Selector *Selector `json:"selector,omitempty"`
+}
+
+// GetSelector returns the selector of the constraint
+func (a *ApplyWindowConstraint) GetSelector() *Selector {
+	return a.Selector
+}
+
+// ConcurrencyConstraint defines the maximum number of terraform jobs running per namespace
+type ConcurrencyConstraint struct {
+	// MaxJobs is the maximum number of terraform plan and apply jobs which can be running at
+	// any one time for the configurations within a namespace, further configurations are queued
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	MaxJobs int `json:"maxJobs"`
+	// Selector is the selector on the namespace or labels on the configuration. By leaving this
+	// fields empty you can implicitly selecting all configurations.
+	// +kubebuilder:validation:Optional
+	Selector *Selector `json:"selector,omitempty"`
+}
+
+// GetSelector returns the selector of the constraint
+func (c *ConcurrencyConstraint) GetSelector() *Selector {
+	return c.Selector
+}
+
+// DriftScheduleConstraint defines the default drift schedule for the selected configurations
+type DriftScheduleConstraint struct {
+	DriftSchedule `json:",inline"`
+	// Selector is the selector on the namespace or labels on the configuration. By leaving this
+	// fields empty you can implicitly selecting all configurations.
+	// +kubebuilder:validation:Optional
+	Selector *Selector `json:"selector,omitempty"`
+}
+
+// GetSelector returns the selector of the constraint
+func (d *DriftScheduleConstraint) GetSelector() *Selector {
+	return d.Selector
+}
+
+// DestroyProtectionConstraint defines the resources which are protected from being destroyed
+// or replaced without approval
+type DestroyProtectionConstraint struct {
+	// AllResources indicates any resource being destroyed or replaced requires approval
+	// +kubebuilder:validation:Optional
+	AllResources bool `json:"allResources,omitempty"`
+	// ResourceTypes is a collection of terraform resource types i.e. aws_db_instance, which
+	// require approval before being destroyed or replaced
+	// +kubebuilder:validation:Optional
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+	// Selector is the selector on the namespace or labels on the configuration. By leaving this
+	// fields empty you can implicitly selecting all configurations.
+	// +kubebuilder:validation:Optional
+	Selector *Selector `json:"selector,omitempty"`
+}
+
+// GetSelector returns the selector of the constraint
+func (d *DestroyProtectionConstraint) GetSelector() *Selector {
+	return d.Selector
 }
 
 // ModuleConstraint provides a collection of constraints on modules
@@ -101,6 +101,61 @@ func SupportedProviderTypeList() []string {
 	return list
 }
 
+// BackendType is the type of terraform state backend
+type BackendType string
+
+const (
+	// AzureRMBackendType is the azure storage account backend type
+	AzureRMBackendType BackendType = "azurerm"
+	// GCSBackendType is the google cloud storage backend type
+	GCSBackendType BackendType = "gcs"
+	// HTTPBackendType is the http backend type
+	HTTPBackendType BackendType = "http"
+	// KubernetesBackendType is the in-cluster kubernetes secret backend type
+	KubernetesBackendType BackendType = "kubernetes"
+	// S3BackendType is the s3 compatible storage backend type
+	S3BackendType BackendType = "s3"
+)
+
+// SupportedBackendTypes returns the supported backend types
+var SupportedBackendTypes = []BackendType{
+	AzureRMBackendType,
+	GCSBackendType,
+	HTTPBackendType,
+	KubernetesBackendType,
+	S3BackendType,
+}
+
+// IsSupportedBackendType returns true if the backend type is supported
+func IsSupportedBackendType(backendType BackendType) bool {
+	for _, x := range SupportedBackendTypes {
+		if x == backendType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Backend defines where the terraform state is kept for the configurations
+type Backend struct {
+	// Configuration is the terraform backend configuration i.e. bucket, region or endpoint. Note the
+	// location of the state within the backend i.e. key or prefix is generated per configuration.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Configuration *runtime.RawExtension `json:"configuration,omitempty"`
+	// SecretRef is a reference to a secret in the controller namespace containing the environment
+	// variables used to authenticate to the backend i.e. AWS_ACCESS_KEY_ID or ARM_ACCESS_KEY. These
+	// are used by the terraform jobs and by the controller when reading the state.
+	// +kubebuilder:validation:Optional
+	SecretRef *v1.SecretReference `json:"secretRef,omitempty"`
+	// Type is the type of terraform backend, currently supported backends are kubernetes, s3, gcs,
+	// azurerm and http
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=azurerm;gcs;http;kubernetes;s3
+	Type BackendType `json:"type"`
+}
+
 // SourceType is the type of source
 type SourceType string
 
@@ -114,6 +169,10 @@ const (
 // ProviderSpec defines the desired state of a provider
 // +k8s:openapi-gen=true
 type ProviderSpec struct {
+	// Backend provides the ability to store the terraform state of configurations using this provider
+	// in an alternative backend. When not defined the controller default is used.
+	// +kubebuilder:validation:Optional
+	Backend *Backend `json:"backend,omitempty"`
 	// Configuration is optional configuration to the provider. This is terraform provider specific.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -60,6 +60,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&Configuration{},
 		&ConfigurationList{},
+		&ConfigurationRevision{},
+		&ConfigurationRevisionList{},
 		&Policy{},
 		&PolicyList{},
 		&Provider{},
@@ -49,6 +49,10 @@ spec:
                 path: backend.tf
               - key: provider.tf
                 path: provider.tf
+              {{- if .EnableImports }}
+              - key: imports.tf
+                path: imports.tf
+              {{- end }}
               {{- if .EnableVariables }}
               - key: variables.tfvars.json
                 path: variables.tfvars.json
@@ -139,15 +143,46 @@ spec:
           - /run/bin/step
         args:
           - --comment=Executing Terraform
+          {{- if ne .Configuration.Workspace "default" }}
+          - --command=/bin/terraform workspace select {{ .Configuration.Workspace }} || /bin/terraform workspace new {{ .Configuration.Workspace }}
+          {{- end }}
           {{- if eq .Stage "plan" }}
-          - --command=/bin/terraform plan {{ .TerraformArguments }} -out=/run/plan.out -lock=false
+          - --command=/bin/terraform plan {{ .TerraformArguments }} -out=/run/plan.out {{ .LockArguments }}
           - --command=/bin/terraform show -json /run/plan.out > /run/plan.json
+          - --command=/bin/gzip -c /run/plan.out > /run/plan.out.gz
+          - --command=/bin/gzip -c /run/plan.json > /run/plan.json.gz
+          - --command=/run/bin/kubectl -n $(KUBE_NAMESPACE) delete secret $(TERRAFORM_PLAN_NAME) --ignore-not-found >/dev/null
+          - --command=/run/bin/kubectl -n $(KUBE_NAMESPACE) create secret generic $(TERRAFORM_PLAN_NAME) --from-file=plan.out=/run/plan.out.gz --from-file=plan.json=/run/plan.json.gz >/dev/null
+          - --command=/run/bin/kubectl -n $(KUBE_NAMESPACE) label secret $(TERRAFORM_PLAN_NAME) terraform.appvia.io/configuration-uid={{ .Configuration.UUID }} terraform.appvia.io/generation={{ .Configuration.Generation }} >/dev/null
           {{- end }}
           {{- if eq .Stage "apply" }}
-          - --command=/bin/terraform apply {{ .TerraformArguments }} -auto-approve -lock=false
+          - --command=/run/bin/kubectl -n $(KUBE_NAMESPACE) get secret $(TERRAFORM_PLAN_NAME) -o jsonpath='{.data.plan\.out}' | /bin/base64 -d | /bin/gzip -d > /run/plan.out
+          - --command=/bin/terraform apply {{ .TerraformArguments }} -auto-approve {{ .LockArguments }} /run/plan.out
           {{- end }}
           {{- if eq .Stage "destroy" }}
-          - --command=/bin/terraform destroy {{ .TerraformArguments }} -auto-approve
+          - --command=/bin/terraform destroy {{ .TerraformArguments }} -auto-approve {{ .LockArguments }}
+          {{- end }}
+          {{- if eq .Stage "destroy-plan" }}
+          - --command=/bin/terraform plan -destroy {{ .TerraformArguments }} -out=/run/plan.out {{ .LockArguments }}
+          - --command=/bin/terraform show -json /run/plan.out > /run/plan.json
+          - --command=/bin/gzip -c /run/plan.json > /run/plan.json.gz
+          - --command=/run/bin/kubectl -n $(KUBE_NAMESPACE) delete secret $(TERRAFORM_PLAN_NAME) --ignore-not-found >/dev/null
+          - --command=/run/bin/kubectl -n $(KUBE_NAMESPACE) create secret generic $(TERRAFORM_PLAN_NAME) --from-file=plan.json=/run/plan.json.gz >/dev/null
+          - --command=/run/bin/kubectl -n $(KUBE_NAMESPACE) label secret $(TERRAFORM_PLAN_NAME) terraform.appvia.io/configuration-uid={{ .Configuration.UUID }} terraform.appvia.io/generation={{ .Configuration.Generation }} terraform.appvia.io/stage={{ .Stage }} >/dev/null
+          {{- end }}
+          {{- if eq .Stage "drift" }}
+          - --command=/bin/terraform plan -refresh-only -detailed-exitcode {{ .TerraformArguments }} -out=/run/drift.out {{ .LockArguments }}; echo $? > /run/drift.exitcode
+          - --command=/bin/grep -qv '^1$' /run/drift.exitcode
+          - --command=/bin/terraform show -json /run/drift.out > /run/drift.json
+          - --command=if /bin/grep -q '^2$' /run/drift.exitcode; then /bin/terraform plan {{ .TerraformArguments }} -out=/run/config.out {{ .LockArguments }} && /bin/terraform show -json /run/config.out > /run/config.json; else echo '{}' > /run/config.json; fi
+          - --command=/bin/gzip -c /run/drift.json > /run/drift.json.gz
+          - --command=/bin/gzip -c /run/config.json > /run/config.json.gz
+          - --command=/run/bin/kubectl -n $(KUBE_NAMESPACE) delete secret $(TERRAFORM_DRIFT_NAME) --ignore-not-found >/dev/null
+          - --command=/run/bin/kubectl -n $(KUBE_NAMESPACE) create secret generic $(TERRAFORM_DRIFT_NAME) --from-file=plan.json=/run/drift.json.gz --from-file=config.json=/run/config.json.gz --from-file=exitcode=/run/drift.exitcode >/dev/null
+          - --command=/run/bin/kubectl -n $(KUBE_NAMESPACE) label secret $(TERRAFORM_DRIFT_NAME) terraform.appvia.io/configuration-uid={{ .Configuration.UUID }} terraform.appvia.io/generation={{ .Configuration.Generation }} terraform.appvia.io/stage={{ .Stage }} terraform.appvia.io/drift={{ index .Labels "terraform.appvia.io/drift" }} >/dev/null
+          {{- end }}
+          {{- if eq .Stage "unlock" }}
+          - --command=/bin/terraform force-unlock -force {{ .LockID }}
           {{- end }}
           - --on-error=/run/steps/terraform.failed
           - --on-success=/run/steps/terraform.complete
@@ -162,11 +197,19 @@ spec:
             valueFrom:
               fieldRef:
                 fieldPath: metadata.namespace
+          - name: TERRAFORM_DRIFT_NAME
+            value: {{ .Secrets.TerraformDrift }}
+          - name: TERRAFORM_PLAN_NAME
+            value: {{ .Secrets.TerraformPlan }}
         envFrom:
         {{- if eq .Provider.Source "secret" }}
           - secretRef:
               name: {{ .Provider.SecretRef.Name }}
         {{- end }}
+        {{- if .Secrets.Backend }}
+          - secretRef:
+              name: {{ .Secrets.Backend }}
+        {{- end }}
         {{- range .ExecutorSecrets }}
           - secretRef:
               name: {{ . }}
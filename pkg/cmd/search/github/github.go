@@ -107,6 +107,21 @@ func (r *ghClient) ResolveSource(ctx context.Context, module search.Module) (str
 	return fmt.Sprintf("%s?ref=%s", source, module.Version), nil
 }
 
+// Revision returns the commit sha the version (a tag or branch) of the module points to
+func (r *ghClient) Revision(ctx context.Context, module search.Module) (string, error) {
+	ref := module.Version
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	sha, _, err := r.gc.Repositories.GetCommitSHA1(ctx, module.Namespace, module.Name, ref, "")
+	if err != nil {
+		return "", err
+	}
+
+	return sha, nil
+}
+
 // Find returns git repositories that match the given search term
 func (r *ghClient) Find(ctx context.Context, query search.Query) ([]search.Module, error) {
 	var modules []search.Module
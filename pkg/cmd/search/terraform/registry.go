@@ -29,6 +29,7 @@ import (
 	"strings"
 
 	"github.com/appvia/terraform-controller/pkg/cmd/search"
+	"github.com/appvia/terraform-controller/pkg/utils"
 	"github.com/appvia/terraform-controller/pkg/version"
 )
 
@@ -160,6 +161,20 @@ func (r *registry) ResolveSource(ctx context.Context, module search.Module) (str
 	return strings.Replace(source, "git::", "", -1), nil
 }
 
+// Revision returns the version of the module, registry versions are immutable so we only need
+// to check the version has been published
+func (r *registry) Revision(ctx context.Context, module search.Module) (string, error) {
+	versions, err := r.Versions(ctx, module)
+	if err != nil {
+		return "", err
+	}
+	if !utils.Contains(module.Version, versions) {
+		return "", fmt.Errorf("version %q of module %s/%s/%s not found", module.Version, module.Namespace, module.Name, module.Provider)
+	}
+
+	return module.Version, nil
+}
+
 // Find returns the terraform registry lookup provider
 func (r *registry) Find(ctx context.Context, query search.Query) ([]search.Module, error) {
 	var list []search.Module
@@ -79,6 +79,9 @@ type Interface interface {
 	// ResolveSource returns the source for a module - this is only a requirement for
 	// terraform registries as they don't show the tag on the module versions
 	ResolveSource(ctx context.Context, module Module) (string, error)
+	// Revision returns the immutable revision of the module version, i.e. the commit a git
+	// tag or branch currently points to, or the version itself for a registry
+	Revision(ctx context.Context, module Module) (string, error)
 	// Source returns the source for the search service
 	Source() string
 	// Versions returns a list of versions for a module
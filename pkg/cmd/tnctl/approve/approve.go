@@ -34,6 +34,8 @@ import (
 // Command represents the available get command options
 type Command struct {
 	cmd.Factory
+	// Destroy indicates we are approving the destroy of the configuration
+	Destroy bool
 	// Names is the name of the resource we are describing
 	Names []string
 	// Namespace is the namespace of the resource
@@ -48,6 +50,11 @@ from 'false' to 'true'.
 
 Approve one or more configurations
 $ tnctl approve NAME
+
+Approve the destroy of a configuration being deleted with the
+RequireApproval deletion policy, this sets the
+terraform.appvia.io/destroy-approved annotation
+$ tnctl approve NAME --destroy
 `
 
 // NewCommand returns a new instance of the get command
@@ -68,6 +75,7 @@ func NewCommand(factory cmd.Factory) *cobra.Command {
 	}
 
 	flags := c.Flags()
+	flags.BoolVar(&options.Destroy, "destroy", false, "Approve the terraform destroy of a configuration being deleted")
 	flags.StringVarP(&options.Namespace, "namespace", "n", "default", "Namespace of the resource/s")
 
 	cmd.RegisterFlagCompletionFunc(c, "namespace", cmd.AutoCompleteNamespaces(factory))
@@ -105,6 +113,14 @@ func (o *Command) Run(ctx context.Context) error {
 
 		original := configuration.DeepCopy()
 
+		if o.Destroy {
+			if err := o.approveDestroy(ctx, cc, configuration); err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		// @step: update the configuration if required
 		switch {
 		case configuration.Annotations == nil:
@@ -124,3 +140,26 @@ func (o *Command) Run(ctx context.Context) error {
 
 	return nil
 }
+
+// approveDestroy is called to approve the terraform destroy of a configuration being deleted
+func (o *Command) approveDestroy(ctx context.Context, cc client.Client, configuration *terraformv1alphav1.Configuration) error {
+	switch {
+	case configuration.DeletionTimestamp == nil:
+		return fmt.Errorf("configuration %s is not being deleted", configuration.Name)
+	case configuration.IsDestroyApproved():
+		return nil
+	}
+	original := configuration.DeepCopy()
+
+	if configuration.Annotations == nil {
+		configuration.Annotations = map[string]string{}
+	}
+	configuration.Annotations[terraformv1alphav1.DestroyApprovedAnnotation] = "true"
+
+	if err := cc.Patch(ctx, configuration, client.MergeFrom(original)); err != nil {
+		return err
+	}
+	o.Println("%s Configuration %s destroy has been approved", cmd.IconGood, configuration.Name)
+
+	return nil
+}
@@ -22,10 +22,12 @@ import (
 	"context"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -152,5 +154,48 @@ var _ = Describe("Approve Command", func() {
 				Expect(stdout.String()).To(ContainSubstring("Configuration test has been approved\n"))
 			})
 		})
+
+		When("approving the destroy of a configuration not being deleted", func() {
+			BeforeEach(func() {
+				command.Destroy = true
+				Expect(cc.Create(context.Background(), configuration)).To(Succeed())
+				err = command.Run(context.Background())
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("configuration test is not being deleted"))
+			})
+
+			It("should not have approved the destroy", func() {
+				Expect(cc.Get(context.Background(), configuration.GetNamespacedName(), configuration)).To(Succeed())
+				Expect(configuration.Annotations).ToNot(HaveKey(terraformv1alphav1.DestroyApprovedAnnotation))
+			})
+		})
+
+		When("approving the destroy of a configuration being deleted", func() {
+			BeforeEach(func() {
+				command.Destroy = true
+				now := metav1.NewTime(time.Now())
+				configuration.DeletionTimestamp = &now
+				configuration.Finalizers = []string{"do-not-delete"}
+				Expect(cc.Create(context.Background(), configuration)).To(Succeed())
+				err = command.Run(context.Background())
+			})
+
+			It("should not error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should have approved the destroy", func() {
+				Expect(cc.Get(context.Background(), configuration.GetNamespacedName(), configuration)).To(Succeed())
+				Expect(configuration.Annotations).To(HaveKeyWithValue(terraformv1alphav1.DestroyApprovedAnnotation, "true"))
+				Expect(configuration.Annotations).To(HaveKeyWithValue(terraformv1alphav1.ApplyAnnotation, "false"))
+			})
+
+			It("should indicate the destroy approval", func() {
+				Expect(stdout.String()).To(ContainSubstring("Configuration test destroy has been approved\n"))
+			})
+		})
 	})
 })
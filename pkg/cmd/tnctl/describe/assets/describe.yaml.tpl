@@ -1,7 +1,13 @@
 Name:         {{ .Object.metadata.name }}
 Namespace:    {{ .Object.metadata.namespace }}
 Created:      {{ .Object.metadata.creationTimestamp }}
+{{- if .Object.status.expiresAt }}
+Expires:      {{ .Object.status.expiresAt }}
+{{- end }}
 Status:       {{ default "Unknown" .Object.status.resourceStatus }}
+{{- if .Object.status.nextDriftCheck }}
+Drift Check:  {{ .Object.status.nextDriftCheck }}
+{{- end }}
 {{- if .Object.metadata.annotations }}
 Annotations:
 {{- range $key, $value := .Object.metadata.annotations }}
@@ -86,3 +92,25 @@ Hourly  Total: ${{ .Cost.breakdown.totalHourlyCost | substr 0 5 }}
 
 {{- end }}
 {{- end }}
+
+{{- if .Object.status.drift }}
+
+Drift:
+=====
+Summary:        {{ default "-" .Object.status.drift.summary }}
+Checked:        {{ default "-" .Object.status.drift.checkedAt }}
+Report:         {{ .Object.metadata.namespace }}/{{ default "-" .Object.status.drift.reportSecret }}
+{{- range $resource := .Object.status.drift.resources }}
+{{ printf "%-14s %-8s %s" $resource.kind $resource.action $resource.address }}
+{{- end }}
+{{- end }}
+
+{{- if .Revisions }}
+
+Revisions:
+=========
+{{ printf "%-12s %-16s %-20s %-32s %-10s %s" "Generation" "Outcome" "Approved By" "Plan" "Policy" "Completed" }}
+{{- range $revision := .Revisions }}
+{{ printf "%-12v %-16s %-20s %-32s %-10s %s" $revision.spec.generation (default "Unknown" $revision.status.outcome) (default "-" $revision.status.approvedBy) (default "-" (($revision.status.plan | default dict).summary)) (default "-" $revision.status.policy) (default "-" $revision.status.completionTime) }}
+{{- end }}
+{{- end }}
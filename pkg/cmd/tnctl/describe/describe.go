@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -155,6 +156,38 @@ func (o *Command) Run(ctx context.Context) error {
 		return nil, false
 	}
 
+	// @step: retrieve the revision history of the configurations
+	revisions := &unstructured.UnstructuredList{}
+	revisions.SetGroupVersionKind(terraformv1alphav1.ConfigurationRevisionGVK)
+	_ = cc.List(context.Background(), revisions,
+		client.HasLabels([]string{terraformv1alphav1.ConfigurationNameLabel}),
+		client.InNamespace(o.Namespace),
+	)
+
+	findRevisions := func(resource client.Object) []map[string]interface{} {
+		var list []unstructured.Unstructured
+		for _, x := range revisions.Items {
+			if x.GetLabels()[terraformv1alphav1.ConfigurationUIDLabel] == string(resource.GetUID()) {
+				list = append(list, x)
+			}
+		}
+		generation := func(u unstructured.Unstructured) int64 {
+			v, _, _ := unstructured.NestedInt64(u.Object, "spec", "generation")
+
+			return v
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return generation(list[i]) > generation(list[j])
+		})
+
+		var items []map[string]interface{}
+		for _, x := range list {
+			items = append(items, x.Object)
+		}
+
+		return items
+	}
+
 	findPolicyReport := func(resource client.Object) (map[string]interface{}, bool) {
 		name := fmt.Sprintf("policy-%v", resource.GetUID())
 		key := "results_json.json"
@@ -185,6 +218,7 @@ func (o *Command) Run(ctx context.Context) error {
 			"Name":               resource.GetName(),
 			"Namespace":          resource.GetNamespace(),
 			"Object":             resource.Object,
+			"Revisions":          findRevisions(&resource),
 		}
 
 		// @step: check if the configuration has a policy report
@@ -78,6 +78,7 @@ func NewCommand(factory cmd.Factory) *cobra.Command {
 	cmd.RegisterFlagCompletionFunc(c, "stage", cmd.AutoCompleteWithList([]string{
 		terraformv1alphav1.StageTerraformPlan,
 		terraformv1alphav1.StageTerraformApply,
+		terraformv1alphav1.StageTerraformDrift,
 	}))
 
 	return c
@@ -93,8 +94,9 @@ func (o *Command) Run(ctx context.Context) error {
 	case o.Stage != "" && !utils.Contains(o.Stage, []string{
 		terraformv1alphav1.StageTerraformPlan,
 		terraformv1alphav1.StageTerraformApply,
+		terraformv1alphav1.StageTerraformDrift,
 	}):
-		return errors.New("invalid stage (must be one of: plan, apply)")
+		return errors.New("invalid stage (must be one of: plan, apply, drift)")
 	}
 
 	cc, err := o.GetClient()
@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/cmd"
+	"github.com/appvia/terraform-controller/pkg/utils/kubernetes"
+)
+
+// Command represents the available rollback command options
+type Command struct {
+	cmd.Factory
+	// Name is the name of the configuration
+	Name string
+	// Namespace is the namespace of the resource
+	Namespace string
+	// Revision is the generation of the configuration to rollback to
+	Revision int64
+}
+
+var longDescription = `
+Used to rollback a terraform configuration to a previous revision.
+The specification recorded by the revision is re-applied to the
+configuration, producing a new generation which is planned and
+applied as normal. The module is pinned to the revision it resolved
+to when the revision was recorded.
+
+Rollback to the last successfully applied revision
+$ tnctl rollback NAME
+
+Rollback to a specific revision
+$ tnctl rollback NAME --revision 3
+`
+
+// NewCommand returns a new instance of the rollback command
+func NewCommand(factory cmd.Factory) *cobra.Command {
+	options := &Command{Factory: factory}
+
+	c := &cobra.Command{
+		Use:   "rollback NAME",
+		Short: "Rolls back a terraform configuration to a previous revision",
+		Args:  cobra.ExactArgs(1),
+		Long:  strings.TrimPrefix(longDescription, "\n"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.Name = args[0]
+
+			return options.Run(cmd.Context())
+		},
+		ValidArgsFunction: cmd.AutoCompleteConfigurations(options.Factory),
+	}
+
+	flags := c.Flags()
+	flags.StringVarP(&options.Namespace, "namespace", "n", "default", "Namespace of the resource")
+	flags.Int64Var(&options.Revision, "revision", 0, "The revision (generation) to rollback to, defaults to the last successful revision")
+
+	cmd.RegisterFlagCompletionFunc(c, "namespace", cmd.AutoCompleteNamespaces(factory))
+
+	return c
+}
+
+// Run is called to execute the rollback command
+func (o *Command) Run(ctx context.Context) error {
+	switch {
+	case o.Namespace == "":
+		return errors.New("namespace is required")
+	case o.Name == "":
+		return errors.New("name is required")
+	case o.Revision < 0:
+		return errors.New("revision must be a positive number")
+	}
+
+	cc, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	configuration := &terraformv1alphav1.Configuration{}
+	configuration.Namespace = o.Namespace
+	configuration.Name = o.Name
+
+	found, err := kubernetes.GetIfExists(ctx, cc, configuration)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("configuration %s not found", o.Name)
+	}
+
+	revision, err := o.findRevision(ctx, cc, configuration)
+	if err != nil {
+		return err
+	}
+	if revision.Spec.Generation == configuration.GetGeneration() {
+		return fmt.Errorf("configuration %s is already at revision %d", o.Name, revision.Spec.Generation)
+	}
+
+	original := configuration.DeepCopy()
+	configuration.Spec = *revision.Spec.Configuration.DeepCopy()
+	// @note: the module is pinned to the revision it resolved to, as a reference such as a branch or
+	// version constraint may resolve to something else today
+	if revision.Status.Module != nil && revision.Status.Module.Source != "" {
+		configuration.Spec.Module = revision.Status.Module.Source
+	}
+
+	if err := cc.Patch(ctx, configuration, client.MergeFrom(original)); err != nil {
+		return err
+	}
+	o.Println("%s Configuration %s has been rolled back to revision %d", cmd.IconGood, o.Name, revision.Spec.Generation)
+
+	return nil
+}
+
+// findRevision returns the requested revision, or the last successful revision prior to the
+// current generation when no revision was requested
+func (o *Command) findRevision(ctx context.Context, cc client.Client, configuration *terraformv1alphav1.Configuration) (*terraformv1alphav1.ConfigurationRevision, error) {
+	if o.Revision > 0 {
+		revision := terraformv1alphav1.NewConfigurationRevision(configuration.Namespace,
+			terraformv1alphav1.GetConfigurationRevisionName(configuration.Name, o.Revision),
+		)
+
+		found, err := kubernetes.GetIfExists(ctx, cc, revision)
+		if err != nil {
+			return nil, err
+		}
+		if !found || revision.GetLabels()[terraformv1alphav1.ConfigurationUIDLabel] != string(configuration.GetUID()) {
+			return nil, fmt.Errorf("revision %d of configuration %s not found", o.Revision, configuration.Name)
+		}
+
+		return revision, nil
+	}
+
+	list := &terraformv1alphav1.ConfigurationRevisionList{}
+	if err := cc.List(ctx, list,
+		client.InNamespace(configuration.Namespace),
+		client.MatchingLabels{terraformv1alphav1.ConfigurationUIDLabel: string(configuration.GetUID())},
+	); err != nil {
+		return nil, err
+	}
+
+	var revision *terraformv1alphav1.ConfigurationRevision
+	for i, x := range list.Items {
+		switch {
+		case x.Spec.Generation >= configuration.GetGeneration():
+			continue
+		case x.Status.Outcome != terraformv1alphav1.RevisionSucceeded:
+			continue
+		case revision == nil, x.Spec.Generation > revision.Spec.Generation:
+			revision = &list.Items[i]
+		}
+	}
+	if revision == nil {
+		return nil, fmt.Errorf("no previous successful revision found for configuration %s", configuration.Name)
+	}
+
+	return revision, nil
+}
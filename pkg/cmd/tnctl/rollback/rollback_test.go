@@ -0,0 +1,203 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rollback
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/cmd"
+	"github.com/appvia/terraform-controller/pkg/schema"
+	"github.com/appvia/terraform-controller/test/fixtures"
+)
+
+func TestReconcile(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Running Test Suite")
+}
+
+var _ = Describe("Rollback Command", func() {
+	logrus.SetOutput(ioutil.Discard)
+
+	var cc client.Client
+	var factory cmd.Factory
+	var streams genericclioptions.IOStreams
+	var stdout *bytes.Buffer
+	var command *Command
+	var configuration *terraformv1alphav1.Configuration
+	var err error
+
+	newRevision := func(generation int64, module string, outcome terraformv1alphav1.RevisionOutcome) *terraformv1alphav1.ConfigurationRevision {
+		revision := terraformv1alphav1.NewConfigurationRevision("default", terraformv1alphav1.GetConfigurationRevisionName("test", generation))
+		revision.Labels = map[string]string{terraformv1alphav1.ConfigurationUIDLabel: string(configuration.GetUID())}
+		revision.Spec.Generation = generation
+		revision.Spec.Configuration = *configuration.Spec.DeepCopy()
+		revision.Spec.Configuration.Module = module
+		revision.Status.Outcome = outcome
+
+		return revision
+	}
+
+	BeforeEach(func() {
+		cc = fake.NewFakeClientWithScheme(schema.GetScheme())
+		streams, _, stdout, _ = genericclioptions.NewTestIOStreams()
+		factory, _ = cmd.NewFactoryWithClient(cc, streams)
+		command = &Command{Factory: factory}
+		command.Name = "test"
+		command.Namespace = "default"
+
+		configuration = fixtures.NewValidBucketConfiguration("default", "test")
+		configuration.Generation = 3
+		configuration.Spec.Module = "module.v3"
+	})
+
+	When("the command is created", func() {
+		It("should create a new command", func() {
+			Expect(NewCommand(factory)).ToNot(BeNil())
+		})
+	})
+
+	When("name is not provided", func() {
+		BeforeEach(func() {
+			command.Name = ""
+			err = command.Run(context.Background())
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("name is required"))
+		})
+	})
+
+	When("configuration does not exist", func() {
+		BeforeEach(func() {
+			err = command.Run(context.Background())
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("configuration test not found"))
+		})
+	})
+
+	When("the configuration has no previous successful revisions", func() {
+		BeforeEach(func() {
+			Expect(cc.Create(context.Background(), configuration)).To(Succeed())
+			Expect(cc.Create(context.Background(), newRevision(2, "module.v2", terraformv1alphav1.RevisionFailed))).To(Succeed())
+
+			err = command.Run(context.Background())
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("no previous successful revision found for configuration test"))
+		})
+	})
+
+	When("the requested revision does not exist", func() {
+		BeforeEach(func() {
+			Expect(cc.Create(context.Background(), configuration)).To(Succeed())
+			command.Revision = 1
+
+			err = command.Run(context.Background())
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("revision 1 of configuration test not found"))
+		})
+	})
+
+	When("the configuration has previous revisions", func() {
+		BeforeEach(func() {
+			Expect(cc.Create(context.Background(), configuration)).To(Succeed())
+			Expect(cc.Create(context.Background(), newRevision(1, "module.v1", terraformv1alphav1.RevisionSucceeded))).To(Succeed())
+			Expect(cc.Create(context.Background(), newRevision(2, "module.v2", terraformv1alphav1.RevisionSucceeded))).To(Succeed())
+			Expect(cc.Create(context.Background(), newRevision(3, "module.v3", terraformv1alphav1.RevisionFailed))).To(Succeed())
+		})
+
+		When("no revision is requested", func() {
+			BeforeEach(func() {
+				err = command.Run(context.Background())
+			})
+
+			It("should not error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should have rolled back to the last successful revision", func() {
+				Expect(cc.Get(context.Background(), configuration.GetNamespacedName(), configuration)).To(Succeed())
+				Expect(configuration.Spec.Module).To(Equal("module.v2"))
+				Expect(stdout.String()).To(ContainSubstring("Configuration test has been rolled back to revision 2\n"))
+			})
+		})
+
+		When("a specific revision is requested", func() {
+			BeforeEach(func() {
+				command.Revision = 1
+				err = command.Run(context.Background())
+			})
+
+			It("should have rolled back to the revision", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cc.Get(context.Background(), configuration.GetNamespacedName(), configuration)).To(Succeed())
+				Expect(configuration.Spec.Module).To(Equal("module.v1"))
+			})
+		})
+
+		When("the revision recorded the resolved module", func() {
+			BeforeEach(func() {
+				revision := terraformv1alphav1.NewConfigurationRevision("default", terraformv1alphav1.GetConfigurationRevisionName("test", 1))
+				Expect(cc.Get(context.Background(), client.ObjectKeyFromObject(revision), revision)).To(Succeed())
+				revision.Status.Module = &terraformv1alphav1.ModuleStatus{Revision: "abc123", Source: "module.v1?ref=abc123"}
+				Expect(cc.Update(context.Background(), revision)).To(Succeed())
+
+				command.Revision = 1
+				err = command.Run(context.Background())
+			})
+
+			It("should have pinned the module to the resolved revision", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cc.Get(context.Background(), configuration.GetNamespacedName(), configuration)).To(Succeed())
+				Expect(configuration.Spec.Module).To(Equal("module.v1?ref=abc123"))
+			})
+		})
+
+		When("the current revision is requested", func() {
+			BeforeEach(func() {
+				command.Revision = 3
+				err = command.Run(context.Background())
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("configuration test is already at revision 3"))
+			})
+		})
+	})
+})
@@ -34,7 +34,10 @@ import (
 	"github.com/appvia/terraform-controller/pkg/cmd/tnctl/describe"
 	"github.com/appvia/terraform-controller/pkg/cmd/tnctl/generate"
 	"github.com/appvia/terraform-controller/pkg/cmd/tnctl/logs"
+	"github.com/appvia/terraform-controller/pkg/cmd/tnctl/retry"
+	"github.com/appvia/terraform-controller/pkg/cmd/tnctl/rollback"
 	"github.com/appvia/terraform-controller/pkg/cmd/tnctl/search"
+	"github.com/appvia/terraform-controller/pkg/cmd/tnctl/unlock"
 	"github.com/appvia/terraform-controller/pkg/cmd/tnctl/workflow"
 	"github.com/appvia/terraform-controller/pkg/version"
 )
@@ -77,6 +80,9 @@ func New(factory cmd.Factory) *cobra.Command {
 		describe.NewCommand(factory),
 		generate.NewCommand(factory),
 		logs.NewCommand(factory),
+		retry.NewCommand(factory),
+		unlock.NewCommand(factory),
+		rollback.NewCommand(factory),
 	)
 
 	flags := command.PersistentFlags()
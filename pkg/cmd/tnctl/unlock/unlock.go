@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package unlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/cmd"
+	"github.com/appvia/terraform-controller/pkg/utils/kubernetes"
+	"github.com/appvia/terraform-controller/pkg/utils/terraform"
+)
+
+// Command represents the available unlock command options
+type Command struct {
+	cmd.Factory
+	// Name is the name of the resource we are unlocking
+	Name string
+	// Namespace is the namespace of the resource
+	Namespace string
+	// LockID is the identifier of the state lock to release, defaults to the lock in the status
+	LockID string
+}
+
+var longDescription = `
+Used to force release a stale terraform state lock held against
+a configuration, i.e. a lock left behind by an interrupted run.
+The controller releases the lock, records an event against the
+configuration and runs the terraform plan again.
+
+Release the state lock reported on the configuration status
+$ tnctl unlock NAME
+
+Release a specific state lock
+$ tnctl unlock NAME --lock-id ID
+`
+
+// NewCommand returns a new instance of the unlock command
+func NewCommand(factory cmd.Factory) *cobra.Command {
+	options := &Command{Factory: factory}
+
+	c := &cobra.Command{
+		Use:   "unlock NAME",
+		Short: "Force releases a stale terraform state lock on a configuration",
+		Args:  cobra.ExactArgs(1),
+		Long:  strings.TrimPrefix(longDescription, "\n"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.Name = args[0]
+
+			return options.Run(cmd.Context())
+		},
+		ValidArgsFunction: cmd.AutoCompleteConfigurations(options.Factory),
+	}
+
+	flags := c.Flags()
+	flags.StringVarP(&options.Namespace, "namespace", "n", "default", "Namespace of the resource")
+	flags.StringVar(&options.LockID, "lock-id", "", "Identifier of the state lock to release, defaults to the lock reported on the status")
+
+	cmd.RegisterFlagCompletionFunc(c, "namespace", cmd.AutoCompleteNamespaces(factory))
+
+	return c
+}
+
+// Run is called to execute the unlock command
+func (o *Command) Run(ctx context.Context) error {
+	switch {
+	case o.Namespace == "":
+		return errors.New("namespace is required")
+
+	case o.Name == "":
+		return errors.New("name is required")
+	}
+
+	cc, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	configuration := &terraformv1alphav1.Configuration{}
+	configuration.Namespace = o.Namespace
+	configuration.Name = o.Name
+
+	found, err := kubernetes.GetIfExists(ctx, cc, configuration)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("configuration %s not found", o.Name)
+	}
+
+	id := o.LockID
+	if id == "" && configuration.Status.StateLock != nil {
+		id = configuration.Status.StateLock.ID
+	}
+	switch {
+	case id == "":
+		return fmt.Errorf("configuration %s has no known state lock, use --lock-id to specify the lock", o.Name)
+	case !terraform.IsValidLockID(id):
+		return fmt.Errorf("state lock id %q is invalid", id)
+	}
+
+	original := configuration.DeepCopy()
+	if configuration.Annotations == nil {
+		configuration.Annotations = map[string]string{}
+	}
+	configuration.Annotations[terraformv1alphav1.UnlockAnnotation] = id
+
+	if err := cc.Patch(ctx, configuration, client.MergeFrom(original)); err != nil {
+		return err
+	}
+	o.Println("%s Configuration %s state lock %s will be released", cmd.IconGood, o.Name, id)
+
+	return nil
+}
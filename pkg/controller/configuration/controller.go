@@ -44,11 +44,15 @@ import (
 
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 	"github.com/appvia/terraform-controller/pkg/handlers/configurations"
-	"github.com/appvia/terraform-controller/pkg/utils/policies"
+	"github.com/appvia/terraform-controller/pkg/utils/modules"
+	"github.com/appvia/terraform-controller/pkg/utils/notifications"
 )
 
 const controllerName = "configuration.terraform.appvia.io"
 
+// DefaultMaxConcurrentReconciles is the number of configurations reconciled concurrently by default
+const DefaultMaxConcurrentReconciles = 10
+
 // Controller handles the reconciliation of the configuration resource
 type Controller struct {
 	// cc is the kubernetes client to the cluster
@@ -58,8 +62,14 @@ type Controller struct {
 	kc kubernetes.Interface
 	// cache is a local cache of resources to make lookups faster
 	cache *cache.Cache
+	// queue is the queue of configurations waiting to run a terraform job
+	queue *jobQueue
 	// recorder is the kubernetes event recorder
 	recorder record.EventRecorder
+	// DefaultBackend is the terraform state backend used when the provider does not define one
+	DefaultBackend *terraformv1alphav1.Backend
+	// DefaultRetry is the retry policy for failed jobs used when the configuration does not define one
+	DefaultRetry *terraformv1alphav1.Retry
 	// ExecutorSecrets is a collection of secrets which should be added to the
 	// executors job everytime - these are configured by the platform team on the
 	// cli options
@@ -68,6 +78,8 @@ type Controller struct {
 	ControllerNamespace string
 	// EnableInfracosts enables the cost analytics via infracost
 	EnableInfracosts bool
+	// EnableStateLocking indicates terraform should acquire the state lock when running
+	EnableStateLocking bool
 	// EnableWatchers indicates we should create watcher jobs in the user namespace
 	EnableWatchers bool
 	// ExecutorImage is the image to use for the executor
@@ -80,8 +92,22 @@ type Controller struct {
 	InfracostsSecretName string
 	// JobTemplate is a custom override for the template to use
 	JobTemplate string
+	// MaxConcurrentJobs is the maximum number of terraform plan and apply jobs which can run at
+	// the same time, zero is unlimited
+	MaxConcurrentJobs int
+	// MaxConcurrentReconciles is the number of configurations which can be reconciled concurrently
+	MaxConcurrentReconciles int
+	// ModuleResolver resolves module sources to an immutable revision, pinning is disabled when nil
+	ModuleResolver modules.Resolver
+	// Notifier sends the lifecycle events of the configurations to the notification sinks, notifications
+	// are disabled when nil
+	Notifier notifications.Notifier
 	// PolicyImage is the image to use for all policy / checkov jobs
 	PolicyImage string
+	// RevisionHistoryLimit is the maximum number of revisions retained per configuration
+	RevisionHistoryLimit int
+	// StateLockTimeout is the duration terraform waits to acquire the state lock
+	StateLockTimeout time.Duration
 	// TerraformImage is the image to use for all terraform jobs
 	TerraformImage string
 }
@@ -91,7 +117,9 @@ func (c *Controller) Add(mgr manager.Manager) error {
 	log.WithFields(log.Fields{
 		"additional_secrets": len(c.ExecutorSecrets),
 		"enable_costs":       c.EnableInfracosts,
+		"enable_locking":     c.EnableStateLocking,
 		"enable_watchers":    c.EnableWatchers,
+		"max_jobs":           c.MaxConcurrentJobs,
 		"namespace":          c.ControllerNamespace,
 		"policy_image":       c.PolicyImage,
 		"terraform_image":    c.TerraformImage,
@@ -110,8 +138,13 @@ func (c *Controller) Add(mgr manager.Manager) error {
 		return errors.New("infracost secret is required")
 	}
 
+	if c.MaxConcurrentReconciles <= 0 {
+		c.MaxConcurrentReconciles = DefaultMaxConcurrentReconciles
+	}
+
 	c.cc = mgr.GetClient()
 	c.cache = cache.New(12*time.Hour, 10*time.Minute)
+	c.queue = newJobQueue()
 	c.recorder = mgr.GetEventRecorderFor(controllerName)
 
 	kc, err := kubernetes.NewForConfig(mgr.GetConfig())
@@ -132,7 +165,7 @@ func (c *Controller) Add(mgr manager.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&terraformv1alphav1.Configuration{}).
 		Named(controllerName).
-		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: c.MaxConcurrentReconciles}).
 		// @note: we will avoid reconciliation on any resource where the annotation is set
 		WithEventFilter(&predicate.Funcs{
 			CreateFunc: func(e event.CreateEvent) bool {
@@ -159,6 +192,11 @@ func (c *Controller) Add(mgr manager.Manager) error {
 				return nil
 			}),
 		).
+		Watches(
+			// allows us to requeue any configurations depending on, or depended upon by, another configuration
+			&source.Kind{Type: &terraformv1alphav1.Configuration{}},
+			handler.EnqueueRequestsFromMapFunc(c.findRelatedConfigurations),
+		).
 		Watches(
 			&source.Kind{Type: &batchv1.Job{}},
 			// allows us to requeue the resource when the job has updated
@@ -189,23 +227,58 @@ func (c *Controller) Add(mgr manager.Manager) error {
 		Complete(c)
 }
 
-// findMatchingPolicies is used to find a matching policy for the configuration. Note, ONLY one policy
-// can be returned - we weight multiple policy least to most specific - i.e. no selector (i.e match all, weight=1),
-// namespace labels=10, resource labels=20 per label. If multiple policies equal the same weight we throw
-// an error.
-func (c *Controller) findMatchingPolicy(
+// findRelatedConfigurations returns a request for any configuration in the same namespace depending on
+// the configuration, along with the configurations it depends upon so deletions can progress in order
+func (c *Controller) findRelatedConfigurations(o client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	// @step: the dependencies of the configuration are requeued, they may be waiting on us to be deleted
+	if configuration, ok := o.(*terraformv1alphav1.Configuration); ok {
+		for _, name := range configuration.GetDependencies() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKey{Namespace: o.GetNamespace(), Name: name},
+			})
+		}
+	}
+
+	list := &terraformv1alphav1.ConfigurationList{}
+	if err := c.cc.List(context.Background(), list, client.InNamespace(o.GetNamespace())); err != nil {
+		log.WithError(err).WithField("namespace", o.GetNamespace()).Error("failed to list the configurations in namespace")
+
+		return requests
+	}
+
+	for _, x := range list.Items {
+		if x.IsDependentOn(o.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: x.GetNamespacedName()})
+		}
+	}
+
+	return requests
+}
+
+// findMatchingConstraint is used to find the constraint from the policies which applies to the configuration,
+// using the namespace of the configuration from the cache. Note, with the exception of notifications ONLY one
+// policy can be returned - we weight multiple policy least to most specific - i.e. no selector (i.e match all,
+// weight=1), namespace labels=10, resource labels=20 per label. If multiple policies equal the same weight we
+// throw an error.
+func findMatchingConstraint[T any](
 	ctx context.Context,
+	c *Controller,
 	configuration *terraformv1alphav1.Configuration,
-	list *terraformv1alphav1.PolicyList) (*terraformv1alphav1.PolicyConstraint, error) {
+	list *terraformv1alphav1.PolicyList,
+	find func(context.Context, *terraformv1alphav1.Configuration, client.Object, *terraformv1alphav1.PolicyList) (T, error)) (T, error) {
+
+	var constraint T
 
-	if len(list.Items) == 0 {
-		return nil, nil
+	if list == nil || len(list.Items) == 0 {
+		return constraint, nil
 	}
 
 	namespace, found := c.cache.Get(configuration.Namespace)
 	if !found {
-		return nil, fmt.Errorf("namespace: %q was not found in the cache", configuration.Namespace)
+		return constraint, fmt.Errorf("namespace: %q was not found in the cache", configuration.Namespace)
 	}
 
-	return policies.FindMatchingPolicy(ctx, configuration, namespace.(client.Object), list)
+	return find(ctx, configuration, namespace.(client.Object), list)
 }
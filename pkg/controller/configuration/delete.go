@@ -20,8 +20,11 @@ package configuration
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	log "github.com/sirupsen/logrus"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,33 +36,185 @@ import (
 	"github.com/appvia/terraform-controller/pkg/utils/filters"
 	"github.com/appvia/terraform-controller/pkg/utils/jobs"
 	"github.com/appvia/terraform-controller/pkg/utils/kubernetes"
+	"github.com/appvia/terraform-controller/pkg/utils/terraform"
 )
 
+// ensureNoDependents is responsible for ensuring no other configurations depend on this configuration
+// before it is destroyed, i.e. a database must be destroyed before the network it resides within
+func (c *Controller) ensureNoDependents(configuration *terraformv1alphav1.Configuration) controller.EnsureFunc {
+	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		// @step: if the configuration is being orphaned, nothing is destroyed
+		if configuration.IsOrphaned() {
+			return reconcile.Result{}, nil
+		}
+
+		list := &terraformv1alphav1.ConfigurationList{}
+		if err := c.cc.List(ctx, list, client.InNamespace(configuration.Namespace)); err != nil {
+			cond.Failed(err, "Failed to list the configurations in the namespace")
+
+			return reconcile.Result{}, err
+		}
+
+		var dependents []string
+		for _, x := range list.Items {
+			if x.Name != configuration.Name && x.IsDependentOn(configuration.Name) {
+				dependents = append(dependents, x.Name)
+			}
+		}
+		if len(dependents) == 0 {
+			return reconcile.Result{}, nil
+		}
+		sort.Strings(dependents)
+
+		cond.InProgress("Waiting for dependent configurations to be deleted (%s)", strings.Join(dependents, ", "))
+
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+}
+
+// ensureTerraformDestroyApproval is responsible for holding the terraform destroy of configurations with the
+// RequireApproval deletion policy. A terraform plan -destroy is produced and summarised on the status, and the
+// destroy waits for the destroy-approved annotation
+func (c *Controller) ensureTerraformDestroyApproval(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
+	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
+	generation := fmt.Sprintf("%d", configuration.GetGeneration())
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		switch {
+		case configuration.IsOrphaned():
+			return reconcile.Result{}, nil
+		case configuration.GetDeletionPolicy() != terraformv1alphav1.DeletionPolicyRequireApproval:
+			return reconcile.Result{}, nil
+		case configuration.IsDestroyApproved():
+			return reconcile.Result{}, nil
+		}
+
+		// @step: without a terraform state there is nothing to destroy
+		tfstate, err := state.backend.GetState(ctx)
+		if err != nil {
+			cond.Failed(err, "Failed to check for the terraform state in the %s backend", state.backend.Type())
+
+			return reconcile.Result{}, err
+		}
+		if tfstate == nil {
+			return reconcile.Result{}, nil
+		}
+
+		job, found := filters.Jobs(state.jobs).
+			WithGeneration(generation).
+			WithName(configuration.GetName()).
+			WithNamespace(configuration.GetNamespace()).
+			WithStage(terraformv1alphav1.StageTerraformDestroyPlan).
+			WithUID(string(configuration.GetUID())).
+			Latest()
+
+		if !found {
+			// @step: the destroy plan uses the module revision last resolved by the controller
+			var module string
+			if configuration.Status.Module != nil {
+				module = configuration.Status.Module.Source
+			}
+
+			runner, err := jobs.New(configuration, state.provider).NewTerraformDestroyPlan(jobs.Options{
+				BackendSecret:      state.backendSecret,
+				EnableStateLocking: c.EnableStateLocking,
+				ExecutorImage:      c.ExecutorImage,
+				ExecutorSecrets:    c.ExecutorSecrets,
+				Module:             module,
+				Namespace:          c.ControllerNamespace,
+				StateLockTimeout:   c.StateLockTimeout,
+				Template:           state.jobTemplate,
+				TerraformImage:     GetTerraformImage(configuration, c.TerraformImage),
+			})
+			if err != nil {
+				cond.Failed(err, "Failed to create the terraform destroy plan job")
+
+				return reconcile.Result{}, err
+			}
+
+			// @note: no watcher is created, as the configuration namespace may itself be terminating
+			if err := c.createJob(ctx, runner); err != nil {
+				cond.Failed(err, "Failed to create the terraform destroy plan job")
+
+				return reconcile.Result{}, err
+			}
+			cond.InProgress("Terraform destroy plan is running")
+
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		switch {
+		case jobs.IsFailed(job):
+			cond.Failed(nil, "Terraform destroy plan has failed, check the logs of the destroy plan job")
+
+			return reconcile.Result{}, controller.ErrIgnore
+
+		case !jobs.IsComplete(job):
+			cond.InProgress("Terraform destroy plan is running")
+
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		// @step: publish the summary of the destroy plan on the status
+		secret := &v1.Secret{}
+		secret.Namespace = c.ControllerNamespace
+		secret.Name = configuration.GetTerraformPlanSecretName()
+
+		found, err = kubernetes.GetIfExists(ctx, c.cc, secret)
+		if err != nil {
+			cond.Failed(err, "Failed to retrieve the terraform destroy plan")
+
+			return reconcile.Result{}, err
+		}
+		if !found || secret.GetLabels()[terraformv1alphav1.ConfigurationStageLabel] != terraformv1alphav1.StageTerraformDestroyPlan {
+			cond.ActionRequired("Terraform destroy plan not found, waiting for %s annotation to be set to true",
+				terraformv1alphav1.DestroyApprovedAnnotation)
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+
+		plan, err := terraform.DecodePlan(secret.Data[terraformv1alphav1.TerraformPlanJSONSecretKey])
+		if err != nil {
+			cond.ActionRequired("Failed to decode the terraform destroy plan output")
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+		configuration.Status.Plan = NewPlanStatus(plan, configuration.GetGeneration())
+
+		// @note: there is nothing to approve if the destroy would not change anything
+		if !configuration.Status.Plan.HasChanges {
+			return reconcile.Result{}, nil
+		}
+		cond.ActionRequired("Terraform destroy plan (%s), waiting for %s annotation to be set to true",
+			configuration.Status.Plan.Summary, terraformv1alphav1.DestroyApprovedAnnotation)
+
+		return reconcile.Result{}, controller.ErrIgnore
+	}
+}
+
 // ensureTerraformDestroy is responsible for deleting any associated terraform configuration
 func (c *Controller) ensureTerraformDestroy(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
 	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
 	generation := fmt.Sprintf("%d", configuration.GetGeneration())
 
 	return func(ctx context.Context) (reconcile.Result, error) {
-		// @step: if the configuration has the orphan label we can skip the deletion step
-		if configuration.GetAnnotations()[terraformv1alphav1.OrphanAnnotation] == "true" {
+		// @step: if the configuration has the orphan label or policy we can skip the deletion step
+		if configuration.IsOrphaned() {
 			return reconcile.Result{}, nil
 		}
 
 		configuration.Status.ResourceStatus = terraformv1alphav1.DestroyingResources
 
 		// @step: check we have a terraform state - else we can just continue
-		secret := &v1.Secret{}
-		secret.Namespace = c.ControllerNamespace
-		secret.Name = configuration.GetTerraformStateSecretName()
-
-		found, err := kubernetes.GetIfExists(ctx, c.cc, secret)
+		tfstate, err := state.backend.GetState(ctx)
 		if err != nil {
-			cond.Failed(err, "Failed to check for the terraform state secret")
+			cond.Failed(err, "Failed to check for the terraform state in the %s backend", state.backend.Type())
 
 			return reconcile.Result{}, err
 		}
-		if !found {
+		if tfstate == nil {
 			return reconcile.Result{}, nil
 		}
 
@@ -72,17 +227,27 @@ func (c *Controller) ensureTerraformDestroy(configuration *terraformv1alphav1.Co
 			WithUID(string(configuration.GetUID())).
 			Latest()
 
+		// @step: the destroy uses the module revision last resolved by the controller
+		var module string
+		if configuration.Status.Module != nil {
+			module = configuration.Status.Module.Source
+		}
+
 		// @step: generate the destroy job
 		batch := jobs.New(configuration, state.provider)
 		runner, err := batch.NewTerraformDestroy(jobs.Options{
-			EnableInfraCosts: c.EnableInfracosts,
-			ExecutorImage:    c.ExecutorImage,
-			ExecutorSecrets:  c.ExecutorSecrets,
-			InfracostsImage:  c.InfracostsImage,
-			InfracostsSecret: c.InfracostsSecretName,
-			Namespace:        c.ControllerNamespace,
-			Template:         state.jobTemplate,
-			TerraformImage:   GetTerraformImage(configuration, c.TerraformImage),
+			EnableInfraCosts:   c.EnableInfracosts,
+			EnableStateLocking: c.EnableStateLocking,
+			ExecutorImage:      c.ExecutorImage,
+			BackendSecret:      state.backendSecret,
+			ExecutorSecrets:    c.ExecutorSecrets,
+			InfracostsImage:    c.InfracostsImage,
+			InfracostsSecret:   c.InfracostsSecretName,
+			Module:             module,
+			Namespace:          c.ControllerNamespace,
+			StateLockTimeout:   c.StateLockTimeout,
+			Template:           state.jobTemplate,
+			TerraformImage:     GetTerraformImage(configuration, c.TerraformImage),
 		})
 		if err != nil {
 			cond.Failed(err, "Failed to create the terraform destroy job")
@@ -99,7 +264,7 @@ func (c *Controller) ensureTerraformDestroy(configuration *terraformv1alphav1.Co
 					return reconcile.Result{}, err
 				}
 
-				if err := c.cc.Create(ctx, runner); err != nil {
+				if err := c.createJob(ctx, runner); err != nil {
 					cond.Failed(err, "Failed to create the terraform destroy job")
 
 					return reconcile.Result{}, err
@@ -116,6 +281,16 @@ func (c *Controller) ensureTerraformDestroy(configuration *terraformv1alphav1.Co
 
 		case jobs.IsFailed(job):
 			cond.Failed(nil, "Terraform destroy is failing")
+
+			// @step: a destroy unable to acquire the state lock is run again once the lock is released
+			lock, err := c.findStateLockInJob(ctx, job)
+			if err != nil {
+				log.WithError(err).WithField("job", job.GetName()).Warn("failed to check the terraform destroy logs for the state lock")
+			}
+			if lock != nil {
+				recordStateLock(configuration, cond, lock)
+			}
+
 			return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 
 		case jobs.IsActive(job):
@@ -134,6 +309,8 @@ func (c *Controller) ensureConfigurationSecretsDeleted(configuration *terraformv
 		names := []string{
 			configuration.GetTerraformConfigSecretName(),
 			configuration.GetTerraformCostSecretName(),
+			configuration.GetTerraformDriftPlanSecretName(),
+			configuration.GetTerraformPlanSecretName(),
 			configuration.GetTerraformPolicySecretName(),
 			configuration.GetTerraformStateSecretName(),
 		}
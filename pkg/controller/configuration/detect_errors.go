@@ -18,28 +18,25 @@
 package configuration
 
 import (
+	"bytes"
 	"context"
-	"io"
 	"regexp"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	batchv1 "k8s.io/api/batch/v1"
-	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1alphav1 "github.com/appvia/terraform-controller/pkg/apis/core/v1alpha1"
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 	"github.com/appvia/terraform-controller/pkg/controller"
-	"github.com/appvia/terraform-controller/pkg/utils/jobs"
-	"github.com/appvia/terraform-controller/pkg/utils/kubernetes"
 	"github.com/appvia/terraform-controller/pkg/utils/terraform"
 )
 
 // ensureErrorDetection is helper used to try and detect by the configuration failed and
-// report is back to the users via status
-func (c *Controller) ensureErrorDetection(configuration *terraformv1alphav1.Configuration, job *batchv1.Job, state *state) controller.EnsureFunc {
+// report is back to the users via status. Unless a detector has determined the failure is
+// not retryable, the retry is called to decide if the job should be attempted again
+func (c *Controller) ensureErrorDetection(configuration *terraformv1alphav1.Configuration, job *batchv1.Job, state *state, retry controller.EnsureFunc) controller.EnsureFunc {
 	logger := log.WithFields(log.Fields{
 		"job":       job.Name,
 		"name":      configuration.Name,
@@ -49,63 +46,52 @@ func (c *Controller) ensureErrorDetection(configuration *terraformv1alphav1.Conf
 	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
 
 	return func(ctx context.Context) (reconcile.Result, error) {
-		// @step: we check if the logs for the configuration are available
-		pods, err := c.kc.CoreV1().Pods(c.ControllerNamespace).List(ctx, metav1.ListOptions{
-			LabelSelector: "job-name=" + job.Name,
-		})
-		if err != nil {
-			logger.WithError(err).Error("failed to list pods for job")
-
-			return reconcile.Result{}, controller.ErrIgnore
-		}
-
-		// @step: ensure we have at least one pod
-		pod := kubernetes.FindLatestPod(pods)
-		if pod == nil {
-			logger.Error("no matching pod found for job, skipping the error checks")
+		// @note: when we are unable to determine the cause of the failure we permit a retry
+		next := func() (reconcile.Result, error) {
+			if retry == nil {
+				return reconcile.Result{}, controller.ErrIgnore
+			}
 
-			return reconcile.Result{}, controller.ErrIgnore
+			return retry(ctx)
 		}
 
-		// @step: ensure the pod is has finished
-		switch pod.Status.Phase {
-		case v1.PodPending, v1.PodRunning:
+		// @step: retrieve the logs from the terraform container of the job
+		logs, err := c.jobLogs(ctx, job)
+		switch {
+		case err == errJobRunning:
 			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
-		case v1.PodSucceeded, v1.PodFailed:
-			break
-		default:
-			// @step: anything else and it's not certain we can workout what went wrong
+		case err != nil:
+			// @note: if we cannot retrieve the logs it's not certain we can workout what went wrong
 			// so we'll just ignore it and post as an error
-			return reconcile.Result{}, controller.ErrIgnore
-		}
-
-		// @step: find the terraform container and retrieve the logs
-		stream, err := c.kc.CoreV1().Pods(c.ControllerNamespace).GetLogs(pod.Name, &v1.PodLogOptions{
-			Container: jobs.TerraformContainerName,
-			Follow:    false,
-		}).Stream(ctx)
-		if err != nil {
 			logger.WithError(err).Error("failed to retrieve logs for job")
 
-			return reconcile.Result{}, controller.ErrIgnore
+			return next()
+		case logs == nil:
+			logger.Error("no matching pod found for job, skipping the error checks")
+
+			return next()
 		}
-		defer stream.Close()
 
-		// @step: retrieve the logs from the terraform container
-		logs, err := io.ReadAll(stream)
+		// @step: check if terraform was unable to acquire the state lock, this is likely transient, unless
+		// the lock is stale, in which case it has to be released
+		lock, err := terraform.FindStateLockInLogs(bytes.NewReader(logs))
 		if err != nil {
-			logger.WithField("container", jobs.TerraformContainerName).
-				WithError(err).Error("failed to read logs from pod while trying to detect errors")
+			logger.WithError(err).Error("failed to scan the logs for the terraform state lock")
+		}
+		if lock != nil {
+			recordStateLock(configuration, cond, lock)
 
-			return reconcile.Result{}, controller.ErrIgnore
+			return next()
 		}
 
 		// @step: retrieve all the detection regexes for this configuration
 		detectors := append(terraform.Detectors["*"], terraform.Detectors[provider]...)
 		if len(detectors) == 0 {
-			return reconcile.Result{}, controller.ErrIgnore
+			return next()
 		}
 
+		retryable := true
+
 		for i := 0; i < len(detectors); i++ {
 			m, err := regexp.Compile(detectors[i].Regex)
 			if err != nil {
@@ -115,8 +101,12 @@ func (c *Controller) ensureErrorDetection(configuration *terraformv1alphav1.Conf
 			}
 			if m.MatchString(string(logs)) {
 				cond.ActionRequired(detectors[i].Message)
+				retryable = retryable && detectors[i].Retryable
 			}
 		}
+		if retryable {
+			return next()
+		}
 
 		// if we've entered this method we cannot move forward in the reconciliation process
 		return reconcile.Result{}, controller.ErrIgnore
@@ -43,6 +43,8 @@ import (
 	"github.com/appvia/terraform-controller/pkg/utils/filters"
 	"github.com/appvia/terraform-controller/pkg/utils/jobs"
 	"github.com/appvia/terraform-controller/pkg/utils/kubernetes"
+	"github.com/appvia/terraform-controller/pkg/utils/modules"
+	"github.com/appvia/terraform-controller/pkg/utils/policies"
 	"github.com/appvia/terraform-controller/pkg/utils/terraform"
 )
 
@@ -75,6 +77,13 @@ func (c *Controller) ensureCapturedState(configuration *terraformv1alphav1.Confi
 		state.jobs = jobs
 		state.policies = policies
 
+		// @step: a manual retry forms part of the upstream checksum, so the configuration is planned
+		// and applied again for the same generation
+		if retry := configuration.GetAnnotations()[terraformv1alphav1.RetryAnnotation]; retry != "" {
+			state.upstreams["retry"] = retry
+			state.upstream = upstreamChecksum(state.upstreams)
+		}
+
 		return reconcile.Result{}, nil
 	}
 }
@@ -113,6 +122,39 @@ func (c *Controller) ensureNoActivity(configuration *terraformv1alphav1.Configur
 	}
 }
 
+// ensureDependenciesReady is responsible for ensuring any configurations listed in spec.dependsOn are
+// ready before we attempt to plan the configuration
+func (c *Controller) ensureDependenciesReady(configuration *terraformv1alphav1.Configuration) controller.EnsureFunc {
+	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		for i, name := range configuration.Spec.DependsOn {
+			dependency := &terraformv1alphav1.Configuration{}
+			dependency.Namespace = configuration.Namespace
+			dependency.Name = name
+
+			found, err := kubernetes.GetIfExists(ctx, c.cc, dependency)
+			if err != nil {
+				cond.Failed(err, "Failed to retrieve the configuration spec.dependsOn[%d]", i)
+
+				return reconcile.Result{}, err
+			}
+
+			switch {
+			case !found:
+				cond.InProgress("Waiting for configuration spec.dependsOn[%d] (%s/%s) to be created", i, dependency.Namespace, name)
+				return reconcile.Result{RequeueAfter: time.Minute}, nil
+
+			case !dependency.Status.IsComplete(corev1alphav1.ConditionReady, dependency.GetGeneration()):
+				cond.InProgress("Waiting for configuration spec.dependsOn[%d] (%s/%s) to be ready", i, dependency.Namespace, name)
+				return reconcile.Result{RequeueAfter: time.Minute}, nil
+			}
+		}
+
+		return reconcile.Result{}, nil
+	}
+}
+
 // ensureCostSecret is responsible for ensuring the cost analytics secret is available. This secret is added into
 // the job namespace by the platform administrator - but it's possible someone has deleted / changed it - so better to
 // place guard around it
@@ -161,6 +203,59 @@ func (c *Controller) ensureValueFromSecret(configuration *terraformv1alphav1.Con
 		}
 
 		for i, x := range configuration.Spec.ValueFrom {
+			// @step: the value is taken from the outputs of another configuration
+			if x.Configuration != "" {
+				upstream := &terraformv1alphav1.Configuration{}
+				upstream.Namespace = configuration.Namespace
+				upstream.Name = x.Configuration
+
+				found, err := kubernetes.GetIfExists(ctx, c.cc, upstream)
+				if err != nil {
+					cond.Failed(err, "Failed to retrieve the configuration spec.valueFrom[%d]", i)
+
+					return reconcile.Result{}, err
+				}
+
+				// @step: we wait for the configuration to be ready at its current generation
+				switch {
+				case !found && x.Optional:
+					continue
+
+				case !found:
+					cond.InProgress("Waiting for configuration spec.valueFrom[%d] (%s/%s) to be created", i, configuration.Namespace, upstream.Name)
+					return reconcile.Result{RequeueAfter: time.Minute}, nil
+
+				case !upstream.Status.IsComplete(corev1alphav1.ConditionReady, upstream.GetGeneration()):
+					cond.InProgress("Waiting for configuration spec.valueFrom[%d] (%s/%s) to be ready", i, configuration.Namespace, upstream.Name)
+					return reconcile.Result{RequeueAfter: time.Minute}, nil
+				}
+
+				tfstate, err := c.getConfigurationState(ctx, upstream)
+				if err != nil {
+					cond.Failed(err, "Failed to retrieve the outputs of configuration spec.valueFrom[%d] (%s/%s)", i, configuration.Namespace, upstream.Name)
+
+					return reconcile.Result{}, err
+				}
+
+				var output terraform.OutputValue
+				if tfstate != nil {
+					output, found = tfstate.Outputs[x.Key]
+				}
+				switch {
+				case tfstate == nil, !found:
+					if x.Optional {
+						continue
+					}
+					cond.ActionRequired("Configuration spec.valueFrom[%d] (%s/%s) does not have the output: %q", i, configuration.Namespace, upstream.Name, x.Key)
+
+					return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+				}
+				state.valueFrom[x.Key] = output.String()
+				state.upstreams[x.Configuration+"/"+x.Key] = output.String()
+
+				continue
+			}
+
 			secret := &v1.Secret{}
 			secret.Namespace = configuration.Namespace
 			secret.Name = x.Secret
@@ -193,6 +288,66 @@ func (c *Controller) ensureValueFromSecret(configuration *terraformv1alphav1.Con
 				state.valueFrom[x.Key] = string(secret.Data[x.Key])
 			}
 		}
+		state.upstream = upstreamChecksum(state.upstreams)
+
+		return reconcile.Result{}, nil
+	}
+}
+
+// ensureModuleResolved is responsible for resolving the module source to an immutable revision, the
+// revision forms part of the upstream checksum so the configuration is planned again when it changes
+func (c *Controller) ensureModuleResolved(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
+	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		if c.ModuleResolver == nil {
+			return reconcile.Result{}, nil
+		}
+		key := "module/" + configuration.Spec.Module
+
+		var reference *modules.Reference
+		if cached, found := c.cache.Get(key); found {
+			reference = cached.(*modules.Reference)
+		} else {
+			resolved, err := c.ModuleResolver.Resolve(ctx, configuration.Spec.Module)
+			if err != nil {
+				cond.Failed(err, "Failed to resolve the revision of the module: %q", configuration.Spec.Module)
+
+				return reconcile.Result{}, err
+			}
+			// @note: unsupported sources are cached as well, saving us from parsing them again
+			reference = resolved
+			c.cache.Set(key, reference, 5*time.Minute)
+		}
+
+		// @step: the module source is not one we can resolve, i.e. a s3 bucket
+		if reference == nil {
+			configuration.Status.Module = nil
+
+			return reconcile.Result{}, nil
+		}
+
+		status := &terraformv1alphav1.ModuleStatus{Revision: reference.Revision, Source: reference.Source}
+		if configuration.Spec.ModuleUpdatePolicy != "" && reference.Version != "" {
+			if update, found := modules.FindUpdate(reference.Version, reference.Versions, configuration.Spec.ModuleUpdatePolicy); found {
+				status.Update = update
+			}
+		}
+		// @note: the status can be lost when the configuration is patched later in the chain, so we also
+		// keep track of the updates we have reported in the cache
+		if status.Update != "" && (configuration.Status.Module == nil || configuration.Status.Module.Update != status.Update) {
+			key := "module-update/" + string(configuration.GetUID())
+			if reported, found := c.cache.Get(key); !found || reported.(string) != status.Update {
+				c.recorder.Event(configuration, v1.EventTypeNormal, "ModuleUpdate",
+					fmt.Sprintf("Module version %s is available, currently using %s", status.Update, reference.Version))
+				c.cache.SetDefault(key, status.Update)
+			}
+		}
+		configuration.Status.Module = status
+
+		state.module = reference.Source
+		state.upstreams["module"] = reference.Revision
+		state.upstream = upstreamChecksum(state.upstreams)
 
 		return reconcile.Result{}, nil
 	}
@@ -328,12 +483,143 @@ func (c *Controller) ensureProviderReady(configuration *terraformv1alphav1.Confi
 	}
 }
 
+// ensureTerraformBackend is responsible for resolving the terraform state backend for the configuration. The
+// backend defined on the provider takes precedence over the controller default
+func (c *Controller) ensureTerraformBackend(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
+	cond := controller.ConditionMgr(configuration, terraformv1alphav1.ConditionProviderReady, c.recorder)
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		backend := c.getBackendDefinition(state.provider)
+
+		// @step: retrieve the credentials for the backend
+		var secret *v1.Secret
+		if backend != nil && backend.SecretRef != nil && backend.SecretRef.Name != "" {
+			secret = &v1.Secret{}
+			secret.Namespace = c.ControllerNamespace
+			secret.Name = backend.SecretRef.Name
+
+			found, err := kubernetes.GetIfExists(ctx, c.cc, secret)
+			if err != nil {
+				cond.Failed(err, "Failed to retrieve the terraform backend secret (%s/%s)", secret.Namespace, secret.Name)
+
+				return reconcile.Result{}, err
+			}
+			if !found {
+				cond.ActionRequired("Terraform backend secret (%s/%s) does not exist", secret.Namespace, secret.Name)
+
+				return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+			}
+			state.backendSecret = secret.Name
+		}
+
+		options, err := c.newBackendOptions(configuration, backend, secret)
+		if err != nil {
+			cond.ActionRequired("Terraform backend configuration is invalid, %s", err)
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+
+		b, err := terraform.NewBackend(options)
+		if err != nil {
+			cond.ActionRequired("Terraform backend is invalid, %s", err)
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+		state.backend = b
+
+		return reconcile.Result{}, nil
+	}
+}
+
+// ensureTerraformStateImport is responsible for seeding the terraform state of the configuration from the
+// spec.importState secret, this only happens before the configuration has a state of its own
+func (c *Controller) ensureTerraformStateImport(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
+	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		if configuration.Spec.ImportState == nil {
+			return reconcile.Result{}, nil
+		}
+		if state.backend.Type() != terraformv1alphav1.KubernetesBackendType {
+			cond.ActionRequired("Importing state (spec.importState) is not supported by the %s backend", state.backend.Type())
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+
+		// @step: we never overwrite an existing state
+		tfstate := &v1.Secret{}
+		tfstate.Namespace = c.ControllerNamespace
+		tfstate.Name = configuration.GetTerraformStateSecretName()
+
+		found, err := kubernetes.GetIfExists(ctx, c.cc, tfstate)
+		if err != nil {
+			cond.Failed(err, "Failed to check for the terraform state secret")
+
+			return reconcile.Result{}, err
+		}
+		if found {
+			return reconcile.Result{}, nil
+		}
+
+		secret := &v1.Secret{}
+		secret.Namespace = configuration.Namespace
+		secret.Name = configuration.Spec.ImportState.Name
+
+		found, err = kubernetes.GetIfExists(ctx, c.cc, secret)
+		if err != nil {
+			cond.Failed(err, "Failed to retrieve the import state secret (%s/%s)", secret.Namespace, secret.Name)
+
+			return reconcile.Result{}, err
+		}
+		if !found {
+			cond.ActionRequired("Import state secret (%s/%s) does not exist", secret.Namespace, secret.Name)
+
+			return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+		}
+
+		// @step: the state can be provided as is or compressed
+		content := secret.Data[terraformv1alphav1.ImportStateSecretKey]
+		if decoded, err := terraform.Decode(content); err == nil {
+			content = decoded
+		}
+		if _, err := terraform.ParseState(content); err != nil || len(content) == 0 {
+			cond.ActionRequired("Import state secret (%s/%s) does not contain a valid terraform state in key: %q",
+				secret.Namespace, secret.Name, terraformv1alphav1.ImportStateSecretKey)
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+
+		encoded, err := terraform.Encode(content)
+		if err != nil {
+			cond.Failed(err, "Failed to encode the imported terraform state")
+
+			return reconcile.Result{}, err
+		}
+
+		// @step: create the state in the same format as the terraform kubernetes backend
+		tfstate.Labels = map[string]string{
+			"app.kubernetes.io/managed-by": "terraform",
+			"tfstate":                      "true",
+			"tfstateSecretSuffix":          string(configuration.GetUID()),
+			"tfstateWorkspace":             configuration.GetWorkspace(),
+		}
+		tfstate.Data = map[string][]byte{terraformv1alphav1.TerraformStateSecretKey: encoded}
+
+		if err := c.cc.Create(ctx, tfstate); err != nil {
+			cond.Failed(err, "Failed to create the terraform state from the import state secret")
+
+			return reconcile.Result{}, err
+		}
+
+		return reconcile.Result{}, nil
+	}
+}
+
 // ensureJobConfigurationSecret is responsible in ensuring the terraform configuration is generated for this job. This
 // includes the backend configuration and the variables which have been included in the configuration
 func (c *Controller) ensureJobConfigurationSecret(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
 	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
 	policyCondition := controller.ConditionMgr(configuration, terraformv1alphav1.ConditionTerraformPolicy, c.recorder)
-	backend := string(configuration.GetUID())
 	name := configuration.GetTerraformConfigSecretName()
 
 	return func(ctx context.Context) (reconcile.Result, error) {
@@ -352,9 +638,8 @@ func (c *Controller) ensureJobConfigurationSecret(configuration *terraformv1alph
 			terraformv1alphav1.ConfigurationUIDLabel:       string(configuration.GetUID()),
 		}
 
-		// @step: generate the terraform backend configuration - this creates a kubernetes terraform
-		// backend pointing at a secret
-		cfg, err := terraform.NewKubernetesBackend(c.ControllerNamespace, backend)
+		// @step: generate the terraform backend configuration for the state
+		cfg, err := state.backend.Configuration()
 		if err != nil {
 			cond.Failed(err, "Failed to generate the terraform backend configuration")
 
@@ -397,6 +682,19 @@ func (c *Controller) ensureJobConfigurationSecret(configuration *terraformv1alph
 			secret.Data[terraformv1alphav1.TerraformVariablesConfigMapKey] = encoded.Bytes()
 		}
 
+		// @step: generate any import blocks for existing resources
+		if !configuration.HasImports() {
+			delete(secret.Data, terraformv1alphav1.TerraformImportsConfigMapKey)
+		} else {
+			cfg, err := terraform.NewTerraformImports(configuration.Spec.Imports)
+			if err != nil {
+				cond.Failed(err, "Failed to generate the terraform import configuration")
+
+				return reconcile.Result{}, err
+			}
+			secret.Data[terraformv1alphav1.TerraformImportsConfigMapKey] = cfg
+		}
+
 		// @step: copy any authentication details into the secret
 		if state.auth != nil {
 			for k, v := range state.auth.Data {
@@ -405,7 +703,7 @@ func (c *Controller) ensureJobConfigurationSecret(configuration *terraformv1alph
 		}
 
 		// @step: we need to find any matching policy which should be attached to this configuration.
-		policy, err := c.findMatchingPolicy(ctx, configuration, state.policies)
+		policy, err := findMatchingConstraint(ctx, c, configuration, state.policies, policies.FindMatchingPolicy)
 		if err != nil {
 			policyCondition.Failed(err, "Failed to find matching policy constraints")
 
@@ -443,31 +741,76 @@ func (c *Controller) ensureTerraformPlan(configuration *terraformv1alphav1.Confi
 
 	return func(ctx context.Context) (reconcile.Result, error) {
 		switch {
-		// @note: the last plan failed for this generation - we do not run it again
-		case cond.GetCondition().IsFailed(configuration.GetGeneration()):
+		// @note: the last plan failed for this generation - we do not run it again, unless a retry
+		// or the upstreams have changed it since
+		case cond.GetCondition().IsFailed(configuration.GetGeneration()) && state.upstream == configuration.Status.UpstreamChecksum:
 			return reconcile.Result{}, controller.ErrIgnore
 
 		case cond.GetCondition().IsComplete(configuration.GetGeneration()):
-			if !configuration.Spec.EnableDriftDetection || configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation] == "" {
+			switch {
+			case state.upstream != configuration.Status.UpstreamChecksum:
+				// @note: the outputs of an upstream configuration have changed since we last applied, so we need
+				// to plan again for the same generation
+			case !configuration.IsRemediatingDrift():
 				// @note: this is effectively checking the status of plan condition - if the condition is True
 				// for the given generation we can say the plan has already been run and can move on
 				return reconcile.Result{}, nil
 			}
 		}
 
+		// @step: a plan remediating drift is labelled with the drift check it remediates
+		var remediation string
+		if configuration.IsRemediatingDrift() {
+			remediation = configuration.Status.DriftTimestamp
+		}
+
+		// @step: search for any current jobs
+		job, found := filters.Jobs(state.jobs).
+			WithGeneration(generation).
+			WithLabel(terraformv1alphav1.ConfigurationResetLabel, formatPlanResets(configuration)).
+			WithLabel(terraformv1alphav1.ConfigurationUpstreamLabel, state.upstream).
+			WithLabel(terraformv1alphav1.ConfigurationDriftLabel, remediation).
+			WithName(configuration.GetName()).
+			WithNamespace(configuration.GetNamespace()).
+			WithStage(terraformv1alphav1.StageTerraformPlan).
+			WithUID(string(configuration.GetUID())).
+			Latest()
+
+		// @step: a failed plan is attempted again for the same generation once the backoff has passed
+		attempt := 1
+		if found && jobs.IsFailed(job) {
+			cond.Failed(nil, "Terraform plan is failed")
+
+			result, err := c.ensureErrorDetection(configuration, job, state,
+				c.ensureTerraformRetry(configuration, job, terraformv1alphav1.StageTerraformPlan))(ctx)
+			if err != nil || result != (reconcile.Result{}) {
+				return result, err
+			}
+			found, attempt = false, jobs.Attempt(job)+1
+		}
+
 		// @step: lets build the options to render the job
 		options := jobs.Options{
-			AdditionalLabels: map[string]string{terraformv1alphav1.DriftAnnotation: configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]},
-			EnableInfraCosts: c.EnableInfracosts,
-			ExecutorImage:    c.ExecutorImage,
-			ExecutorSecrets:  c.ExecutorSecrets,
-			InfracostsImage:  c.InfracostsImage,
-			InfracostsSecret: c.InfracostsSecretName,
-			Namespace:        c.ControllerNamespace,
-			PolicyConstraint: state.checkovConstraint,
-			PolicyImage:      c.PolicyImage,
-			Template:         state.jobTemplate,
-			TerraformImage:   GetTerraformImage(configuration, c.TerraformImage),
+			AdditionalLabels: map[string]string{
+				terraformv1alphav1.ConfigurationAttemptLabel:  strconv.Itoa(attempt),
+				terraformv1alphav1.ConfigurationResetLabel:    formatPlanResets(configuration),
+				terraformv1alphav1.ConfigurationUpstreamLabel: state.upstream,
+				terraformv1alphav1.ConfigurationDriftLabel:    remediation,
+			},
+			EnableInfraCosts:   c.EnableInfracosts,
+			EnableStateLocking: c.EnableStateLocking,
+			ExecutorImage:      c.ExecutorImage,
+			BackendSecret:      state.backendSecret,
+			ExecutorSecrets:    c.ExecutorSecrets,
+			InfracostsImage:    c.InfracostsImage,
+			InfracostsSecret:   c.InfracostsSecretName,
+			Module:             state.module,
+			Namespace:          c.ControllerNamespace,
+			PolicyConstraint:   state.checkovConstraint,
+			PolicyImage:        c.PolicyImage,
+			StateLockTimeout:   c.StateLockTimeout,
+			Template:           state.jobTemplate,
+			TerraformImage:     GetTerraformImage(configuration, c.TerraformImage),
 		}
 
 		// @step: use the options to generate the job
@@ -478,19 +821,13 @@ func (c *Controller) ensureTerraformPlan(configuration *terraformv1alphav1.Confi
 			return reconcile.Result{}, err
 		}
 
-		// @step: search for any current jobs
-		job, found := filters.Jobs(state.jobs).
-			WithGeneration(generation).
-			WithLabel(terraformv1alphav1.DriftAnnotation, configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]).
-			WithName(configuration.GetName()).
-			WithNamespace(configuration.GetNamespace()).
-			WithStage(terraformv1alphav1.StageTerraformPlan).
-			WithUID(string(configuration.GetUID())).
-			Latest()
-
 		if !found {
-			// @step: if auto approval is not enabled we should annotate the configuration with the need to approve.
-			if !configuration.Spec.EnableAutoApproval && !configuration.NeedsApproval() {
+			// @step: if auto approval is not enabled, or the plan includes operations, we should annotate the configuration
+			// with the need to approve. Any previous approval is also reset, as it was given for a different plan
+			// @note: plan only configurations are never applied, so there is nothing to approve
+			// @note: a plan remediating drift is approved according to the drift remediation mode
+			if !configuration.Spec.PlanOnly && !configuration.NeedsApproval() && !configuration.IsRemediatingDrift() &&
+				(!configuration.Spec.EnableAutoApproval || configuration.HasApproval() || configuration.HasOperations()) {
 				original := configuration.DeepCopy()
 				if configuration.Annotations == nil {
 					configuration.Annotations = map[string]string{}
@@ -506,6 +843,32 @@ func (c *Controller) ensureTerraformPlan(configuration *terraformv1alphav1.Confi
 				return controller.RequeueImmediate, nil
 			}
 
+			// @step: a plan remediating drift cannot be covered by an approval left over from a previous plan, so
+			// the approval is removed; the remediation mode and the apply gates then decide if a new one is required
+			if !configuration.Spec.PlanOnly && configuration.IsRemediatingDrift() && configuration.HasApproval() {
+				original := configuration.DeepCopy()
+				delete(configuration.Annotations, terraformv1alphav1.ApplyAnnotation)
+
+				if err := c.cc.Patch(ctx, configuration, client.MergeFrom(original)); err != nil {
+					cond.Failed(err, "Failed to create or update the terraform configuration")
+
+					return reconcile.Result{}, err
+				}
+
+				return controller.RequeueImmediate, nil
+			}
+
+			// @step: the job is queued if the concurrency quotas have been reached
+			if position, err := c.acquireJobSlot(ctx, configuration, state); err != nil {
+				cond.Failed(err, "Failed to check the concurrency quotas for terraform jobs")
+
+				return reconcile.Result{}, err
+			} else if position > 0 {
+				cond.InProgress("Queued (position %d), waiting for other terraform jobs to complete", position)
+
+				return reconcile.Result{RequeueAfter: queueRequeueInterval}, nil
+			}
+
 			if c.EnableWatchers {
 				// @step: create a watch job in the configuration namespace to allow the user to witness
 				// the terraform output
@@ -517,11 +880,16 @@ func (c *Controller) ensureTerraformPlan(configuration *terraformv1alphav1.Confi
 			}
 
 			// @step: create the terraform plan job
-			if err := c.cc.Create(ctx, runner); err != nil {
+			if err := c.createJob(ctx, runner); err != nil {
 				cond.Failed(err, "Failed to create the terraform plan job")
 
 				return reconcile.Result{}, err
 			}
+			configuration.Status.Operations = nil
+			if configuration.HasOperations() {
+				configuration.Status.Operations = configuration.Spec.Operations.DeepCopy()
+			}
+			configuration.Status.Retry = c.newRetryStatus(configuration, terraformv1alphav1.StageTerraformPlan, attempt)
 			cond.InProgress("Terraform plan in progress")
 
 			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
@@ -530,14 +898,10 @@ func (c *Controller) ensureTerraformPlan(configuration *terraformv1alphav1.Confi
 		// @step: we only shift out of this state of the job is complete
 		switch {
 		case jobs.IsComplete(job):
+			configuration.Status.StateLock = nil
 			cond.Success("Terraform plan is complete")
 			return reconcile.Result{}, nil
 
-		case jobs.IsFailed(job):
-			cond.Failed(nil, "Terraform plan is failed")
-
-			return c.ensureErrorDetection(configuration, job, state)(ctx)
-
 		case jobs.IsActive(job):
 			cond.InProgress("Terraform plan is running")
 		}
@@ -546,8 +910,46 @@ func (c *Controller) ensureTerraformPlan(configuration *terraformv1alphav1.Confi
 	}
 }
 
+// ensureTerraformPlanStatus is responsible for summarising the saved terraform plan onto the configuration status
+func (c *Controller) ensureTerraformPlanStatus(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
+	cond := controller.ConditionMgr(configuration, terraformv1alphav1.ConditionTerraformPlan, c.recorder)
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		secret := &v1.Secret{}
+		secret.Namespace = c.ControllerNamespace
+		secret.Name = configuration.GetTerraformPlanSecretName()
+
+		found, err := kubernetes.GetIfExists(ctx, c.cc, secret)
+		if err != nil {
+			cond.Failed(err, "Failed to retrieve the saved terraform plan")
+
+			return reconcile.Result{}, err
+		}
+		if !found || len(secret.Data[terraformv1alphav1.TerraformPlanJSONSecretKey]) == 0 {
+			return reconcile.Result{}, nil
+		}
+
+		// @step: we only summarise the plan if it was produced for this generation
+		generation := configuration.GetGeneration()
+		if secret.GetLabels()[terraformv1alphav1.ConfigurationGenerationLabel] != fmt.Sprintf("%d", generation) {
+			return reconcile.Result{}, nil
+		}
+
+		plan, err := terraform.DecodePlan(secret.Data[terraformv1alphav1.TerraformPlanJSONSecretKey])
+		if err != nil {
+			cond.ActionRequired("Failed to decode the terraform plan output")
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+		configuration.Status.Plan = NewPlanStatus(plan, generation)
+		state.plan = plan
+
+		return reconcile.Result{}, nil
+	}
+}
+
 // ensureCostStatus is responsible for updating the cost status post a plan
-func (c *Controller) ensureCostStatus(configuration *terraformv1alphav1.Configuration) controller.EnsureFunc {
+func (c *Controller) ensureCostStatus(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
 	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
 	labels := []string{configuration.GetNamespace(), configuration.GetName()}
 
@@ -602,6 +1004,12 @@ func (c *Controller) ensureCostStatus(configuration *terraformv1alphav1.Configur
 			values[key] = cost
 		}
 
+		// @step: notify the sinks when the estimated monthly cost has increased since the last plan
+		if previous, found := parseMonthlyCost(configuration.Status.Costs); found && values["totalMonthlyCost"] > previous {
+			c.notify(ctx, configuration, state, terraformv1alphav1.NotificationCostIncreased,
+				"Estimated monthly cost has increased from $%v to $%v", previous, values["totalMonthlyCost"])
+		}
+
 		configuration.Status.Costs = &terraformv1alphav1.CostStatus{
 			Enabled: true,
 			Hourly:  fmt.Sprintf("$%v", values["totalHourlyCost"]),
@@ -709,7 +1117,10 @@ func (c *Controller) ensurePolicyStatus(configuration *terraformv1alphav1.Config
 		}
 
 		if checksFailed.Int() > 0 {
-			cond.ActionRequired("Configuration has failed security policy, refusing to continue")
+			if hasTransitioned(cond, func() { cond.ActionRequired("Configuration has failed security policy, refusing to continue") }) {
+				c.notify(ctx, configuration, state, terraformv1alphav1.NotificationPolicyFailed,
+					"Configuration has failed security policy, %d check(s) failed", checksFailed.Int())
+			}
 
 			return reconcile.Result{}, controller.ErrIgnore
 		}
@@ -720,135 +1131,416 @@ func (c *Controller) ensurePolicyStatus(configuration *terraformv1alphav1.Config
 	}
 }
 
-// ensureDriftDetection is responsible for checking for drift in the terraform state
+// ensureDriftDetection is responsible for checking for drift in the terraform state. A refresh-only terraform plan
+// is run for each drift check, the exit code of which indicates if the resources have drifted. When drift is found
+// a terraform plan is also run, classifying the drift into changes the configuration would correct or not
 func (c *Controller) ensureDriftDetection(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
 	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
+	apply := controller.ConditionMgr(configuration, terraformv1alphav1.ConditionTerraformApply, c.recorder)
 	generation := fmt.Sprintf("%d", configuration.GetGeneration())
 
 	return func(ctx context.Context) (reconcile.Result, error) {
+		drift := configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]
+
 		switch {
 		// if drift detection is not enable, we ignore
 		case !configuration.Spec.EnableDriftDetection:
 			return reconcile.Result{}, nil
 		// if the annotation is not set, we ignore
-		case configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation] == "":
+		case drift == "":
 			return reconcile.Result{}, nil
 			// if the annotation is the same as the last drift timestamp, we ignore
-		case configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation] == configuration.Status.DriftTimestamp:
+		case drift == configuration.Status.DriftTimestamp:
 			return reconcile.Result{}, nil
 		}
-		// @note: everytime we run a drift we update the timestamp on the status, this is used to ensure we don't
-		// try and rerun the drift. We should remove the annotation from the configuration but that has issues as it
-		// updates the resourceVersion which make updating the status conflict.
-		configuration.Status.DriftTimestamp = configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]
 
-		// @step: we need retrieve the logs and check for the drift
+		// @step: search for the drift job for this drift check
 		job, found := filters.Jobs(state.jobs).
 			WithGeneration(generation).
-			WithLabel(terraformv1alphav1.DriftAnnotation, configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]).
+			WithLabel(terraformv1alphav1.ConfigurationDriftLabel, drift).
 			WithName(configuration.GetName()).
 			WithNamespace(configuration.GetNamespace()).
-			WithStage(terraformv1alphav1.StageTerraformPlan).
+			WithStage(terraformv1alphav1.StageTerraformDrift).
 			WithUID(string(configuration.GetUID())).
 			Latest()
+
 		if !found {
-			log.WithFields(log.Fields{
-				"name":      configuration.GetName(),
-				"namespace": configuration.GetNamespace(),
-			}).Warn("no terraform plan job found to check drift")
+			runner, err := jobs.New(configuration, state.provider).NewTerraformDrift(jobs.Options{
+				AdditionalLabels:   map[string]string{terraformv1alphav1.ConfigurationDriftLabel: drift},
+				BackendSecret:      state.backendSecret,
+				EnableStateLocking: c.EnableStateLocking,
+				ExecutorImage:      c.ExecutorImage,
+				ExecutorSecrets:    c.ExecutorSecrets,
+				Module:             state.module,
+				Namespace:          c.ControllerNamespace,
+				StateLockTimeout:   c.StateLockTimeout,
+				Template:           state.jobTemplate,
+				TerraformImage:     GetTerraformImage(configuration, c.TerraformImage),
+			})
+			if err != nil {
+				cond.Failed(err, "Failed to create the terraform drift job")
+
+				return reconcile.Result{}, err
+			}
+
+			// @step: the job is queued if the concurrency quotas have been reached
+			if position, err := c.acquireJobSlot(ctx, configuration, state); err != nil {
+				cond.Failed(err, "Failed to check the concurrency quotas for terraform jobs")
+
+				return reconcile.Result{}, err
+			} else if position > 0 {
+				cond.InProgress("Queued (position %d), waiting for other terraform jobs to complete", position)
+
+				return reconcile.Result{RequeueAfter: queueRequeueInterval}, nil
+			}
+
+			if c.EnableWatchers {
+				if err := c.CreateWatcher(ctx, configuration, terraformv1alphav1.StageTerraformDrift); err != nil {
+					cond.Failed(err, "Failed to create the terraform drift watcher")
+
+					return reconcile.Result{}, err
+				}
+			}
+
+			if err := c.createJob(ctx, runner); err != nil {
+				cond.Failed(err, "Failed to create the terraform drift job")
+
+				return reconcile.Result{}, err
+			}
+			cond.InProgress("Terraform drift check in progress")
+
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		switch {
+		case jobs.IsFailed(job):
+			// @note: a failed check is not retried, the next check will be triggered by the drift schedule
+			configuration.Status.DriftTimestamp = drift
+			c.recorder.Event(configuration, v1.EventTypeWarning, "DriftDetection", "Terraform drift check has failed, check the logs of the drift job")
 
 			return reconcile.Result{}, nil
+
+		case !jobs.IsComplete(job):
+			cond.InProgress("Terraform drift check is running")
+
+			return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
 		}
 
-		// @step: retrive a list of pods related to the job
-		pods := &v1.PodList{}
-		filters := client.MatchingLabels{"job-name": job.GetName()}
-		if err := c.cc.List(ctx, pods, client.InNamespace(c.ControllerNamespace), filters); err != nil {
-			cond.Failed(err, "Failed to list the terraform plan pods")
+		// @note: everytime we run a drift we update the timestamp on the status, this is used to ensure we don't
+		// try and rerun the drift. We should remove the annotation from the configuration but that has issues as it
+		// updates the resourceVersion which make updating the status conflict.
+		configuration.Status.DriftTimestamp = drift
+
+		// @step: retrieve the output of the refresh-only plan
+		secret := &v1.Secret{}
+		secret.Namespace = c.ControllerNamespace
+		secret.Name = configuration.GetTerraformDriftPlanSecretName()
+
+		found, err := kubernetes.GetIfExists(ctx, c.cc, secret)
+		if err != nil {
+			cond.Failed(err, "Failed to retrieve the terraform drift check output")
 
 			return reconcile.Result{}, err
 		}
-		if len(pods.Items) == 0 {
+		if !found || secret.GetLabels()[terraformv1alphav1.ConfigurationDriftLabel] != drift {
+			c.recorder.Event(configuration, v1.EventTypeWarning, "DriftDetection", "Terraform drift check did not produce any output")
+
 			return reconcile.Result{}, nil
 		}
 
-		// @step: scan the logs for updates or changes
-		latest := kubernetes.FindLatestPod(pods)
-		stream, err := c.kc.CoreV1().Pods(latest.Namespace).GetLogs(latest.Name, &v1.PodLogOptions{
-			Container: "terraform",
-			Follow:    false,
-		}).Stream(ctx)
-		if err != nil {
-			cond.Failed(err, "Failed to retrieve the terraform plan logs from pod")
+		// @step: the exit code of terraform plan -detailed-exitcode is 2 when changes are present
+		switch code := strings.TrimSpace(string(secret.Data[terraformv1alphav1.TerraformDriftExitCodeSecretKey])); code {
+		case "0":
+			state.hasDrift = false
+		case "2":
+			state.hasDrift = true
+		default:
+			c.recorder.Eventf(configuration, v1.EventTypeWarning, "DriftDetection", "Terraform drift check returned an unexpected exit code %q", code)
 
-			return reconcile.Result{}, err
+			return reconcile.Result{}, nil
 		}
-		defer stream.Close()
 
-		// @step: check for changes in the plan
-		state.hasDrift, err = terraform.FindChangesInLogs(stream)
-		if err != nil {
-			cond.Failed(err, "Failed to find the changes in the terraform plan logs")
+		// @step: produce the drift report from the json output of the refresh-only plan, classified against
+		// the changes of the terraform plan run alongside it
+		// @note: the plan is missing from the output of drift checks run by an older version of the controller
+		var plan *terraform.Plan
+		if encoded, found := secret.Data[terraformv1alphav1.TerraformDriftConfigSecretKey]; found {
+			if plan, err = terraform.DecodePlan(encoded); err != nil {
+				log.WithError(err).WithFields(log.Fields{
+					"name":      configuration.GetName(),
+					"namespace": configuration.GetNamespace(),
+				}).Warn("failed to decode the terraform plan of the drift check")
+			}
+		}
 
-			return reconcile.Result{}, err
+		configuration.Status.Drift = nil
+		if refresh, err := terraform.DecodePlan(secret.Data[terraformv1alphav1.TerraformPlanJSONSecretKey]); err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"name":      configuration.GetName(),
+				"namespace": configuration.GetNamespace(),
+			}).Warn("failed to decode the terraform drift check output")
+		} else {
+			report := terraform.NewDriftReport(refresh, plan)
+			if err := c.createDriftReport(ctx, configuration, report); err != nil {
+				cond.Failed(err, "Failed to create or update the terraform drift report")
+
+				return reconcile.Result{}, err
+			}
+			configuration.Status.Drift = NewDriftStatus(report)
+			configuration.Status.Drift.CheckedAt = &metav1.Time{Time: time.Now()}
+			configuration.Status.Drift.ReportSecret = configuration.GetTerraformDriftSecretName()
 		}
 
 		// @step: handle the update to the status
 		if !state.hasDrift {
 			configuration.Status.ResourceStatus = terraformv1alphav1.ResourcesInSync
-		} else {
-			configuration.Status.ResourceStatus = terraformv1alphav1.ResourcesOutOfSync
 
-			cond.ActionRequired("Drift has been detected in the resource")
+			return controller.RequeueImmediate, nil
+		}
+		configuration.Status.ResourceStatus = terraformv1alphav1.ResourcesOutOfSync
+
+		message := formatDrift(configuration)
+		if configuration.Status.Drift == nil {
+			configuration.Status.Drift = &terraformv1alphav1.DriftStatus{}
+		}
+		c.notify(ctx, configuration, state, terraformv1alphav1.NotificationDriftDetected, "%s", message)
+
+		// @step: the drift is remediated by an apply of the current generation, unless disabled
+		switch configuration.GetDriftRemediation() {
+		case terraformv1alphav1.DriftRemediationAuto:
+			configuration.Status.Drift.Remediation = terraformv1alphav1.RemediationPending
+			apply.InProgress("Terraform apply is pending to remediate the drift")
+			c.recorder.Eventf(configuration, v1.EventTypeNormal, "DriftRemediation", "%s, applying the configuration to remediate", message)
+
+		case terraformv1alphav1.DriftRemediationApproval:
+			if err := c.revokeApproval(ctx, configuration); err != nil {
+				cond.Failed(err, "Failed to update the approval on the configuration")
+
+				return reconcile.Result{}, err
+			}
+			configuration.Status.Drift.Remediation = terraformv1alphav1.RemediationPending
+			apply.ActionRequired("%s, waiting for terraform apply annotation to be set to true", message)
+
+		default:
+			cond.ActionRequired("%s", message)
 		}
 
 		return controller.RequeueImmediate, nil
 	}
 }
 
+// ensureTerraformPlanOnly is responsible for stopping plan only configurations before the apply, reporting
+// if applying the plan would result in any changes
+func (c *Controller) ensureTerraformPlanOnly(configuration *terraformv1alphav1.Configuration) controller.EnsureFunc {
+	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
+	apply := controller.ConditionMgr(configuration, terraformv1alphav1.ConditionTerraformApply, c.recorder)
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		if !configuration.Spec.PlanOnly {
+			return reconcile.Result{}, nil
+		}
+		apply.Warning("Configuration is plan only, terraform apply is disabled")
+
+		plan := configuration.Status.Plan
+		switch {
+		case plan == nil || plan.Generation != configuration.GetGeneration():
+			cond.Success("Plan only, terraform plan is complete")
+		case plan.HasChanges:
+			configuration.Status.ResourceStatus = terraformv1alphav1.ResourcesOutOfSync
+			cond.Success("Plan only, terraform plan has changes (%s)", plan.Summary)
+		default:
+			configuration.Status.ResourceStatus = terraformv1alphav1.ResourcesInSync
+			cond.Success("Plan only, terraform plan has no changes")
+		}
+
+		return reconcile.Result{}, controller.ErrIgnore
+	}
+}
+
 // ensureTerraformApply is responsible for ensuring the terraform apply is running or run
 func (c *Controller) ensureTerraformApply(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
 	cond := controller.ConditionMgr(configuration, terraformv1alphav1.ConditionTerraformApply, c.recorder)
 	generation := fmt.Sprintf("%d", configuration.GetGeneration())
 
+	// awaitingApproval sets the condition, notifying the sinks when the plan first starts waiting on approval
+	awaitingApproval := func(ctx context.Context, message string, args ...interface{}) {
+		if hasTransitioned(cond, func() { cond.ActionRequired(message, args...) }) {
+			c.notify(ctx, configuration, state, terraformv1alphav1.NotificationPlanAwaitingApproval, message, args...)
+		}
+	}
+
 	return func(ctx context.Context) (reconcile.Result, error) {
 		switch {
-		case cond.GetCondition().IsComplete(configuration.GetGeneration()):
+		case cond.GetCondition().IsComplete(configuration.GetGeneration()) && state.upstream == configuration.Status.UpstreamChecksum &&
+			!configuration.IsRemediatingDrift():
 			return reconcile.Result{}, nil
 
+		case configuration.IsRemediatingDrift() && configuration.GetDriftRemediation() == terraformv1alphav1.DriftRemediationApproval &&
+			!configuration.HasApproval():
+			awaitingApproval(ctx, "%s, waiting for terraform apply annotation to be set to true", formatDrift(configuration))
+			return reconcile.Result{}, controller.ErrIgnore
+
+		case configuration.NeedsApproval() && configuration.HasOperations():
+			awaitingApproval(ctx, "Terraform plan includes operations (%s), waiting for terraform apply annotation to be set to true",
+				formatOperations(configuration.Spec.Operations))
+			return reconcile.Result{}, controller.ErrIgnore
+
 		case configuration.NeedsApproval() && !configuration.Spec.EnableAutoApproval:
-			cond.ActionRequired("Waiting for terraform apply annotation to be set to true")
+			awaitingApproval(ctx, "Waiting for terraform apply annotation to be set to true")
 			return reconcile.Result{}, controller.ErrIgnore
 		}
 
-		// @step: create the terraform job
-		runner, err := jobs.New(configuration, state.provider).NewTerraformApply(jobs.Options{
-			EnableInfraCosts: c.EnableInfracosts,
-			ExecutorImage:    c.ExecutorImage,
-			ExecutorSecrets:  c.ExecutorSecrets,
-			InfracostsImage:  c.InfracostsImage,
-			InfracostsSecret: c.InfracostsSecretName,
-			Namespace:        c.ControllerNamespace,
-			Template:         state.jobTemplate,
-			TerraformImage:   GetTerraformImage(configuration, c.TerraformImage),
-		})
-		if err != nil {
-			cond.Failed(err, "Failed to create the terraform apply job")
-
-			return reconcile.Result{}, err
+		// @step: an apply remediating drift is labelled with the drift check it remediates
+		var remediation string
+		if configuration.IsRemediatingDrift() {
+			remediation = configuration.Status.DriftTimestamp
 		}
 
 		// @step: find the job which is implementing this stage if any
 		job, found := filters.Jobs(state.jobs).
 			WithGeneration(generation).
+			WithLabel(terraformv1alphav1.ConfigurationResetLabel, formatPlanResets(configuration)).
+			WithLabel(terraformv1alphav1.ConfigurationUpstreamLabel, state.upstream).
+			WithLabel(terraformv1alphav1.ConfigurationDriftLabel, remediation).
 			WithNamespace(configuration.GetNamespace()).
 			WithName(configuration.GetName()).
 			WithStage(terraformv1alphav1.StageTerraformApply).
 			WithUID(string(configuration.GetUID())).
 			Latest()
 
+		// @step: a failed apply is attempted again for the same generation once the backoff has passed
+		attempt := 1
+		if found && jobs.IsFailed(job) {
+			// @step: terraform refuses to apply a saved plan if the state has moved on since it was produced
+			stale, err := c.findStalePlanInJob(ctx, job)
+			if err != nil {
+				log.WithError(err).WithField("job", job.GetName()).Warn("failed to check the terraform apply logs for a stale plan")
+			}
+			if stale {
+				return c.ensureTerraformPlanReset(configuration, state, "Saved terraform plan was stale at apply, a new plan is required")(ctx)
+			}
+			if hasTransitioned(cond, func() { cond.Failed(nil, "Terraform apply has failed") }) {
+				c.notify(ctx, configuration, state, terraformv1alphav1.NotificationApplyFailed, "Terraform apply has failed, attempt %d", jobs.Attempt(job))
+			}
+
+			result, err := c.ensureErrorDetection(configuration, job, state,
+				c.ensureTerraformRetry(configuration, job, terraformv1alphav1.StageTerraformApply))(ctx)
+			if err != nil || result != (reconcile.Result{}) {
+				return result, err
+			}
+			found, attempt = false, jobs.Attempt(job)+1
+		}
+
+		// @step: create the terraform job
+		runner, err := jobs.New(configuration, state.provider).NewTerraformApply(jobs.Options{
+			AdditionalLabels: map[string]string{
+				terraformv1alphav1.ConfigurationAttemptLabel:  strconv.Itoa(attempt),
+				terraformv1alphav1.ConfigurationResetLabel:    formatPlanResets(configuration),
+				terraformv1alphav1.ConfigurationUpstreamLabel: state.upstream,
+				terraformv1alphav1.ConfigurationDriftLabel:    remediation,
+			},
+			EnableInfraCosts:   c.EnableInfracosts,
+			EnableStateLocking: c.EnableStateLocking,
+			ExecutorImage:      c.ExecutorImage,
+			BackendSecret:      state.backendSecret,
+			ExecutorSecrets:    c.ExecutorSecrets,
+			InfracostsImage:    c.InfracostsImage,
+			InfracostsSecret:   c.InfracostsSecretName,
+			Module:             state.module,
+			Namespace:          c.ControllerNamespace,
+			StateLockTimeout:   c.StateLockTimeout,
+			Template:           state.jobTemplate,
+			TerraformImage:     GetTerraformImage(configuration, c.TerraformImage),
+		})
+		if err != nil {
+			cond.Failed(err, "Failed to create the terraform apply job")
+
+			return reconcile.Result{}, err
+		}
+
 		// @step: we can requeue or move on depending on the status
 		if !found {
+			// @step: we only ever apply the saved plan which was produced (and approved) for this generation
+			current, err := c.hasCurrentTerraformPlan(ctx, configuration)
+			if err != nil {
+				cond.Failed(err, "Failed to retrieve the saved terraform plan")
+
+				return reconcile.Result{}, err
+			}
+			if !current {
+				return c.ensureTerraformPlanReset(configuration, state, "Saved terraform plan is missing or stale, a new plan is required")(ctx)
+			}
+
+			// @step: destroying or replacing protected resources always requires an approval
+			protected, err := c.findDestroyProtectedResources(ctx, configuration, state)
+			if err != nil {
+				cond.Failed(err, "Failed to check the terraform plan against the destroy protection")
+
+				return reconcile.Result{}, err
+			}
+			if len(protected) > 0 && !configuration.HasApproval() {
+				if err := c.revokeApproval(ctx, configuration); err != nil {
+					cond.Failed(err, "Failed to update the approval on the configuration")
+
+					return reconcile.Result{}, err
+				}
+				awaitingApproval(ctx, "Terraform plan destroys or replaces protected resources (%s), waiting for terraform apply annotation to be set to true",
+					utils.Truncate(protected, 5))
+
+				return reconcile.Result{}, controller.ErrIgnore
+			}
+
+			// @step: resources we expected to import should never be created
+			creates, unexpected := findUnexpectedCreates(configuration, state)
+			if len(unexpected) > 0 {
+				cond.ActionRequired("Terraform plan creates resources which should have been imported (%s), check the spec.imports",
+					utils.Truncate(unexpected, 5))
+
+				return reconcile.Result{}, controller.ErrIgnore
+			}
+			if len(creates) > 0 && !configuration.HasApproval() {
+				if err := c.revokeApproval(ctx, configuration); err != nil {
+					cond.Failed(err, "Failed to update the approval on the configuration")
+
+					return reconcile.Result{}, err
+				}
+				awaitingApproval(ctx, "Terraform plan creates resources not found in the imported state (%s), waiting for terraform apply annotation to be set to true",
+					utils.Truncate(creates, 5))
+
+				return reconcile.Result{}, controller.ErrIgnore
+			}
+
+			// @step: an approved apply is held until the apply window is open
+			window, err := c.findApplyWindow(ctx, configuration, state)
+			if err != nil {
+				cond.Failed(err, "Failed to evaluate the apply window for the configuration")
+
+				return reconcile.Result{}, err
+			}
+			if window != nil {
+				if open, next := window.IsOpen(time.Now()); !open {
+					if next.IsZero() {
+						cond.ActionRequired("Apply window never opens, check the schedule of the apply window")
+
+						return reconcile.Result{}, controller.ErrIgnore
+					}
+					cond.InProgress("Waiting for the apply window to open at %s", next.Format(time.RFC3339))
+
+					return reconcile.Result{RequeueAfter: time.Until(next)}, nil
+				}
+			}
+
+			// @step: the job is queued if the concurrency quotas have been reached
+			if position, err := c.acquireJobSlot(ctx, configuration, state); err != nil {
+				cond.Failed(err, "Failed to check the concurrency quotas for terraform jobs")
+
+				return reconcile.Result{}, err
+			} else if position > 0 {
+				cond.InProgress("Queued (position %d), waiting for other terraform jobs to complete", position)
+
+				return reconcile.Result{RequeueAfter: queueRequeueInterval}, nil
+			}
+
 			configuration.Status.ResourceStatus = terraformv1alphav1.ResourcesOutOfSync
 
 			if c.EnableWatchers {
@@ -860,11 +1552,12 @@ func (c *Controller) ensureTerraformApply(configuration *terraformv1alphav1.Conf
 			}
 
 			// @step: create the job for terraform apply
-			if err := c.cc.Create(ctx, runner); err != nil {
+			if err := c.createJob(ctx, runner); err != nil {
 				cond.Failed(err, "Failed to create the terraform apply job")
 
 				return reconcile.Result{}, err
 			}
+			configuration.Status.Retry = c.newRetryStatus(configuration, terraformv1alphav1.StageTerraformApply, attempt)
 			cond.InProgress("Terraform apply is running")
 
 			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
@@ -873,16 +1566,28 @@ func (c *Controller) ensureTerraformApply(configuration *terraformv1alphav1.Conf
 		// @step: we only shift out of this state of the job is complete
 		switch {
 		case jobs.IsComplete(job):
+			// @step: the operations are one-shot, so we remove them once they have been applied
+			if configuration.HasOperations() {
+				if err := c.clearOperations(ctx, configuration); err != nil {
+					cond.Failed(err, "Failed to remove the applied operations from the configuration")
+
+					return reconcile.Result{}, err
+				}
+			}
+			if configuration.IsRemediatingDrift() {
+				configuration.Status.Drift.Remediation = terraformv1alphav1.RemediationApplied
+				c.recorder.Event(configuration, v1.EventTypeNormal, "DriftRemediation", "Drift has been remediated by the terraform apply")
+			}
+			configuration.Status.Operations = nil
 			configuration.Status.ResourceStatus = terraformv1alphav1.ResourcesInSync
+			configuration.Status.StateLock = nil
+			configuration.Status.UpstreamChecksum = state.upstream
 
-			cond.Success("Terraform apply is complete")
+			if hasTransitioned(cond, func() { cond.Success("Terraform apply is complete") }) {
+				c.notify(ctx, configuration, state, terraformv1alphav1.NotificationApplySucceeded, "Terraform apply is complete")
+			}
 			return reconcile.Result{}, nil
 
-		case jobs.IsFailed(job):
-			cond.Failed(nil, "Terraform apply has failed")
-
-			return c.ensureErrorDetection(configuration, job, state)(ctx)
-
 		case jobs.IsActive(job):
 			cond.InProgress("Terraform apply in progress")
 		}
@@ -891,36 +1596,74 @@ func (c *Controller) ensureTerraformApply(configuration *terraformv1alphav1.Conf
 	}
 }
 
+// ensureTerraformPlanReset is responsible for discarding the plan and apply jobs for the current generation. This
+// forces a new terraform plan to be produced, and approved if required, before anything is applied
+func (c *Controller) ensureTerraformPlanReset(configuration *terraformv1alphav1.Configuration, state *state, message string) controller.EnsureFunc {
+	cond := controller.ConditionMgr(configuration, terraformv1alphav1.ConditionTerraformPlan, c.recorder)
+	generation := fmt.Sprintf("%d", configuration.GetGeneration())
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		list, _ := filters.Jobs(state.jobs).
+			WithGeneration(generation).
+			WithName(configuration.GetName()).
+			WithNamespace(configuration.GetNamespace()).
+			WithUID(string(configuration.GetUID())).
+			List()
+
+		for i := 0; i < len(list.Items); i++ {
+			switch list.Items[i].GetLabels()[terraformv1alphav1.ConfigurationStageLabel] {
+			case terraformv1alphav1.StageTerraformPlan, terraformv1alphav1.StageTerraformApply:
+			default:
+				continue
+			}
+
+			err := c.cc.Delete(ctx, &list.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground))
+			if client.IgnoreNotFound(err) != nil {
+				cond.Failed(err, "Failed to delete the terraform job (%s/%s)", list.Items[i].Namespace, list.Items[i].Name)
+
+				return reconcile.Result{}, err
+			}
+		}
+
+		// @step: the jobs of the replacement plan are labelled with the reset, so they are named differently
+		// and never confused with the jobs above while they are being removed
+		configuration.Status.PlanResets++
+
+		// @step: any approval given was for the previous plan, so we need to ask again
+		if !configuration.Spec.EnableAutoApproval || configuration.HasApproval() {
+			if err := c.revokeApproval(ctx, configuration); err != nil {
+				cond.Failed(err, "Failed to reset the approval on the configuration")
+
+				return reconcile.Result{}, err
+			}
+		}
+		cond.Warning(message)
+
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+}
+
 // ensureConnectionSecret is responsible for ensuring the jobs ran successfully
 func (c *Controller) ensureConnectionSecret(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
 	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
 
 	return func(ctx context.Context) (reconcile.Result, error) {
-		secret := &v1.Secret{}
-		secret.Name = configuration.GetTerraformStateSecretName()
-		secret.Namespace = c.ControllerNamespace
-
-		found, err := kubernetes.GetIfExists(ctx, c.cc, secret)
+		// @step: retrieve the terraform state from the backend
+		tfstate, err := state.backend.GetState(ctx)
 		if err != nil {
-			cond.Failed(err, "Failed to get terraform state secret (%s/%s)", c.ControllerNamespace, secret.Name)
+			cond.Failed(err, "Failed to retrieve the terraform state from the %s backend", state.backend.Type())
 
 			return reconcile.Result{}, err
 		}
-		if !found {
-			cond.Failed(nil, "Terraform state secret (%s/%s) not found", c.ControllerNamespace, secret.Name)
+		if tfstate == nil {
+			cond.Failed(nil, "Terraform state not found in the %s backend", state.backend.Type())
 
 			return reconcile.Result{}, controller.ErrIgnore
 		}
-		state.tfstate = secret
+		state.tfstate = tfstate
 
 		if configuration.Spec.WriteConnectionSecretToRef != nil {
-			// @step: decode the terraform state (essentially just returning the uncompressed content)
-			state, err := terraform.DecodeState(secret.Data[terraformv1alphav1.TerraformStateSecretKey])
-			if err != nil {
-				cond.Failed(err, "Failed to decode the terraform state")
-
-				return reconcile.Result{}, err
-			}
+			state := tfstate
 
 			// @step: check if we have any module outputs and if found, we convert the outputs to a
 			// kubernetes secret
@@ -978,14 +1721,8 @@ func (c *Controller) ensureTerraformStatus(configuration *terraformv1alphav1.Con
 	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
 
 	return func(ctx context.Context) (reconcile.Result, error) {
-		tfstate, err := terraform.DecodeState(state.tfstate.Data[terraformv1alphav1.TerraformStateSecretKey])
-		if err != nil {
-			cond.Failed(err, "Failed to decode the terraform state")
-
-			return reconcile.Result{}, err
-		}
-		configuration.Status.Resources = tfstate.CountResources()
-		configuration.Status.TerraformVersion = tfstate.TerraformVersion
+		configuration.Status.Resources = state.tfstate.CountResources()
+		configuration.Status.TerraformVersion = state.tfstate.TerraformVersion
 
 		switch configuration.Status.ResourceStatus {
 		case terraformv1alphav1.ResourcesInSync:
@@ -18,13 +18,31 @@
 package configuration
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
 	"strings"
 
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/controller"
 	"github.com/appvia/terraform-controller/pkg/utils/jobs"
 	"github.com/appvia/terraform-controller/pkg/utils/kubernetes"
+	"github.com/appvia/terraform-controller/pkg/utils/policies"
+	"github.com/appvia/terraform-controller/pkg/utils/schedule"
+	"github.com/appvia/terraform-controller/pkg/utils/terraform"
 )
 
 // checkovPolicyTemplate is the default template used to produce a checkov configuration
@@ -51,6 +69,120 @@ external-checks-dir:
   {{- end }}
 {{- end }}`
 
+// maxPlanResources is the maximum number of resources recorded on the plan status
+const maxPlanResources = 50
+
+// NewPlanStatus is called to produce a summary of the terraform plan for the configuration status
+func NewPlanStatus(plan *terraform.Plan, generation int64) *terraformv1alphav1.PlanStatus {
+	status := &terraformv1alphav1.PlanStatus{Generation: generation}
+
+	for i := 0; i < len(plan.ResourceChanges); i++ {
+		change := plan.ResourceChanges[i]
+		if change.Mode == "data" {
+			continue
+		}
+
+		action := change.Action()
+		if change.IsImporting() {
+			status.Import++
+			if action == "no-op" {
+				action = "import"
+			}
+		}
+
+		switch action {
+		case "create":
+			status.Add++
+		case "update":
+			status.Change++
+		case "delete":
+			status.Destroy++
+		case "replace":
+			status.Replace++
+		case "import":
+			// @note: already counted, the resource is imported without any further changes
+		default:
+			continue
+		}
+
+		if len(status.Resources) >= maxPlanResources {
+			status.Truncated = true
+
+			continue
+		}
+		status.Resources = append(status.Resources, terraformv1alphav1.PlanResource{
+			Action:  action,
+			Address: change.Address,
+		})
+	}
+
+	var summary []string
+	for _, x := range []struct {
+		Count int
+		Label string
+	}{
+		{status.Import, "to import"},
+		{status.Add, "to add"},
+		{status.Change, "to change"},
+		{status.Destroy, "to destroy"},
+		{status.Replace, "to replace"},
+	} {
+		if x.Count > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", x.Count, x.Label))
+		}
+	}
+	status.Summary = "No changes"
+	if len(summary) > 0 {
+		status.HasChanges = true
+		status.Summary = strings.Join(summary, ", ")
+	}
+
+	return status
+}
+
+// NewDriftStatus is called to produce a summary of the drift report for the configuration status
+func NewDriftStatus(report *terraform.DriftReport) *terraformv1alphav1.DriftStatus {
+	status := &terraformv1alphav1.DriftStatus{
+		ConfigChanges:      report.Count(terraform.DriftKindConfig),
+		RefreshOnlyChanges: report.Count(terraform.DriftKindRefreshOnly),
+	}
+
+	for _, x := range report.Resources {
+		if len(status.Resources) >= maxPlanResources {
+			status.Truncated = true
+
+			break
+		}
+		status.Resources = append(status.Resources, terraformv1alphav1.DriftResource{
+			Action:  x.Action,
+			Address: x.Address,
+			Kind:    x.Kind,
+		})
+	}
+
+	var summary []string
+	for _, x := range []struct {
+		Count int
+		Label string
+	}{
+		{status.ConfigChanges, "config change"},
+		{status.RefreshOnlyChanges, "refresh-only change"},
+	} {
+		switch {
+		case x.Count == 1:
+			summary = append(summary, fmt.Sprintf("%d %s", x.Count, x.Label))
+		case x.Count > 1:
+			summary = append(summary, fmt.Sprintf("%d %ss", x.Count, x.Label))
+		}
+	}
+	status.Summary = "No drift"
+	if len(summary) > 0 {
+		status.Summary = strings.Join(summary, ", ")
+	}
+
+	return status
+}
+
 // GetTerraformImage is called to return the terraform image to use, or the image plus version
 // override
 func GetTerraformImage(configuration *terraformv1alphav1.Configuration, image string) string {
@@ -75,5 +207,397 @@ func (c Controller) CreateWatcher(ctx context.Context, configuration *terraformv
 		return nil
 	}
 
-	return c.cc.Create(ctx, watcher)
+	return c.createJob(ctx, watcher)
+}
+
+// createJob is called to create a job, a job which already exists was created by a previous reconcile
+// or another replica of the controller and is not an error
+func (c Controller) createJob(ctx context.Context, job *batchv1.Job) error {
+	if err := c.cc.Create(ctx, job); err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// hasCurrentTerraformPlan checks the saved terraform plan exists and was produced for the current generation
+func (c Controller) hasCurrentTerraformPlan(ctx context.Context, configuration *terraformv1alphav1.Configuration) (bool, error) {
+	secret := &v1.Secret{}
+	secret.Namespace = c.ControllerNamespace
+	secret.Name = configuration.GetTerraformPlanSecretName()
+
+	found, err := kubernetes.GetIfExists(ctx, c.cc, secret)
+	if err != nil || !found {
+		return false, err
+	}
+
+	switch {
+	case len(secret.Data[terraformv1alphav1.TerraformPlanSecretKey]) == 0:
+		return false, nil
+	case secret.GetLabels()[terraformv1alphav1.ConfigurationUIDLabel] != string(configuration.GetUID()):
+		return false, nil
+	case secret.GetLabels()[terraformv1alphav1.ConfigurationGenerationLabel] != fmt.Sprintf("%d", configuration.GetGeneration()):
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// createDriftReport is called to store the drift report in a secret within the configuration namespace
+func (c Controller) createDriftReport(ctx context.Context, configuration *terraformv1alphav1.Configuration, report *terraform.DriftReport) error {
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	secret := &v1.Secret{}
+	secret.Namespace = configuration.GetNamespace()
+	secret.Name = configuration.GetTerraformDriftSecretName()
+	secret.Labels = map[string]string{
+		terraformv1alphav1.ConfigurationNameLabel: configuration.GetName(),
+		terraformv1alphav1.ConfigurationUIDLabel:  string(configuration.GetUID()),
+	}
+	secret.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: terraformv1alphav1.SchemeGroupVersion.String(),
+			Kind:       terraformv1alphav1.ConfigurationKind,
+			Name:       configuration.GetName(),
+			UID:        configuration.GetUID(),
+		},
+	}
+	secret.Data = map[string][]byte{terraformv1alphav1.TerraformDriftSecretKey: encoded}
+
+	return kubernetes.CreateOrForceUpdate(ctx, c.cc, secret)
+}
+
+// errJobRunning is returned when the logs of a job are requested before the pod has finished
+var errJobRunning = errors.New("the pod of the job has not finished")
+
+// jobLogs returns the logs of the terraform container from the latest pod of the job, the logs are nil
+// when the job has no pods
+func (c Controller) jobLogs(ctx context.Context, job *batchv1.Job) ([]byte, error) {
+	pods, err := c.kc.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "job-name=" + job.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pod := kubernetes.FindLatestPod(pods)
+	if pod == nil {
+		return nil, nil
+	}
+
+	switch pod.Status.Phase {
+	case v1.PodPending, v1.PodRunning:
+		return nil, errJobRunning
+	case v1.PodSucceeded, v1.PodFailed:
+	default:
+		return nil, fmt.Errorf("pod %q is in an unknown phase", pod.Name)
+	}
+
+	stream, err := c.kc.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &v1.PodLogOptions{
+		Container: jobs.TerraformContainerName,
+		Follow:    false,
+	}).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	return io.ReadAll(stream)
+}
+
+// findStalePlanInJob checks the terraform logs of the job for terraform refusing to apply a stale plan
+func (c Controller) findStalePlanInJob(ctx context.Context, job *batchv1.Job) (bool, error) {
+	logs, err := c.jobLogs(ctx, job)
+	if err != nil || logs == nil {
+		return false, err
+	}
+
+	return terraform.FindStalePlanInLogs(bytes.NewReader(logs))
+}
+
+// findStateLockInJob is used to check if the job failed as terraform was unable to acquire the state lock,
+// returning the holder of the lock
+func (c Controller) findStateLockInJob(ctx context.Context, job *batchv1.Job) (*terraform.StateLock, error) {
+	logs, err := c.jobLogs(ctx, job)
+	if err != nil || logs == nil {
+		return nil, err
+	}
+
+	return terraform.FindStateLockInLogs(bytes.NewReader(logs))
+}
+
+// recordStateLock records the holder of the terraform state lock on the configuration status and condition
+func recordStateLock(configuration *terraformv1alphav1.Configuration, cond *controller.ConditionManager, lock *terraform.StateLock) {
+	var failed int
+	if configuration.Status.StateLock != nil && configuration.Status.StateLock.ID == lock.ID {
+		failed = configuration.Status.StateLock.FailedUnlocks
+	}
+	configuration.Status.StateLock = &terraformv1alphav1.StateLockStatus{
+		ID:            lock.ID,
+		Operation:     lock.Operation,
+		Who:           lock.Who,
+		Created:       lock.Created,
+		FailedUnlocks: failed,
+	}
+	cond.ActionRequired("Terraform state is locked by %s (lock id: %s) since %s, if the lock is stale use tnctl unlock to release it",
+		lock.Who, lock.ID, lock.Created)
+}
+
+// findApplyWindow returns the apply window for the configuration, the window on the configuration takes
+// precedence over any matching policy. A nil window indicates the apply can run at any time.
+func (c *Controller) findApplyWindow(
+	ctx context.Context,
+	configuration *terraformv1alphav1.Configuration,
+	state *state) (*schedule.Window, error) {
+
+	window := configuration.Spec.ApplyWindow
+	if window == nil {
+		policy, err := findMatchingConstraint(ctx, c, configuration, state.policies, policies.FindMatchingApplyWindow)
+		if err != nil {
+			return nil, err
+		}
+		if policy == nil {
+			return nil, nil
+		}
+		window = &policy.ApplyWindow
+	}
+
+	return schedule.NewWindow(window.Schedule, window.Duration.Duration, window.TimeZone)
+}
+
+// findDestroyProtectedResources returns the addresses of any protected resources the terraform plan will
+// destroy or replace. The protection on the configuration is combined with any matching policy.
+func (c Controller) findDestroyProtectedResources(
+	ctx context.Context,
+	configuration *terraformv1alphav1.Configuration,
+	state *state) ([]string, error) {
+
+	protection := &terraformv1alphav1.DestroyProtection{}
+	if configuration.Spec.DestroyProtection != nil {
+		protection.AllResources = configuration.Spec.DestroyProtection.AllResources
+		protection.ResourceTypes = append(protection.ResourceTypes, configuration.Spec.DestroyProtection.ResourceTypes...)
+	}
+
+	policy, err := findMatchingConstraint(ctx, &c, configuration, state.policies, policies.FindMatchingDestroyProtection)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		protection.AllResources = protection.AllResources || policy.AllResources
+		protection.ResourceTypes = append(protection.ResourceTypes, policy.ResourceTypes...)
+	}
+
+	if !protection.IsEnabled() {
+		return nil, nil
+	}
+	if state.plan == nil {
+		return nil, errors.New("terraform plan output is not available to verify the destroy protection")
+	}
+
+	var list []string
+	for _, change := range state.plan.ResourceChanges {
+		if change.Mode == "data" {
+			continue
+		}
+		switch change.Action() {
+		case "delete", "replace":
+			if protection.IsProtected(change.Type) {
+				list = append(list, change.Address)
+			}
+		}
+	}
+
+	return list, nil
+}
+
+// revokeApproval is called to mark the configuration as requiring an approval before being applied
+func (c Controller) revokeApproval(ctx context.Context, configuration *terraformv1alphav1.Configuration) error {
+	if configuration.NeedsApproval() {
+		return nil
+	}
+
+	updated := configuration.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[terraformv1alphav1.ApplyAnnotation] = "false"
+
+	return c.cc.Patch(ctx, updated, client.MergeFrom(configuration))
+}
+
+// clearOperations is called to remove the one-shot operations from the configuration once applied
+func (c Controller) clearOperations(ctx context.Context, configuration *terraformv1alphav1.Configuration) error {
+	updated := configuration.DeepCopy()
+	updated.Spec.Operations = nil
+
+	return c.cc.Patch(ctx, updated, client.MergeFrom(configuration))
+}
+
+// formatPlanResets returns the reset label of the jobs for the current plan, this is empty until the plan
+// has been reset, so the jobs created before the first reset are still matched
+func formatPlanResets(configuration *terraformv1alphav1.Configuration) string {
+	if configuration.Status.PlanResets == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(configuration.Status.PlanResets)
+}
+
+// formatDrift returns a human readable message for the drift detected on the configuration
+func formatDrift(configuration *terraformv1alphav1.Configuration) string {
+	if configuration.Status.Drift == nil || configuration.Status.Drift.Summary == "" {
+		return "Drift has been detected in the resource"
+	}
+
+	return fmt.Sprintf("Drift has been detected in the resource (%s)", configuration.Status.Drift.Summary)
+}
+
+// formatOperations returns a human readable summary of the replace and target operations
+func formatOperations(operations *terraformv1alphav1.Operations) string {
+	var list []string
+	if len(operations.Replace) > 0 {
+		list = append(list, "replace: "+strings.Join(operations.Replace, ", "))
+	}
+	if len(operations.Target) > 0 {
+		list = append(list, "target: "+strings.Join(operations.Target, ", "))
+	}
+
+	return strings.Join(list, "; ")
+}
+
+// findUnexpectedCreates returns the resources the terraform plan creates while the configuration is adopting
+// existing infrastructure. Creates are returned when the configuration imported a state, while unexpected are
+// the addresses in spec.imports which terraform is creating rather than importing.
+func findUnexpectedCreates(configuration *terraformv1alphav1.Configuration, state *state) (creates, unexpected []string) {
+	if state.plan == nil {
+		return nil, nil
+	}
+
+	imports := make(map[string]bool, len(configuration.Spec.Imports))
+	for _, x := range configuration.Spec.Imports {
+		imports[x.Address] = true
+	}
+
+	for _, change := range state.plan.ResourceChanges {
+		if change.Mode == "data" || change.Action() != "create" {
+			continue
+		}
+		switch {
+		case imports[change.Address]:
+			unexpected = append(unexpected, change.Address)
+		case configuration.Spec.ImportState != nil:
+			creates = append(creates, change.Address)
+		}
+	}
+
+	return creates, unexpected
+}
+
+// getBackendDefinition returns the terraform backend defined on the provider, else the controller default
+func (c Controller) getBackendDefinition(provider *terraformv1alphav1.Provider) *terraformv1alphav1.Backend {
+	if provider.Spec.Backend != nil {
+		return provider.Spec.Backend
+	}
+
+	return c.DefaultBackend
+}
+
+// newBackendOptions returns the options used to create the terraform backend for the configuration, with any
+// credentials taken from the secret
+func (c Controller) newBackendOptions(
+	configuration *terraformv1alphav1.Configuration,
+	backend *terraformv1alphav1.Backend,
+	secret *v1.Secret) (terraform.BackendOptions, error) {
+
+	options := terraform.BackendOptions{
+		Client:    c.cc,
+		Key:       string(configuration.GetUID()),
+		Namespace: c.ControllerNamespace,
+		Type:      terraformv1alphav1.KubernetesBackendType,
+		Workspace: configuration.GetWorkspace(),
+	}
+	if backend == nil {
+		return options, nil
+	}
+	options.Type = backend.Type
+
+	if backend.Configuration != nil && len(backend.Configuration.Raw) > 0 {
+		if err := json.NewDecoder(bytes.NewReader(backend.Configuration.Raw)).Decode(&options.Configuration); err != nil {
+			return options, err
+		}
+	}
+	if secret != nil {
+		options.Credentials = make(map[string]string)
+		for k, v := range secret.Data {
+			options.Credentials[k] = string(v)
+		}
+	}
+
+	return options, nil
+}
+
+// getConfigurationState is responsible for retrieving the terraform state of another configuration, returning
+// nil when the configuration has no state
+func (c Controller) getConfigurationState(ctx context.Context, configuration *terraformv1alphav1.Configuration) (*terraform.State, error) {
+	provider := &terraformv1alphav1.Provider{}
+	provider.Name = configuration.Spec.ProviderRef.Name
+
+	found, err := kubernetes.GetIfExists(ctx, c.cc, provider)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("provider %q does not exist", provider.Name)
+	}
+	backend := c.getBackendDefinition(provider)
+
+	var secret *v1.Secret
+	if backend != nil && backend.SecretRef != nil && backend.SecretRef.Name != "" {
+		secret = &v1.Secret{}
+		secret.Namespace = c.ControllerNamespace
+		secret.Name = backend.SecretRef.Name
+
+		found, err := kubernetes.GetIfExists(ctx, c.cc, secret)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("terraform backend secret (%s/%s) does not exist", secret.Namespace, secret.Name)
+		}
+	}
+
+	options, err := c.newBackendOptions(configuration, backend, secret)
+	if err != nil {
+		return nil, err
+	}
+	b, err := terraform.NewBackend(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.GetState(ctx)
+}
+
+// upstreamChecksum returns a checksum of the outputs taken from other configurations, or an empty
+// string when the configuration has no upstreams
+func upstreamChecksum(values map[string]string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(hash, "%s=%s\n", k, values[k])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:16]
 }
@@ -18,11 +18,15 @@
 package configuration
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/utils/terraform"
 )
 
 func TestGetTerraformImage(t *testing.T) {
@@ -49,3 +53,103 @@ func TestGetTerraformImage(t *testing.T) {
 		assert.Equal(t, c.Expected, GetTerraformImage(config, c.Default))
 	}
 }
+
+func TestNewPlanStatus(t *testing.T) {
+	newChange := func(address, mode string, actions ...string) terraform.ResourceChange {
+		change := terraform.ResourceChange{Address: address, Mode: mode}
+		change.Change.Actions = actions
+
+		return change
+	}
+
+	plan := &terraform.Plan{ResourceChanges: []terraform.ResourceChange{
+		newChange("aws_s3_bucket.this", "managed", "create"),
+		newChange("aws_s3_bucket_policy.this", "managed", "update"),
+		newChange("aws_iam_role.this", "managed", "delete", "create"),
+		newChange("aws_iam_policy.this", "managed", "delete"),
+		newChange("aws_kms_key.this", "managed", "no-op"),
+		newChange("data.aws_caller_identity.current", "data", "read"),
+	}}
+
+	status := NewPlanStatus(plan, 2)
+	assert.Equal(t, int64(2), status.Generation)
+	assert.Equal(t, 1, status.Add)
+	assert.Equal(t, 1, status.Change)
+	assert.Equal(t, 1, status.Destroy)
+	assert.Equal(t, 1, status.Replace)
+	assert.Equal(t, "1 to add, 1 to change, 1 to destroy, 1 to replace", status.Summary)
+	assert.True(t, status.HasChanges)
+	assert.False(t, status.Truncated)
+	assert.Equal(t, []terraformv1alphav1.PlanResource{
+		{Action: "create", Address: "aws_s3_bucket.this"},
+		{Action: "update", Address: "aws_s3_bucket_policy.this"},
+		{Action: "replace", Address: "aws_iam_role.this"},
+		{Action: "delete", Address: "aws_iam_policy.this"},
+	}, status.Resources)
+}
+
+func TestNewPlanStatusImports(t *testing.T) {
+	plan := &terraform.Plan{}
+	for _, actions := range [][]string{{"no-op"}, {"update"}} {
+		change := terraform.ResourceChange{Address: fmt.Sprintf("aws_s3_bucket.%s", actions[0]), Mode: "managed"}
+		change.Change.Actions = actions
+		change.Change.Importing = &terraform.ResourceImport{ID: "bucket"}
+		plan.ResourceChanges = append(plan.ResourceChanges, change)
+	}
+
+	status := NewPlanStatus(plan, 1)
+	assert.Equal(t, 2, status.Import)
+	assert.Equal(t, 1, status.Change)
+	assert.Equal(t, "2 to import, 1 to change", status.Summary)
+	assert.Equal(t, []terraformv1alphav1.PlanResource{
+		{Action: "import", Address: "aws_s3_bucket.no-op"},
+		{Action: "update", Address: "aws_s3_bucket.update"},
+	}, status.Resources)
+}
+
+func TestNewPlanStatusNoChanges(t *testing.T) {
+	status := NewPlanStatus(&terraform.Plan{}, 1)
+	assert.Equal(t, "No changes", status.Summary)
+	assert.False(t, status.HasChanges)
+	assert.Empty(t, status.Resources)
+}
+
+func TestNewPlanStatusTruncated(t *testing.T) {
+	plan := &terraform.Plan{}
+	for i := 0; i < maxPlanResources+10; i++ {
+		change := terraform.ResourceChange{Address: fmt.Sprintf("aws_s3_bucket.this[%d]", i), Mode: "managed"}
+		change.Change.Actions = []string{"delete"}
+		plan.ResourceChanges = append(plan.ResourceChanges, change)
+	}
+
+	status := NewPlanStatus(plan, 1)
+	assert.Equal(t, maxPlanResources+10, status.Destroy)
+	assert.Equal(t, "60 to destroy", status.Summary)
+	assert.Len(t, status.Resources, maxPlanResources)
+	assert.True(t, status.Truncated)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	retry := &terraformv1alphav1.Retry{MaxAttempts: 10, Backoff: &metav1.Duration{Duration: time.Minute}}
+
+	assert.Equal(t, time.Minute, retryBackoff(retry, 1))
+	assert.Equal(t, 2*time.Minute, retryBackoff(retry, 2))
+	assert.Equal(t, 4*time.Minute, retryBackoff(retry, 3))
+	assert.Equal(t, maxRetryBackoff, retryBackoff(retry, 10))
+}
+
+func TestFindRetry(t *testing.T) {
+	configuration := &terraformv1alphav1.Configuration{}
+	c := &Controller{}
+
+	retry := c.findRetry(configuration)
+	assert.Equal(t, 1, retry.MaxAttempts)
+	assert.Equal(t, defaultRetryBackoff, retry.Backoff.Duration)
+
+	c.DefaultRetry = &terraformv1alphav1.Retry{MaxAttempts: 3, Backoff: &metav1.Duration{Duration: time.Minute}}
+	configuration.Spec.Retry = &terraformv1alphav1.Retry{MaxAttempts: 5}
+
+	retry = c.findRetry(configuration)
+	assert.Equal(t, 5, retry.MaxAttempts)
+	assert.Equal(t, time.Minute, retry.Backoff.Duration)
+}
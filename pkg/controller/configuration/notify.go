@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/controller"
+	"github.com/appvia/terraform-controller/pkg/utils/notifications"
+	"github.com/appvia/terraform-controller/pkg/utils/policies"
+)
+
+// notify is called to send a lifecycle event of the configuration to the matching notification sinks. The event
+// is delivered in the background, a failure to deliver is logged but never fails the reconciliation
+func (c *Controller) notify(
+	ctx context.Context,
+	configuration *terraformv1alphav1.Configuration,
+	state *state,
+	kind terraformv1alphav1.NotificationEvent,
+	message string, args ...interface{}) {
+
+	if c.Notifier == nil {
+		return
+	}
+
+	logger := log.WithFields(log.Fields{
+		"event":     kind,
+		"name":      configuration.GetName(),
+		"namespace": configuration.GetNamespace(),
+	})
+
+	sinks, err := findMatchingConstraint(ctx, c, configuration, state.policies, policies.FindMatchingNotifications)
+	if err != nil {
+		logger.WithError(err).Warn("failed to find the notification sinks for the configuration")
+
+		return
+	}
+
+	event := notifications.Event{
+		Configuration: configuration.GetName(),
+		Generation:    configuration.GetGeneration(),
+		ID:            string(uuid.NewUUID()),
+		Message:       fmt.Sprintf(message, args...),
+		Namespace:     configuration.GetNamespace(),
+		Time:          time.Now().UTC(),
+		Type:          kind,
+		UID:           string(configuration.GetUID()),
+	}
+
+	for i := 0; i < len(sinks); i++ {
+		if !sinks[i].HasEvent(kind) {
+			continue
+		}
+
+		url, err := c.findNotificationURL(ctx, &sinks[i])
+		if err != nil {
+			logger.WithError(err).WithField("sink", sinks[i].Name).Warn("failed to retrieve the url of the notification sink")
+
+			continue
+		}
+		sink := notifications.Sink{Format: sinks[i].GetFormat(), Name: sinks[i].Name, URL: url}
+
+		go func() {
+			if err := c.Notifier.Send(context.Background(), sink, event); err != nil {
+				logger.WithError(err).WithField("sink", sink.Name).Error("failed to send the notification")
+			}
+		}()
+	}
+}
+
+// findNotificationURL returns the url of the notification sink, taken from the secret when referenced
+func (c *Controller) findNotificationURL(ctx context.Context, sink *terraformv1alphav1.NotificationConstraint) (string, error) {
+	if sink.SecretRef == nil {
+		return sink.URL, nil
+	}
+
+	secret := &v1.Secret{}
+	key := client.ObjectKey{Namespace: sink.SecretRef.Namespace, Name: sink.SecretRef.Name}
+	if key.Namespace == "" {
+		key.Namespace = c.ControllerNamespace
+	}
+	if err := c.cc.Get(ctx, key, secret); err != nil {
+		return "", err
+	}
+
+	url := string(secret.Data[terraformv1alphav1.NotificationURLSecretKey])
+	if url == "" {
+		return "", fmt.Errorf("secret (%s/%s) does not contain the %q key", key.Namespace, key.Name, terraformv1alphav1.NotificationURLSecretKey)
+	}
+
+	return url, nil
+}
+
+// hasTransitioned returns true if the update changed the condition, this allows us to notify on a change of
+// state rather than on every reconciliation
+func hasTransitioned(cond *controller.ConditionManager, update func()) bool {
+	original := *cond.GetCondition()
+	update()
+
+	return !reflect.DeepEqual(original, *cond.GetCondition())
+}
+
+// parseMonthlyCost returns the estimated monthly cost recorded on the status, if any
+func parseMonthlyCost(costs *terraformv1alphav1.CostStatus) (float64, bool) {
+	if costs == nil || !costs.Enabled {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimPrefix(costs.Monthly, "$"), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return value, true
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/utils/jobs"
+	"github.com/appvia/terraform-controller/pkg/utils/policies"
+)
+
+const (
+	// queueRequeueInterval is the interval queued configurations are checked for a free slot
+	queueRequeueInterval = 30 * time.Second
+	// queueEntryTimeout is the time after which a queued configuration which has not been
+	// reconciled is removed from the queue, i.e. it has been deleted
+	queueEntryTimeout = 2 * time.Minute
+	// queueReservationTimeout is the time an admitted configuration holds a slot, giving the
+	// job it creates time to appear in the cache
+	queueReservationTimeout = time.Minute
+)
+
+// queueEntry is a configuration waiting for a slot to run a terraform job
+type queueEntry struct {
+	// key is the namespace/name of the configuration
+	key string
+	// namespace is the namespace of the configuration
+	namespace string
+	// limit is the maximum number of jobs permitted in the namespace, zero is unlimited
+	limit int
+	// seen is the last time the configuration was reconciled
+	seen time.Time
+}
+
+// jobQueue is a first in first out queue of configurations waiting to run a terraform job
+type jobQueue struct {
+	sync.Mutex
+	// entries is the ordered list of configurations waiting
+	entries []*queueEntry
+	// reservations are the configurations which have been admitted but whose jobs may not
+	// yet be visible
+	reservations map[string]*queueEntry
+}
+
+// newJobQueue returns an empty job queue
+func newJobQueue() *jobQueue {
+	return &jobQueue{reservations: make(map[string]*queueEntry)}
+}
+
+// Admit is called to check if the configuration can run a job given the jobs already running (a map
+// of configuration key to namespace). Zero is returned when admitted, otherwise the position of the
+// configuration in the queue.
+func (q *jobQueue) Admit(entry *queueEntry, running map[string]string, globalLimit int, now time.Time) int {
+	q.Lock()
+	defer q.Unlock()
+
+	// @step: the configuration keeps its position in the queue, or joins the back of it
+	if i := q.indexOf(entry.key); i >= 0 {
+		q.entries[i].limit = entry.limit
+		q.entries[i].seen = entry.seen
+	} else {
+		q.entries = append(q.entries, entry)
+	}
+
+	// @step: expire any stale entries and reservations
+	var entries []*queueEntry
+	for _, x := range q.entries {
+		if now.Sub(x.seen) < queueEntryTimeout {
+			entries = append(entries, x)
+		}
+	}
+	q.entries = entries
+
+	for key, x := range q.reservations {
+		if _, found := running[key]; found || now.Sub(x.seen) >= queueReservationTimeout {
+			delete(q.reservations, key)
+		}
+	}
+
+	// @step: count the jobs which are running or have been reserved
+	global := len(running)
+	namespaces := make(map[string]int)
+	for _, namespace := range running {
+		namespaces[namespace]++
+	}
+	for _, x := range q.reservations {
+		global++
+		namespaces[x.namespace]++
+	}
+
+	// @step: walk the queue in order, the configurations ahead which fit the quotas take the slots first
+	for i, x := range q.entries {
+		fits := (globalLimit <= 0 || global < globalLimit) && (x.limit <= 0 || namespaces[x.namespace] < x.limit)
+		if x.key != entry.key {
+			if fits {
+				global++
+				namespaces[x.namespace]++
+			}
+
+			continue
+		}
+		if !fits {
+			return i + 1
+		}
+
+		q.entries = append(q.entries[:i], q.entries[i+1:]...)
+		q.reservations[entry.key] = entry
+
+		return 0
+	}
+
+	return 0
+}
+
+// indexOf returns the index of the configuration in the queue or -1
+func (q *jobQueue) indexOf(key string) int {
+	for i, x := range q.entries {
+		if x.key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// acquireJobSlot is called before creating a terraform plan or apply job, returning zero when the
+// configuration is permitted to run the job, otherwise its position in the queue
+func (c *Controller) acquireJobSlot(ctx context.Context, configuration *terraformv1alphav1.Configuration, state *state) (int, error) {
+	limit, err := c.findNamespaceJobLimit(ctx, configuration, state)
+	if err != nil {
+		return 0, err
+	}
+	if c.MaxConcurrentJobs <= 0 && limit <= 0 {
+		return 0, nil
+	}
+
+	list := &batchv1.JobList{}
+	if err := c.cc.List(ctx, list,
+		client.InNamespace(c.ControllerNamespace),
+		client.HasLabels{terraformv1alphav1.ConfigurationStageLabel},
+	); err != nil {
+		return 0, err
+	}
+
+	running := make(map[string]string)
+	for i := 0; i < len(list.Items); i++ {
+		labels := list.Items[i].GetLabels()
+
+		switch labels[terraformv1alphav1.ConfigurationStageLabel] {
+		case terraformv1alphav1.StageTerraformPlan, terraformv1alphav1.StageTerraformApply, terraformv1alphav1.StageTerraformDrift:
+		default:
+			continue
+		}
+		if !jobs.IsActive(&list.Items[i]) {
+			continue
+		}
+		namespace := labels[terraformv1alphav1.ConfigurationNamespaceLabel]
+		running[fmt.Sprintf("%s/%s", namespace, labels[terraformv1alphav1.ConfigurationNameLabel])] = namespace
+	}
+
+	return c.queue.Admit(&queueEntry{
+		key:       fmt.Sprintf("%s/%s", configuration.Namespace, configuration.Name),
+		namespace: configuration.Namespace,
+		limit:     limit,
+		seen:      time.Now(),
+	}, running, c.MaxConcurrentJobs, time.Now()), nil
+}
+
+// findNamespaceJobLimit returns the maximum number of jobs permitted in the namespace of the
+// configuration, or zero if unlimited
+func (c *Controller) findNamespaceJobLimit(ctx context.Context, configuration *terraformv1alphav1.Configuration, state *state) (int, error) {
+	constraint, err := findMatchingConstraint(ctx, c, configuration, state.policies, policies.FindMatchingConcurrency)
+	if err != nil || constraint == nil {
+		return 0, err
+	}
+
+	return constraint.MaxJobs, nil
+}
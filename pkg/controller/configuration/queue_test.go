@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package configuration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobQueueAdmit(t *testing.T) {
+	now := time.Now()
+	queue := newJobQueue()
+	running := map[string]string{"apps/running": "apps"}
+
+	entry := func(name, namespace string, limit int) *queueEntry {
+		return &queueEntry{key: namespace + "/" + name, namespace: namespace, limit: limit, seen: now}
+	}
+
+	// @note: the global limit is reached, so the configurations are queued in order
+	assert.Equal(t, 1, queue.Admit(entry("a", "apps", 0), running, 1, now))
+	assert.Equal(t, 2, queue.Admit(entry("b", "apps", 0), running, 1, now))
+	assert.Equal(t, 1, queue.Admit(entry("a", "apps", 0), running, 1, now))
+
+	// @note: a slot is freed, the head of the queue is admitted before those behind it
+	assert.Equal(t, 2, queue.Admit(entry("b", "apps", 0), map[string]string{}, 1, now))
+	assert.Equal(t, 0, queue.Admit(entry("a", "apps", 0), map[string]string{}, 1, now))
+	// @note: the slot is reserved for a until its job is running
+	assert.Equal(t, 1, queue.Admit(entry("b", "apps", 0), map[string]string{}, 1, now))
+	assert.Equal(t, 1, queue.Admit(entry("b", "apps", 0), map[string]string{"apps/a": "apps"}, 1, now))
+}
+
+func TestJobQueueNamespaceLimit(t *testing.T) {
+	now := time.Now()
+	queue := newJobQueue()
+	running := map[string]string{"apps/running": "apps"}
+
+	// @note: the apps namespace is at its limit, but it should not block other namespaces
+	assert.Equal(t, 1, queue.Admit(&queueEntry{key: "apps/a", namespace: "apps", limit: 1, seen: now}, running, 0, now))
+	assert.Equal(t, 0, queue.Admit(&queueEntry{key: "team/b", namespace: "team", limit: 1, seen: now}, running, 0, now))
+}
+
+func TestJobQueueExpiresEntries(t *testing.T) {
+	now := time.Now()
+	queue := newJobQueue()
+	running := map[string]string{"apps/running": "apps"}
+
+	assert.Equal(t, 1, queue.Admit(&queueEntry{key: "apps/a", namespace: "apps", seen: now}, running, 1, now))
+	assert.Equal(t, 2, queue.Admit(&queueEntry{key: "apps/b", namespace: "apps", seen: now}, running, 1, now))
+
+	// @note: a is no longer being reconciled, i.e. it was deleted, so b moves to the front
+	later := now.Add(queueEntryTimeout)
+	assert.Equal(t, 0, queue.Admit(&queueEntry{key: "apps/b", namespace: "apps", seen: later}, map[string]string{}, 1, later))
+}
@@ -28,11 +28,16 @@ import (
 
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 	"github.com/appvia/terraform-controller/pkg/controller"
+	"github.com/appvia/terraform-controller/pkg/utils/terraform"
 )
 
 type state struct {
 	// auth is an optional secret which is used for authentication
 	auth *v1.Secret
+	// backend is the terraform state backend for the configuration
+	backend terraform.Backend
+	// backendSecret is the name of the secret containing the backend credentials
+	backendSecret string
 	// checkovConstraint is the policy constraint for this configuration
 	checkovConstraint *terraformv1alphav1.PolicyConstraint
 	// hasDrift is a flag to indicate if the configuration has drift
@@ -41,14 +46,22 @@ type state struct {
 	policies *terraformv1alphav1.PolicyList
 	// provider is the credentials provider to use
 	provider *terraformv1alphav1.Provider
+	// plan is the decoded terraform plan for the current generation
+	plan *terraform.Plan
 	// jobs is list of all jobs for this configuration and generation
 	jobs *batchv1.JobList
 	// jobTemplate is the template to use when rendering the job
 	jobTemplate []byte
+	// module is the source of the module pinned to the resolved revision
+	module string
+	// upstream is a checksum of the outputs taken from other configurations and the module revision
+	upstream string
+	// upstreams is a map of the values the upstream checksum is computed from
+	upstreams map[string]string
 	// valueFrom is a map of keys to values
 	valueFrom map[string]string
-	// tfstate is the secret containing the terraform state
-	tfstate *v1.Secret
+	// tfstate is the decoded terraform state
+	tfstate *terraform.State
 }
 
 // Reconcile is called to handle the reconciliation of the provider resource
@@ -64,16 +77,20 @@ func (c *Controller) Reconcile(ctx context.Context, request reconcile.Request) (
 		return reconcile.Result{}, err
 	}
 
-	state := &state{valueFrom: make(map[string]string)}
+	state := &state{upstreams: make(map[string]string), valueFrom: make(map[string]string)}
 
 	finalizer := controller.NewFinalizer(c.cc, controllerName)
 	if finalizer.IsDeletionCandidate(configuration) {
 		result, err := controller.DefaultEnsureHandler.Run(ctx, c.cc, configuration,
 			[]controller.EnsureFunc{
 				c.ensureCapturedState(configuration, state),
+				c.ensureNoDependents(configuration),
 				c.ensureProviderReady(configuration, state),
+				c.ensureTerraformBackend(configuration, state),
 				c.ensureAuthenticationSecret(configuration, state),
 				c.ensureCustomJobTemplate(configuration, state),
+				c.ensureTerraformUnlock(configuration, state),
+				c.ensureTerraformDestroyApproval(configuration, state),
 				c.ensureTerraformDestroy(configuration, state),
 				c.ensureConfigurationSecretsDeleted(configuration),
 				c.ensureConfigurationJobsDeleted(configuration),
@@ -97,19 +114,32 @@ func (c *Controller) Reconcile(ctx context.Context, request reconcile.Request) (
 			c.ensureCapturedState(configuration, state),
 			c.ensureNoActivity(configuration, state),
 			c.ensureCostSecret(configuration),
+			c.ensureDependenciesReady(configuration),
 			c.ensureValueFromSecret(configuration, state),
+			c.ensureModuleResolved(configuration, state),
 			c.ensureAuthenticationSecret(configuration, state),
 			c.ensureCustomJobTemplate(configuration, state),
 			c.ensureProviderReady(configuration, state),
+			c.ensureTerraformBackend(configuration, state),
+			c.ensureTerraformStateImport(configuration, state),
 			c.ensureJobConfigurationSecret(configuration, state),
+			c.ensureTerraformUnlock(configuration, state),
 			c.ensureTerraformPlan(configuration, state),
-			c.ensureCostStatus(configuration),
+			c.ensureTerraformPlanStatus(configuration, state),
+			c.ensureCostStatus(configuration, state),
 			c.ensurePolicyStatus(configuration, state),
 			c.ensureDriftDetection(configuration, state),
+			c.ensureTerraformPlanOnly(configuration),
 			c.ensureTerraformApply(configuration, state),
 			c.ensureConnectionSecret(configuration, state),
 			c.ensureTerraformStatus(configuration, state),
 		})
+
+	// @step: record the outcome of the generation in the revision history
+	if rerr := c.recordRevision(ctx, configuration); rerr != nil {
+		log.WithError(rerr).Error("failed to record the configuration revision")
+	}
+
 	if err != nil {
 		log.WithError(err).Error("failed to reconcile the configuration resource")
 
@@ -19,8 +19,12 @@ package configuration
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -30,6 +34,7 @@ import (
 	"github.com/sirupsen/logrus"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -40,8 +45,13 @@ import (
 
 	corev1alphav1 "github.com/appvia/terraform-controller/pkg/apis/core/v1alpha1"
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/controller"
 	"github.com/appvia/terraform-controller/pkg/schema"
+	"github.com/appvia/terraform-controller/pkg/utils/jobs"
 	"github.com/appvia/terraform-controller/pkg/utils/kubernetes"
+	"github.com/appvia/terraform-controller/pkg/utils/modules"
+	"github.com/appvia/terraform-controller/pkg/utils/notifications"
+	"github.com/appvia/terraform-controller/pkg/utils/terraform"
 	controllertests "github.com/appvia/terraform-controller/test"
 	"github.com/appvia/terraform-controller/test/fixtures"
 )
@@ -51,6 +61,50 @@ func TestReconcile(t *testing.T) {
 	RunSpecs(t, "Running Test Suite")
 }
 
+// fakeResolver returns a fixed reference for all module sources
+type fakeResolver struct {
+	err       error
+	reference *modules.Reference
+}
+
+// Resolve returns the reference or the error
+func (f *fakeResolver) Resolve(_ context.Context, _ string) (*modules.Reference, error) {
+	return f.reference, f.err
+}
+
+type fakeNotifier struct {
+	sync.Mutex
+	events []notifications.Event
+	sinks  []notifications.Sink
+}
+
+// Send records the event and the sink it was sent to
+func (f *fakeNotifier) Send(_ context.Context, sink notifications.Sink, event notifications.Event) error {
+	f.Lock()
+	defer f.Unlock()
+
+	f.events = append(f.events, event)
+	f.sinks = append(f.sinks, sink)
+
+	return nil
+}
+
+// Events returns a copy of the events sent
+func (f *fakeNotifier) Events() []notifications.Event {
+	f.Lock()
+	defer f.Unlock()
+
+	return append([]notifications.Event{}, f.events...)
+}
+
+// Sinks returns a copy of the sinks the events were sent to
+func (f *fakeNotifier) Sinks() []notifications.Sink {
+	f.Lock()
+	defer f.Unlock()
+
+	return append([]notifications.Sink{}, f.sinks...)
+}
+
 var _ = Describe("Configuration Controller", func() {
 	logrus.SetOutput(ioutil.Discard)
 
@@ -94,6 +148,7 @@ var _ = Describe("Configuration Controller", func() {
 			ControllerNamespace: "default",
 			PolicyImage:         "bridgecrew/checkov:2.0.1140",
 			TerraformImage:      "hashicorp/terraform:1.1.9",
+			queue:               newJobQueue(),
 		}
 		ctrl.cache.SetDefault(cfgNamespace, fixtures.NewNamespace(cfgNamespace))
 	}
@@ -630,7 +685,11 @@ var _ = Describe("Configuration Controller", func() {
 				token := fixtures.NewCostsSecret(ctrl.ControllerNamespace, "infracost")
 				token.Namespace = ctrl.ControllerNamespace
 
-				Setup(configuration, plan, state, token)
+				// create fake saved plan
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+
+				Setup(configuration, plan, state, saved, token)
 				ctrl.EnableInfracosts = true
 				ctrl.InfracostsSecretName = "infracost"
 
@@ -665,7 +724,11 @@ var _ = Describe("Configuration Controller", func() {
 				token := fixtures.NewCostsSecret(ctrl.ControllerNamespace, "infracost")
 				token.Namespace = ctrl.ControllerNamespace
 
-				Setup(configuration, plan, state, report, token)
+				// create fake saved plan
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+
+				Setup(configuration, plan, state, saved, report, token)
 				ctrl.EnableInfracosts = true
 				ctrl.InfracostsSecretName = "infracost"
 
@@ -874,6 +937,379 @@ var _ = Describe("Configuration Controller", func() {
 		})
 	})
 
+	// UPSTREAM CONFIGURATIONS
+	When("the configuration has valueFrom configuration definitions", func() {
+		var upstream *terraformv1alphav1.Configuration
+
+		NewUpstream := func(ready bool) *terraformv1alphav1.Configuration {
+			upstream = fixtures.NewValidBucketConfiguration(cfgNamespace, "vpc")
+			upstream.UID = "upstream-1234"
+			upstream.Generation = 2
+			if ready {
+				upstream.Status.Conditions = []corev1alphav1.Condition{{
+					Type:               corev1alphav1.ConditionReady,
+					Status:             metav1.ConditionTrue,
+					Reason:             corev1alphav1.ReasonReady,
+					ObservedGeneration: 2,
+				}}
+			}
+
+			return upstream
+		}
+
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Spec.ValueFrom = []terraformv1alphav1.ValueFromSource{
+				{Configuration: "vpc", Key: "test_output"},
+			}
+		})
+
+		When("the upstream configuration is not ready", func() {
+			BeforeEach(func() {
+				Setup(configuration, NewUpstream(false))
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should indicate the configuration is waiting", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Waiting for configuration spec.valueFrom[0] (apps/vpc) to be ready"))
+			})
+
+			It("should ask us to requeue", func() {
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+				Expect(rerr).To(BeNil())
+			})
+
+			It("should not create any jobs", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(0))
+			})
+		})
+
+		When("the upstream configuration does not have the output", func() {
+			BeforeEach(func() {
+				configuration.Spec.ValueFrom[0].Key = "missing"
+				state := fixtures.NewTerraformState(NewUpstream(true))
+				state.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, upstream, state)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should indicate the output is missing", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+				Expect(cond.Message).To(Equal("Configuration spec.valueFrom[0] (apps/vpc) does not have the output: \"missing\""))
+			})
+		})
+
+		When("the upstream configuration is ready", func() {
+			BeforeEach(func() {
+				state := fixtures.NewTerraformState(NewUpstream(true))
+				state.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, upstream, state)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have added the output to the configuration config", func() {
+				expected := "{\"name\":\"test\",\"test_output\":\"test\"}\n"
+
+				secret := &v1.Secret{}
+				secret.Namespace = ctrl.ControllerNamespace
+				secret.Name = configuration.GetTerraformConfigSecretName()
+
+				found, err := kubernetes.GetIfExists(context.TODO(), ctrl.cc, secret)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(string(secret.Data[terraformv1alphav1.TerraformVariablesConfigMapKey])).To(Equal(expected))
+			})
+
+			It("should have labelled the plan with the upstream outputs", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+				Expect(list.Items[0].GetLabels()[terraformv1alphav1.ConfigurationUpstreamLabel]).ToNot(BeEmpty())
+			})
+		})
+
+		When("the upstream outputs have changed since the last apply", func() {
+			BeforeEach(func() {
+				configuration.Status.UpstreamChecksum = "changed"
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				apply := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformApply)
+				apply.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				apply.Status.Succeeded = 1
+				state := fixtures.NewTerraformState(NewUpstream(true))
+				state.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, upstream, state, plan, apply)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have created a new plan", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(3))
+			})
+		})
+	})
+
+	// MODULE PINNING
+	When("the controller is resolving module revisions", func() {
+		var resolver *fakeResolver
+
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Spec.Module = "https://github.com/terraform-aws-modules/terraform-aws-s3-bucket.git?ref=v3.0.0"
+			resolver = &fakeResolver{reference: &modules.Reference{
+				Revision: "abc123",
+				Source:   "https://github.com/terraform-aws-modules/terraform-aws-s3-bucket.git?ref=abc123",
+				Version:  "v3.0.0",
+				Versions: []string{"v3.0.0", "v3.0.1", "v3.1.0"},
+			}}
+		})
+
+		JustBeforeEach(func() {
+			ctrl.ModuleResolver = resolver
+		})
+
+		When("the module cannot be resolved", func() {
+			BeforeEach(func() {
+				resolver.err = errors.New("rate limited")
+				Setup(configuration)
+			})
+
+			JustBeforeEach(func() {
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should indicate the failure on the conditions", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonError))
+				Expect(cond.Message).To(ContainSubstring("Failed to resolve the revision of the module"))
+				Expect(rerr).To(HaveOccurred())
+			})
+
+			It("should not create any jobs", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(BeEmpty())
+			})
+		})
+
+		When("the module is resolved", func() {
+			BeforeEach(func() {
+				Setup(configuration)
+			})
+
+			JustBeforeEach(func() {
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have recorded the revision on the status", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.Module).To(Equal(&terraformv1alphav1.ModuleStatus{
+					Revision: "abc123",
+					Source:   "https://github.com/terraform-aws-modules/terraform-aws-s3-bucket.git?ref=abc123",
+				}))
+			})
+
+			It("should have recorded the module on the revision", func() {
+				revision := terraformv1alphav1.NewConfigurationRevision(configuration.Namespace,
+					terraformv1alphav1.GetConfigurationRevisionName(configuration.Name, configuration.GetGeneration()),
+				)
+				Expect(cc.Get(context.TODO(), client.ObjectKeyFromObject(revision), revision)).ToNot(HaveOccurred())
+				Expect(revision.Status.Module).To(Equal(&terraformv1alphav1.ModuleStatus{
+					Revision: "abc123",
+					Source:   "https://github.com/terraform-aws-modules/terraform-aws-s3-bucket.git?ref=abc123",
+				}))
+			})
+
+			It("should have used the pinned source in the plan", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+
+				container := list.Items[0].Spec.Template.Spec.InitContainers[0]
+				Expect(strings.Join(container.Args, " ")).To(ContainSubstring("--source=https://github.com/terraform-aws-modules/terraform-aws-s3-bucket.git?ref=abc123"))
+				Expect(list.Items[0].GetLabels()[terraformv1alphav1.ConfigurationUpstreamLabel]).ToNot(BeEmpty())
+			})
+
+			It("should not have raised an update event", func() {
+				for _, x := range recorder.Events {
+					Expect(x).ToNot(ContainSubstring("ModuleUpdate"))
+				}
+			})
+		})
+
+		When("the module has an update policy", func() {
+			BeforeEach(func() {
+				configuration.Spec.ModuleUpdatePolicy = terraformv1alphav1.ModuleUpdatePolicyPatch
+				Setup(configuration)
+			})
+
+			JustBeforeEach(func() {
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have recorded the update on the status", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.Module).ToNot(BeNil())
+				Expect(configuration.Status.Module.Update).To(Equal("v3.0.1"))
+			})
+
+			It("should have raised a single update event", func() {
+				var events []string
+				for _, x := range recorder.Events {
+					if strings.Contains(x, "ModuleUpdate") {
+						events = append(events, x)
+					}
+				}
+				Expect(events).To(HaveLen(1))
+				Expect(events[0]).To(ContainSubstring("Module version v3.0.1 is available, currently using v3.0.0"))
+			})
+		})
+
+		When("the module revision has changed since the last apply", func() {
+			BeforeEach(func() {
+				configuration.Status.UpstreamChecksum = upstreamChecksum(map[string]string{"module": "previous"})
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				apply := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformApply)
+				apply.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				apply.Status.Succeeded = 1
+				Setup(configuration, plan, apply)
+			})
+
+			JustBeforeEach(func() {
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have created a new plan", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(3))
+			})
+		})
+	})
+
+	// DEPENDENCIES
+	When("the configuration has dependencies", func() {
+		var dependency *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			dependency = fixtures.NewValidBucketConfiguration(cfgNamespace, "vpc")
+			dependency.UID = "dependency-1234"
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "database")
+			configuration.Spec.DependsOn = []string{"vpc"}
+		})
+
+		When("the dependency does not exist", func() {
+			BeforeEach(func() {
+				Setup(configuration)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should indicate the configuration is waiting", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Waiting for configuration spec.dependsOn[0] (apps/vpc) to be created"))
+			})
+		})
+
+		When("the dependency is not ready", func() {
+			BeforeEach(func() {
+				Setup(configuration, dependency)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should indicate the configuration is waiting", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Waiting for configuration spec.dependsOn[0] (apps/vpc) to be ready"))
+			})
+
+			It("should ask us to requeue", func() {
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+				Expect(rerr).To(BeNil())
+			})
+
+			It("should not create any jobs", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(BeEmpty())
+			})
+		})
+
+		When("the dependency is ready", func() {
+			BeforeEach(func() {
+				dependency.Generation = 1
+				dependency.Status.Conditions = []corev1alphav1.Condition{{
+					Type:               corev1alphav1.ConditionReady,
+					Status:             metav1.ConditionTrue,
+					Reason:             corev1alphav1.ReasonReady,
+					ObservedGeneration: 1,
+				}}
+				Setup(configuration, dependency)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should create the terraform plan", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+		})
+
+		When("deleting a configuration which others depend upon", func() {
+			BeforeEach(func() {
+				now := metav1.Now()
+				dependency.Finalizers = []string{controllerName}
+				dependency.DeletionTimestamp = &now
+				controller.EnsureConditionsRegistered(terraformv1alphav1.DefaultConfigurationConditions, dependency)
+				Setup(configuration, dependency, fixtures.NewTerraformState(dependency))
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, dependency, 1)
+			})
+
+			It("should indicate the configuration is waiting on the dependents", func() {
+				Expect(cc.Get(context.TODO(), dependency.GetNamespacedName(), dependency)).ToNot(HaveOccurred())
+
+				cond := dependency.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Waiting for dependent configurations to be deleted (database)"))
+			})
+
+			It("should ask us to requeue", func() {
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 30 * time.Second}))
+				Expect(rerr).To(BeNil())
+			})
+
+			It("should not create a destroy job", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(BeEmpty())
+			})
+		})
+	})
+
 	// ADDITIONAL SECRETS
 	When("the controller has been configured with additional secrets", func() {
 		BeforeEach(func() {
@@ -925,17 +1361,99 @@ var _ = Describe("Configuration Controller", func() {
 		})
 	})
 
-	When("configuration has not yet run the terraform plan", func() {
-		BeforeEach(func() {
+	When("the provider has a terraform backend", func() {
+		UseBackend := func(objects ...runtime.Object) {
 			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
-			Setup(configuration)
+			Setup(append([]runtime.Object{configuration}, objects...)...)
+
+			provider := &terraformv1alphav1.Provider{}
+			provider.Name = "aws"
+			Expect(cc.Get(context.TODO(), client.ObjectKeyFromObject(provider), provider)).ToNot(HaveOccurred())
+			provider.Spec.Backend = &terraformv1alphav1.Backend{
+				Type:          terraformv1alphav1.S3BackendType,
+				Configuration: &runtime.RawExtension{Raw: []byte(`{"bucket": "state", "region": "eu-west-2"}`)},
+				SecretRef:     &v1.SecretReference{Name: "backend", Namespace: ctrl.ControllerNamespace},
+			}
+			Expect(cc.Update(context.TODO(), provider)).ToNot(HaveOccurred())
+
 			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
-		})
+		}
 
-		It("should have the conditions", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
-			Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
-		})
+		When("the backend secret does not exist", func() {
+			BeforeEach(func() {
+				UseBackend()
+			})
+
+			It("should indicate the backend secret is missing", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionProviderReady)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+				Expect(cond.Message).To(Equal("Terraform backend secret (default/backend) does not exist"))
+			})
+
+			It("should ask us to requeue", func() {
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Minute}))
+				Expect(rerr).To(BeNil())
+			})
+
+			It("should not create any jobs", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(0))
+			})
+		})
+
+		When("the backend secret exists", func() {
+			BeforeEach(func() {
+				secret := &v1.Secret{}
+				secret.Namespace = "default"
+				secret.Name = "backend"
+				secret.Data = map[string][]byte{"AWS_ACCESS_KEY_ID": []byte("id"), "AWS_SECRET_ACCESS_KEY": []byte("secret")}
+
+				UseBackend(secret)
+			})
+
+			It("should have create a s3 backend configuration", func() {
+				secret := &v1.Secret{}
+				secret.Namespace = ctrl.ControllerNamespace
+				secret.Name = configuration.GetTerraformConfigSecretName()
+
+				found, err := kubernetes.GetIfExists(context.TODO(), cc, secret)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				backend := string(secret.Data[terraformv1alphav1.TerraformBackendConfigMapKey])
+				Expect(backend).To(ContainSubstring(`backend "s3"`))
+				Expect(backend).To(ContainSubstring(`bucket = "state"`))
+				Expect(backend).To(ContainSubstring(`key = "1234-122-1234-1234/terraform.tfstate"`))
+			})
+
+			It("should have the backend secret added to the plan", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+
+				job := list.Items[0]
+				Expect(job.Spec.Template.Spec.Containers[0].EnvFrom).To(HaveLen(2))
+				Expect(job.Spec.Template.Spec.Containers[0].EnvFrom[1].SecretRef.Name).To(Equal("backend"))
+			})
+		})
+	})
+
+	When("configuration has not yet run the terraform plan", func() {
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			Setup(configuration)
+			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+		})
+
+		It("should have the conditions", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+			Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
+		})
 
 		It("should indicate the provider is ready", func() {
 			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
@@ -1125,7 +1643,7 @@ terraform {
 				Expect(labels[terraformv1alphav1.ConfigurationStageLabel]).To(Equal(terraformv1alphav1.StageTerraformPlan))
 				Expect(labels[terraformv1alphav1.ConfigurationNameLabel]).To(Equal(configuration.Name))
 				Expect(labels[terraformv1alphav1.ConfigurationNamespaceLabel]).To(Equal(configuration.Namespace))
-				Expect(labels[terraformv1alphav1.DriftAnnotation]).To(Equal("true"))
+				Expect(labels[terraformv1alphav1.DriftAnnotation]).To(BeEmpty())
 			})
 
 			It("should have created a watch job in the configuration namespace", func() {
@@ -1140,7 +1658,7 @@ terraform {
 			})
 		})
 
-		When("drift check has already been run", func() {
+		When("drift check is triggered", func() {
 			BeforeEach(func() {
 				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
 				configuration.Spec.EnableDriftDetection = true
@@ -1152,16 +1670,88 @@ terraform {
 				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
 				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
 				plan.Status.Succeeded = 1
+				delete(plan.Labels, terraformv1alphav1.ConfigurationDriftLabel)
 
 				Setup(configuration, plan)
 				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
 			})
 
-			It("should not create another job", func() {
+			It("should have created a terraform drift job", func() {
 				list := &batchv1.JobList{}
 				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(2))
+
+				var found bool
+				for _, x := range list.Items {
+					if x.Labels[terraformv1alphav1.ConfigurationStageLabel] == terraformv1alphav1.StageTerraformDrift {
+						Expect(x.Labels[terraformv1alphav1.ConfigurationDriftLabel]).To(Equal("true"))
+						found = true
+					}
+				}
+				Expect(found).To(BeTrue())
+			})
 
+			It("should run a refresh-only terraform plan", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace),
+					client.MatchingLabels{terraformv1alphav1.ConfigurationStageLabel: terraformv1alphav1.StageTerraformDrift})).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+
+				args := list.Items[0].Spec.Template.Spec.Containers[0].Args
+				Expect(args).To(ContainElement(ContainSubstring("/bin/terraform plan -refresh-only -detailed-exitcode")))
+			})
+
+			It("should run a terraform plan to classify the drift", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace),
+					client.MatchingLabels{terraformv1alphav1.ConfigurationStageLabel: terraformv1alphav1.StageTerraformDrift})).ToNot(HaveOccurred())
 				Expect(len(list.Items)).To(Equal(1))
+
+				args := list.Items[0].Spec.Template.Spec.Containers[0].Args
+				Expect(args).To(ContainElement(ContainSubstring("-out=/run/config.out")))
+				Expect(args).To(ContainElement(ContainSubstring("--from-file=config.json=/run/config.json.gz")))
+			})
+
+			It("should indicate the drift check is running", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Terraform drift check is running"))
+				Expect(configuration.Status.DriftTimestamp).To(BeEmpty())
+			})
+		})
+
+		When("drift check has already been run", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.EnableDriftDetection = true
+				configuration.Annotations = map[string]string{
+					terraformv1alphav1.DriftAnnotation: "true",
+					terraformv1alphav1.ApplyAnnotation: "false",
+				}
+
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				delete(plan.Labels, terraformv1alphav1.ConfigurationDriftLabel)
+
+				drift := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformDrift)
+				drift.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				drift.Status.Succeeded = 1
+
+				output := fixtures.NewTerraformDriftPlan(configuration, "0")
+				output.Namespace = ctrl.ControllerNamespace
+
+				Setup(configuration, plan, drift, output)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should not create another job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+
+				Expect(len(list.Items)).To(Equal(2))
 			})
 
 			It("should have the conditions", func() {
@@ -1169,7 +1759,13 @@ terraform {
 				Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
 			})
 
-			It("should indicate the terraform plan is running", func() {
+			It("should have an in sync status", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.DriftTimestamp).To(Equal("true"))
+				Expect(configuration.Status.ResourceStatus).To(Equal(terraformv1alphav1.ResourcesInSync))
+			})
+
+			It("should indicate the terraform apply is waiting for approval", func() {
 				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
 
 				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
@@ -1179,158 +1775,510 @@ terraform {
 			})
 		})
 
-		When("drift annotation changes", func() {
+		When("drift check has failed", func() {
 			BeforeEach(func() {
 				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
-				configuration.Spec.EnableAutoApproval = true
-				configuration.Annotations = map[string]string{terraformv1alphav1.DriftAnnotation: "changed"}
-
-				job := &batchv1.Job{}
-				job.Name = "test"
-				job.Namespace = ctrl.ControllerNamespace
-				job.Labels = map[string]string{
-					terraformv1alphav1.ConfigurationGenerationLabel: fmt.Sprintf("%d", configuration.GetGeneration()),
-					terraformv1alphav1.ConfigurationNameLabel:       configuration.Name,
-					terraformv1alphav1.ConfigurationNamespaceLabel:  configuration.Namespace,
-					terraformv1alphav1.ConfigurationStageLabel:      terraformv1alphav1.StageTerraformPlan,
-					terraformv1alphav1.ConfigurationUIDLabel:        string(configuration.GetUID()),
-					terraformv1alphav1.DriftAnnotation:              "different_before",
+				configuration.Spec.EnableDriftDetection = true
+				configuration.Annotations = map[string]string{
+					terraformv1alphav1.DriftAnnotation: "true",
+					terraformv1alphav1.ApplyAnnotation: "false",
 				}
-				Setup(configuration, job)
-				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
-			})
 
-			It("should indicate the terraform plan is running", func() {
-				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				delete(plan.Labels, terraformv1alphav1.ConfigurationDriftLabel)
 
-				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
-				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
-				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
-				Expect(cond.Message).To(Equal("Terraform plan is running"))
-			})
+				drift := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformDrift)
+				drift.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v1.ConditionTrue}}
+				drift.Status.Failed = 1
 
-			It("should have an out of sync status", func() {
-				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
-				Expect(configuration.Status.ResourceStatus).To(Equal(terraformv1alphav1.ResourcesOutOfSync))
+				Setup(configuration, plan, drift)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
 			})
 
-			It("should create another job", func() {
+			It("should not retry the drift check", func() {
 				list := &batchv1.JobList{}
 				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
 				Expect(len(list.Items)).To(Equal(2))
+
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.DriftTimestamp).To(Equal("true"))
+			})
+
+			It("should have raised an event", func() {
+				Expect(recorder.Events).To(ContainElement(
+					"(apps/bucket) Warning DriftDetection: Terraform drift check has failed, check the logs of the drift job",
+				))
 			})
 		})
-	})
 
-	// CHECKOV
-	When("checkov is configured", func() {
-		When("configuration matches a policy", func() {
+		When("drift check has detected drift", func() {
 			BeforeEach(func() {
 				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
-				Setup(configuration)
-				constraint := fixtures.NewMatchAllPolicyConstraint("all")
-				constraint.Spec.Constraints.Checkov.Checks = []string{"check0", "check1"}
-				constraint.Spec.Constraints.Checkov.SkipChecks = nil
-
-				Expect(ctrl.cc.Create(context.TODO(), constraint)).ToNot(HaveOccurred())
+				configuration.Spec.EnableDriftDetection = true
+				configuration.Annotations = map[string]string{
+					terraformv1alphav1.DriftAnnotation: "true",
+					terraformv1alphav1.ApplyAnnotation: "false",
+				}
 
-				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
-			})
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				delete(plan.Labels, terraformv1alphav1.ConfigurationDriftLabel)
 
-			It("should have conditions", func() {
-				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
-				Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
-			})
+				drift := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformDrift)
+				drift.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				drift.Status.Succeeded = 1
 
-			It("should created a terraform plan job", func() {
-				list := &batchv1.JobList{}
+				output := fixtures.NewTerraformDriftPlan(configuration, "2")
+				output.Namespace = ctrl.ControllerNamespace
 
-				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
-				Expect(len(list.Items)).To(Equal(1))
+				Setup(configuration, plan, drift, output)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
 			})
 
-			It("should add a verify-policy container to the job", func() {
-				list := &batchv1.JobList{}
+			It("should have an out of sync status", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.ResourceStatus).To(Equal(terraformv1alphav1.ResourcesOutOfSync))
+			})
 
-				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
-				Expect(len(list.Items)).To(Equal(1))
-				job := list.Items[0]
+			It("should summarise the drift on the status", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
 
-				Expect(len(job.Spec.Template.Spec.Containers)).To(Equal(2))
-				Expect(job.Spec.Template.Spec.Containers[1].Name).To(Equal("verify-policy"))
-				Expect(job.Spec.Template.Spec.Containers[1].Command).To(Equal([]string{"/run/bin/step"}))
-				Expect(job.Spec.Template.Spec.Containers[1].Args).To(Equal(verifyPolicyArguments))
-				Expect(job.Spec.Template.Spec.Containers[1].VolumeMounts).To(HaveLen(3))
-				Expect(job.Spec.Template.Spec.Containers[1].VolumeMounts[0].Name).To(Equal("checkov"))
-				Expect(job.Spec.Template.Spec.Containers[1].VolumeMounts[0].MountPath).To(Equal("/run/checkov"))
+				drift := configuration.Status.Drift
+				Expect(drift).ToNot(BeNil())
+				Expect(drift.CheckedAt).ToNot(BeNil())
+				Expect(drift.ConfigChanges).To(Equal(1))
+				Expect(drift.RefreshOnlyChanges).To(Equal(1))
+				Expect(drift.ReportSecret).To(Equal(configuration.GetTerraformDriftSecretName()))
+				Expect(drift.Summary).To(Equal("1 config change, 1 refresh-only change"))
+				Expect(drift.Resources).To(Equal([]terraformv1alphav1.DriftResource{
+					{Action: "update", Address: "aws_s3_bucket_acl.this", Kind: terraform.DriftKindRefreshOnly},
+					{Action: "create", Address: "aws_s3_bucket_policy.this", Kind: terraform.DriftKindConfig},
+				}))
 			})
 
-			It("should have a checkov configuration secret", func() {
+			It("should have created the drift report in the configuration namespace", func() {
 				secret := &v1.Secret{}
-				secret.Namespace = ctrl.ControllerNamespace
-				secret.Name = configuration.GetTerraformConfigSecretName()
+				secret.Namespace = configuration.Namespace
+				secret.Name = configuration.GetTerraformDriftSecretName()
 
-				found, err := kubernetes.GetIfExists(context.TODO(), ctrl.cc, secret)
+				found, err := kubernetes.GetIfExists(context.TODO(), cc, secret)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(found).To(BeTrue())
-				Expect(secret.Data).To(HaveKey(terraformv1alphav1.CheckovJobTemplateConfigMapKey))
-				Expect(string(secret.Data[terraformv1alphav1.CheckovJobTemplateConfigMapKey])).To(Equal("framework:\n  - terraform_plan\nsoft-fail: true\ncompact: true\ncheck:\n  - check0\n  - check1"))
+				Expect(secret.OwnerReferences).To(HaveLen(1))
+				Expect(secret.OwnerReferences[0].UID).To(Equal(configuration.GetUID()))
+				Expect(secret.Data).To(HaveKey(terraformv1alphav1.TerraformDriftSecretKey))
+
+				report := &terraform.DriftReport{}
+				Expect(json.Unmarshal(secret.Data[terraformv1alphav1.TerraformDriftSecretKey], report)).ToNot(HaveOccurred())
+				Expect(report.Resources).To(HaveLen(2))
+				Expect(report.Resources[0].Attributes).To(Equal([]terraform.DriftAttribute{
+					{Name: "acl", Before: `"private"`, After: `"public-read"`},
+				}))
+			})
+
+			It("should not have run another terraform plan", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(2))
 			})
 		})
 
-		When("configuration matches multiple policies, highest priority wins", func() {
+		When("drift is detected and remediation is automatic", func() {
 			BeforeEach(func() {
 				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
-				Setup(configuration)
+				configuration.Spec.DriftRemediation = terraformv1alphav1.DriftRemediationAuto
+				configuration.Spec.EnableDriftDetection = true
+				configuration.Annotations = map[string]string{
+					terraformv1alphav1.DriftAnnotation: "true",
+					terraformv1alphav1.ApplyAnnotation: "true",
+				}
 
-				// @notes: we add two policies here, the later one with the namespace should take priority
-				// given the namespace selector.
+				// @note: the plan which previously completed for this generation
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Name = "previous-plan"
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				delete(plan.Labels, terraformv1alphav1.ConfigurationDriftLabel)
 
-				all := fixtures.NewMatchAllPolicyConstraint("all")
-				all.Spec.Constraints.Checkov.Checks = []string{"check0"}
+				drift := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformDrift)
+				drift.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				drift.Status.Succeeded = 1
 
-				priority := fixtures.NewMatchAllPolicyConstraint("priority")
-				priority.Spec.Constraints.Checkov.Checks = []string{"priority"}
-				priority.Spec.Constraints.Checkov.Selector = &terraformv1alphav1.Selector{}
-				priority.Spec.Constraints.Checkov.Selector.Namespace = &metav1.LabelSelector{
-					MatchLabels: map[string]string{"name": cfgNamespace},
-				}
+				output := fixtures.NewTerraformDriftPlan(configuration, "2")
+				output.Namespace = ctrl.ControllerNamespace
 
-				Expect(ctrl.cc.Create(context.TODO(), all)).ToNot(HaveOccurred())
-				Expect(ctrl.cc.Create(context.TODO(), priority)).ToNot(HaveOccurred())
+				Setup(configuration, plan, drift, output)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
 
-				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			It("should have a pending remediation", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.Drift).ToNot(BeNil())
+				Expect(configuration.Status.Drift.Remediation).To(Equal(terraformv1alphav1.RemediationPending))
 			})
 
-			It("should have conditions", func() {
+			It("should have removed the previous approval", func() {
 				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
-				Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
+				Expect(configuration.GetAnnotations()).ToNot(HaveKey(terraformv1alphav1.ApplyAnnotation))
 			})
 
-			It("should created a terraform plan job", func() {
+			It("should have created a terraform plan job for the drift", func() {
 				list := &batchv1.JobList{}
-
 				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
-				Expect(len(list.Items)).To(Equal(1))
+
+				var found bool
+				for _, x := range list.Items {
+					if x.Labels[terraformv1alphav1.ConfigurationStageLabel] == terraformv1alphav1.StageTerraformPlan && x.Name != "previous-plan" {
+						Expect(x.Labels[terraformv1alphav1.ConfigurationDriftLabel]).To(Equal("true"))
+						found = true
+					}
+				}
+				Expect(found).To(BeTrue())
 			})
 
-			It("should have selected priority policy", func() {
-				list := &batchv1.JobList{}
+			It("should indicate the terraform apply is pending", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
 
-				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
-				Expect(len(list.Items)).To(Equal(1))
-				job := list.Items[0]
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Terraform apply is pending to remediate the drift"))
+			})
 
-				Expect(len(job.Spec.Template.Spec.Containers)).To(Equal(2))
-				Expect(job.Spec.Template.Spec.Containers[1].Name).To(Equal("verify-policy"))
-				Expect(job.Spec.Template.Spec.Containers[1].Command).To(Equal([]string{"/run/bin/step"}))
-				Expect(job.Spec.Template.Spec.Containers[1].Args).To(Equal(verifyPolicyArguments))
+			It("should have raised an event for the remediation", func() {
+				Expect(recorder.Events).To(ContainElement(
+					"(apps/bucket) Normal DriftRemediation: Drift has been detected in the resource (1 config change, 1 refresh-only change), applying the configuration to remediate",
+				))
 			})
+		})
 
-			It("should have a checkov configuration secret", func() {
-				secret := &v1.Secret{}
-				secret.Namespace = ctrl.ControllerNamespace
-				secret.Name = configuration.GetTerraformConfigSecretName()
+		When("drift remediation is automatic and the plan replaces a protected resource", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.DriftRemediation = terraformv1alphav1.DriftRemediationAuto
+				configuration.Spec.EnableDriftDetection = true
+				configuration.Spec.DestroyProtection = &terraformv1alphav1.DestroyProtection{
+					ResourceTypes: []string{"aws_s3_bucket_policy"},
+				}
+				configuration.Annotations = map[string]string{
+					terraformv1alphav1.DriftAnnotation: "true",
+					terraformv1alphav1.ApplyAnnotation: "true",
+				}
+
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Name = "previous-plan"
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				delete(plan.Labels, terraformv1alphav1.ConfigurationDriftLabel)
+
+				drift := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformDrift)
+				drift.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				drift.Status.Succeeded = 1
+
+				output := fixtures.NewTerraformDriftPlan(configuration, "2")
+				output.Namespace = ctrl.ControllerNamespace
+
+				Setup(configuration, plan, drift, output)
+				_, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+				Expect(rerr).ToNot(HaveOccurred())
+
+				// @step: complete the plan created to remediate the drift
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				for i := range list.Items {
+					x := &list.Items[i]
+					if x.Labels[terraformv1alphav1.ConfigurationStageLabel] == terraformv1alphav1.StageTerraformPlan && x.Name != "previous-plan" {
+						x.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+						x.Status.Succeeded = 1
+						Expect(cc.Status().Update(context.TODO(), x)).ToNot(HaveOccurred())
+					}
+				}
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+				Expect(cc.Create(context.TODO(), saved)).ToNot(HaveOccurred())
+
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should indicate the apply requires approval", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+				Expect(cond.Message).To(Equal("Terraform plan destroys or replaces protected resources (aws_s3_bucket_policy.this), waiting for terraform apply annotation to be set to true"))
+			})
+
+			It("should have annotated the configuration for approval", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.GetAnnotations()[terraformv1alphav1.ApplyAnnotation]).To(Equal("false"))
+			})
+
+			It("should not have created an apply job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				for _, x := range list.Items {
+					Expect(x.Labels[terraformv1alphav1.ConfigurationStageLabel]).ToNot(Equal(terraformv1alphav1.StageTerraformApply))
+				}
+			})
+		})
+
+		When("drift is detected and remediation requires approval", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.DriftRemediation = terraformv1alphav1.DriftRemediationApproval
+				configuration.Spec.EnableAutoApproval = true
+				configuration.Spec.EnableDriftDetection = true
+				configuration.Annotations = map[string]string{
+					terraformv1alphav1.DriftAnnotation: "true",
+					terraformv1alphav1.ApplyAnnotation: "true",
+				}
+
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				delete(plan.Labels, terraformv1alphav1.ConfigurationDriftLabel)
+
+				drift := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformDrift)
+				drift.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				drift.Status.Succeeded = 1
+
+				output := fixtures.NewTerraformDriftPlan(configuration, "2")
+				output.Namespace = ctrl.ControllerNamespace
+
+				Setup(configuration, plan, drift, output)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should have revoked the approval", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.GetAnnotations()[terraformv1alphav1.ApplyAnnotation]).To(Equal("false"))
+			})
+
+			It("should be waiting for the approval", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.Drift.Remediation).To(Equal(terraformv1alphav1.RemediationPending))
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+				Expect(cond.Message).To(Equal("Drift has been detected in the resource (1 config change, 1 refresh-only change), waiting for terraform apply annotation to be set to true"))
+			})
+
+			It("should not have created a terraform apply job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+
+				for _, x := range list.Items {
+					Expect(x.Labels[terraformv1alphav1.ConfigurationStageLabel]).ToNot(Equal(terraformv1alphav1.StageTerraformApply))
+				}
+			})
+		})
+
+		When("drift remediation has been applied", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.DriftRemediation = terraformv1alphav1.DriftRemediationApproval
+				configuration.Spec.EnableDriftDetection = true
+				configuration.Annotations = map[string]string{
+					terraformv1alphav1.DriftAnnotation: "true",
+					terraformv1alphav1.ApplyAnnotation: "true",
+				}
+				configuration.Status.DriftTimestamp = "true"
+				configuration.Status.Drift = &terraformv1alphav1.DriftStatus{Remediation: terraformv1alphav1.RemediationPending}
+
+				job := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				job.Status.Succeeded = 1
+
+				apply := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformApply)
+				apply.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				apply.Status.Succeeded = 1
+
+				plan := fixtures.NewTerraformPlan(configuration)
+				plan.Namespace = ctrl.ControllerNamespace
+
+				Setup(configuration, job, apply, plan)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should have remediated the drift", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.Drift.Remediation).To(Equal(terraformv1alphav1.RemediationApplied))
+				Expect(configuration.Status.ResourceStatus).To(Equal(terraformv1alphav1.ResourcesInSync))
+			})
+
+			It("should have completed the terraform apply", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonReady))
+				Expect(cond.Message).To(Equal("Terraform apply is complete"))
+			})
+
+			It("should have raised an event for the remediation", func() {
+				Expect(recorder.Events).To(ContainElement("(apps/bucket) Normal DriftRemediation: Drift has been remediated by the terraform apply"))
+			})
+		})
+
+		When("drift annotation changes", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.EnableAutoApproval = true
+				configuration.Spec.EnableDriftDetection = true
+				configuration.Annotations = map[string]string{terraformv1alphav1.DriftAnnotation: "changed"}
+				configuration.Status.DriftTimestamp = "different_before"
+
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				delete(plan.Labels, terraformv1alphav1.ConfigurationDriftLabel)
+
+				job := &batchv1.Job{}
+				job.Name = "test"
+				job.Namespace = ctrl.ControllerNamespace
+				job.Labels = map[string]string{
+					terraformv1alphav1.ConfigurationGenerationLabel: fmt.Sprintf("%d", configuration.GetGeneration()),
+					terraformv1alphav1.ConfigurationNameLabel:       configuration.Name,
+					terraformv1alphav1.ConfigurationNamespaceLabel:  configuration.Namespace,
+					terraformv1alphav1.ConfigurationStageLabel:      terraformv1alphav1.StageTerraformDrift,
+					terraformv1alphav1.ConfigurationUIDLabel:        string(configuration.GetUID()),
+					terraformv1alphav1.DriftAnnotation:              "different_before",
+				}
+				job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				job.Status.Succeeded = 1
+
+				Setup(configuration, plan, job)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should indicate the drift check is running", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Terraform drift check is running"))
+			})
+
+			It("should create another drift job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace),
+					client.MatchingLabels{terraformv1alphav1.ConfigurationStageLabel: terraformv1alphav1.StageTerraformDrift})).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(2))
+			})
+		})
+	})
+
+	// CHECKOV
+	When("checkov is configured", func() {
+		When("configuration matches a policy", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				Setup(configuration)
+				constraint := fixtures.NewMatchAllPolicyConstraint("all")
+				constraint.Spec.Constraints.Checkov.Checks = []string{"check0", "check1"}
+				constraint.Spec.Constraints.Checkov.SkipChecks = nil
+
+				Expect(ctrl.cc.Create(context.TODO(), constraint)).ToNot(HaveOccurred())
+
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have conditions", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
+			})
+
+			It("should created a terraform plan job", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+			})
+
+			It("should add a verify-policy container to the job", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+				job := list.Items[0]
+
+				Expect(len(job.Spec.Template.Spec.Containers)).To(Equal(2))
+				Expect(job.Spec.Template.Spec.Containers[1].Name).To(Equal("verify-policy"))
+				Expect(job.Spec.Template.Spec.Containers[1].Command).To(Equal([]string{"/run/bin/step"}))
+				Expect(job.Spec.Template.Spec.Containers[1].Args).To(Equal(verifyPolicyArguments))
+				Expect(job.Spec.Template.Spec.Containers[1].VolumeMounts).To(HaveLen(3))
+				Expect(job.Spec.Template.Spec.Containers[1].VolumeMounts[0].Name).To(Equal("checkov"))
+				Expect(job.Spec.Template.Spec.Containers[1].VolumeMounts[0].MountPath).To(Equal("/run/checkov"))
+			})
+
+			It("should have a checkov configuration secret", func() {
+				secret := &v1.Secret{}
+				secret.Namespace = ctrl.ControllerNamespace
+				secret.Name = configuration.GetTerraformConfigSecretName()
+
+				found, err := kubernetes.GetIfExists(context.TODO(), ctrl.cc, secret)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(secret.Data).To(HaveKey(terraformv1alphav1.CheckovJobTemplateConfigMapKey))
+				Expect(string(secret.Data[terraformv1alphav1.CheckovJobTemplateConfigMapKey])).To(Equal("framework:\n  - terraform_plan\nsoft-fail: true\ncompact: true\ncheck:\n  - check0\n  - check1"))
+			})
+		})
+
+		When("configuration matches multiple policies, highest priority wins", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				Setup(configuration)
+
+				// @notes: we add two policies here, the later one with the namespace should take priority
+				// given the namespace selector.
+
+				all := fixtures.NewMatchAllPolicyConstraint("all")
+				all.Spec.Constraints.Checkov.Checks = []string{"check0"}
+
+				priority := fixtures.NewMatchAllPolicyConstraint("priority")
+				priority.Spec.Constraints.Checkov.Checks = []string{"priority"}
+				priority.Spec.Constraints.Checkov.Selector = &terraformv1alphav1.Selector{}
+				priority.Spec.Constraints.Checkov.Selector.Namespace = &metav1.LabelSelector{
+					MatchLabels: map[string]string{"name": cfgNamespace},
+				}
+
+				Expect(ctrl.cc.Create(context.TODO(), all)).ToNot(HaveOccurred())
+				Expect(ctrl.cc.Create(context.TODO(), priority)).ToNot(HaveOccurred())
+
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have conditions", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
+			})
+
+			It("should created a terraform plan job", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+			})
+
+			It("should have selected priority policy", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+				job := list.Items[0]
+
+				Expect(len(job.Spec.Template.Spec.Containers)).To(Equal(2))
+				Expect(job.Spec.Template.Spec.Containers[1].Name).To(Equal("verify-policy"))
+				Expect(job.Spec.Template.Spec.Containers[1].Command).To(Equal([]string{"/run/bin/step"}))
+				Expect(job.Spec.Template.Spec.Containers[1].Args).To(Equal(verifyPolicyArguments))
+			})
+
+			It("should have a checkov configuration secret", func() {
+				secret := &v1.Secret{}
+				secret.Namespace = ctrl.ControllerNamespace
+				secret.Name = configuration.GetTerraformConfigSecretName()
 
 				found, err := kubernetes.GetIfExists(context.TODO(), ctrl.cc, secret)
 				Expect(err).ToNot(HaveOccurred())
@@ -1452,8 +2400,11 @@ terraform {
 				report.Namespace = ctrl.ControllerNamespace
 				report.Name = configuration.GetTerraformPolicySecretName()
 				report.Data = map[string][]byte{"results_json.json": []byte(`{"summary":{"failed": 1}}`)}
+				// create fake saved plan
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
 
-				Setup(configuration, policy, plan, report)
+				Setup(configuration, policy, plan, report, saved)
 			})
 
 			When("policy report is missing due to interval error", func() {
@@ -1711,6 +2662,15 @@ terraform {
 				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
 				Expect(len(list.Items)).To(Equal(1))
 			})
+
+			It("should have recorded the revision as waiting on an action", func() {
+				revision := terraformv1alphav1.NewConfigurationRevision(cfgNamespace, "bucket-0")
+
+				Expect(cc.Get(context.TODO(), client.ObjectKeyFromObject(revision), revision)).ToNot(HaveOccurred())
+				Expect(revision.Status.Outcome).To(Equal(terraformv1alphav1.RevisionActionRequired))
+				Expect(revision.Status.ApprovedBy).To(BeEmpty())
+				Expect(revision.Status.CompletionTime).To(BeNil())
+			})
 		})
 
 		When("the configuration is approved", func() {
@@ -1719,7 +2679,9 @@ terraform {
 				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
 				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
 				plan.Status.Succeeded = 1
-				Setup(configuration, plan)
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, plan, saved)
 				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
 			})
 
@@ -1737,6 +2699,18 @@ terraform {
 				Expect(cond.Message).To(Equal("Terraform plan is complete"))
 			})
 
+			It("should have a summary of the terraform plan", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				Expect(configuration.Status.Plan).ToNot(BeNil())
+				Expect(configuration.Status.Plan.Add).To(Equal(1))
+				Expect(configuration.Status.Plan.Change).To(Equal(0))
+				Expect(configuration.Status.Plan.Destroy).To(Equal(1))
+				Expect(configuration.Status.Plan.Replace).To(Equal(0))
+				Expect(configuration.Status.Plan.Summary).To(Equal("1 to add, 1 to destroy"))
+				Expect(configuration.Status.Plan.Resources).To(HaveLen(2))
+			})
+
 			It("should have created job for the terraform apply", func() {
 				list := &batchv1.JobList{}
 
@@ -1774,149 +2748,1785 @@ terraform {
 				Expect(rerr).To(BeNil())
 			})
 		})
-	})
 
-	// AFTER SUCCESSFUL APPLY
-	When("terraform apply has been provisioned", func() {
-		BeforeEach(func() {
-			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
-			// create two successful jobs
+		When("the configuration is approved but the saved plan is missing", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Annotations = map[string]string{terraformv1alphav1.ApplyAnnotation: "true"}
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				Setup(configuration, plan)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 2)
+			})
+
+			It("should indicate a new plan is required", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonWarning))
+				Expect(cond.Message).To(Equal("Saved terraform plan is missing or stale, a new plan is required"))
+			})
+
+			It("should have removed the plan and not created an apply job", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(BeEmpty())
+			})
+
+			It("should require the new plan to be approved", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.GetAnnotations()[terraformv1alphav1.ApplyAnnotation]).To(Equal("false"))
+			})
+
+			It("should ask us to requeue", func() {
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+				Expect(rerr).To(BeNil())
+			})
+		})
+
+		When("the configuration is approved but the saved plan is from another generation", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.EnableAutoApproval = true
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+				saved.Labels[terraformv1alphav1.ConfigurationGenerationLabel] = "100"
+				Setup(configuration, plan, saved)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 2)
+			})
+
+			It("should indicate a new plan is required", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonWarning))
+				Expect(cond.Message).To(Equal("Saved terraform plan is missing or stale, a new plan is required"))
+			})
+
+			It("should have removed the plan and not created an apply job", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(BeEmpty())
+			})
+
+			It("should not have added an approval annotation", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.GetAnnotations()).ToNot(HaveKey(terraformv1alphav1.ApplyAnnotation))
+			})
+
+			It("should have recorded the reset on the status", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.PlanResets).To(Equal(1))
+			})
+
+			When("the discarded plan job is still present", func() {
+				var discarded *batchv1.Job
+
+				BeforeEach(func() {
+					Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+					discarded = fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+					discarded.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+					discarded.Status.Succeeded = 1
+					Expect(cc.Create(context.TODO(), discarded)).To(Succeed())
+
+					result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 1)
+				})
+
+				It("should create a new plan job rather than adopt the discarded one", func() {
+					list := &batchv1.JobList{}
+					Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+					Expect(list.Items).To(HaveLen(2))
+
+					var created *batchv1.Job
+					for i := 0; i < len(list.Items); i++ {
+						if list.Items[i].Name != discarded.Name {
+							created = &list.Items[i]
+						}
+					}
+					Expect(created).ToNot(BeNil())
+					Expect(created.Labels[terraformv1alphav1.ConfigurationStageLabel]).To(Equal(terraformv1alphav1.StageTerraformPlan))
+					Expect(created.Labels[terraformv1alphav1.ConfigurationResetLabel]).To(Equal("1"))
+				})
+
+				It("should indicate the new plan is in progress", func() {
+					Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+					cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+					Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+					Expect(cond.Message).To(Equal("Terraform plan in progress"))
+				})
+
+				It("should not reset the plan again", func() {
+					Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+					Expect(configuration.Status.PlanResets).To(Equal(1))
+				})
+			})
+		})
+
+		When("the plan destroys a resource protected by the configuration", func() {
+			var annotations map[string]string
+
+			JustBeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Annotations = annotations
+				configuration.Spec.EnableAutoApproval = true
+				configuration.Spec.DestroyProtection = &terraformv1alphav1.DestroyProtection{
+					ResourceTypes: []string{"aws_s3_bucket_policy"},
+				}
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, plan, saved)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			When("the configuration has not been approved", func() {
+				BeforeEach(func() {
+					annotations = nil
+				})
+
+				It("should indicate the apply requires approval", func() {
+					Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+					cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+					Expect(cond.Message).To(Equal("Terraform plan destroys or replaces protected resources (aws_s3_bucket_policy.this), waiting for terraform apply annotation to be set to true"))
+				})
+
+				It("should have annotated the configuration for approval", func() {
+					Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+					Expect(configuration.GetAnnotations()[terraformv1alphav1.ApplyAnnotation]).To(Equal("false"))
+				})
+
+				It("should not have created an apply job", func() {
+					list := &batchv1.JobList{}
+
+					Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+					Expect(len(list.Items)).To(Equal(1))
+				})
+
+				It("should not ask us to requeue", func() {
+					Expect(result).To(Equal(reconcile.Result{}))
+					Expect(rerr).To(BeNil())
+				})
+			})
+
+			When("the configuration has been approved", func() {
+				BeforeEach(func() {
+					annotations = map[string]string{terraformv1alphav1.ApplyAnnotation: "true"}
+				})
+
+				It("should have created an apply job", func() {
+					list := &batchv1.JobList{}
+
+					Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+					Expect(len(list.Items)).To(Equal(2))
+				})
+			})
+		})
+
+		When("the plan does not destroy a resource protected by the configuration", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.EnableAutoApproval = true
+				configuration.Spec.DestroyProtection = &terraformv1alphav1.DestroyProtection{
+					ResourceTypes: []string{"aws_db_instance"},
+				}
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, plan, saved)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should indicate the terraform apply is running", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+			})
+
+			It("should have created an apply job", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(2))
+			})
+		})
+
+		When("the plan destroys resources and a policy protects all resources", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.EnableAutoApproval = true
+				policy := fixtures.NewMatchAllPolicyConstraint("all")
+				policy.Spec.Constraints.Checkov = nil
+				policy.Spec.Constraints.DestroyProtection = &terraformv1alphav1.DestroyProtectionConstraint{AllResources: true}
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, policy, plan, saved)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should indicate the apply requires approval", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+				Expect(cond.Message).To(ContainSubstring("destroys or replaces protected resources"))
+			})
+
+			It("should not have created an apply job", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+			})
+		})
+	})
+
+	// APPLY WINDOWS
+	When("the configuration has an apply window", func() {
+		var opens time.Time
+
+		// closedWindow returns a window which is closed now and next opens in two hours
+		closedWindow := func() terraformv1alphav1.ApplyWindow {
+			opens = time.Now().UTC().Add(2 * time.Hour).Truncate(time.Minute)
+
+			return terraformv1alphav1.ApplyWindow{
+				Schedule: fmt.Sprintf("%d %d * * *", opens.Minute(), opens.Hour()),
+				Duration: metav1.Duration{Duration: time.Hour},
+			}
+		}
+
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+		})
+
+		JustBeforeEach(func() {
 			plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
 			plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
 			plan.Status.Succeeded = 1
+			saved := fixtures.NewTerraformPlan(configuration)
+			saved.Namespace = ctrl.ControllerNamespace
+			Setup(configuration, plan, saved)
+		})
+
+		When("the apply window is closed", func() {
+			BeforeEach(func() {
+				window := closedWindow()
+				configuration.Spec.ApplyWindow = &window
+			})
+
+			JustBeforeEach(func() {
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should indicate the apply is waiting for the window", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Waiting for the apply window to open at " + opens.Format(time.RFC3339)))
+			})
+
+			It("should requeue when the window opens", func() {
+				Expect(rerr).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically("~", time.Until(opens), time.Minute))
+			})
+
+			It("should not have created the apply job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+		})
+
+		When("the apply window is open", func() {
+			BeforeEach(func() {
+				configuration.Spec.ApplyWindow = &terraformv1alphav1.ApplyWindow{
+					Schedule: "* * * * *",
+					Duration: metav1.Duration{Duration: time.Hour},
+					TimeZone: "Europe/London",
+				}
+			})
+
+			JustBeforeEach(func() {
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should have created the apply job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(2))
+			})
+		})
+
+		When("a policy defines a closed apply window", func() {
+			JustBeforeEach(func() {
+				policy := fixtures.NewPolicy("windows")
+				policy.Spec.Constraints = &terraformv1alphav1.Constraints{
+					ApplyWindow: &terraformv1alphav1.ApplyWindowConstraint{ApplyWindow: closedWindow()},
+				}
+				Expect(cc.Create(context.TODO(), policy)).To(Succeed())
+
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should hold the apply until the window opens", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Waiting for the apply window to open at " + opens.Format(time.RFC3339)))
+			})
+
+			When("the configuration defines its own apply window", func() {
+				BeforeEach(func() {
+					configuration.Spec.ApplyWindow = &terraformv1alphav1.ApplyWindow{
+						Schedule: "* * * * *",
+						Duration: metav1.Duration{Duration: time.Hour},
+					}
+				})
+
+				It("should use the window on the configuration", func() {
+					list := &batchv1.JobList{}
+					Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+					Expect(list.Items).To(HaveLen(2))
+				})
+			})
+		})
+	})
+
+	// IMPORTS
+	When("configuration is importing existing resources", func() {
+		When("the import state secret does not exist", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.ImportState = &v1.SecretReference{Name: "state"}
+				Setup(configuration)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should indicate the import state secret is missing", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+				Expect(cond.Message).To(Equal("Import state secret (apps/state) does not exist"))
+			})
+
+			It("should not create any jobs", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(0))
+			})
+		})
+
+		When("the import state secret exists", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.ImportState = &v1.SecretReference{Name: "state"}
+				imported := fixtures.NewTerraformState(configuration)
+				imported.Namespace = cfgNamespace
+				imported.Name = "state"
+				imported.Data = map[string][]byte{
+					terraformv1alphav1.ImportStateSecretKey: imported.Data[terraformv1alphav1.TerraformStateSecretKey],
+				}
+				Setup(configuration, imported)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have created the terraform state", func() {
+				secret := &v1.Secret{}
+				secret.Namespace = ctrl.ControllerNamespace
+				secret.Name = configuration.GetTerraformStateSecretName()
+
+				found, err := kubernetes.GetIfExists(context.TODO(), cc, secret)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(secret.GetLabels()).To(HaveKeyWithValue("tfstateSecretSuffix", string(configuration.GetUID())))
+				Expect(secret.Data).To(HaveKey(terraformv1alphav1.TerraformStateSecretKey))
+			})
+
+			It("should have created a plan", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+			})
+		})
+
+		When("the configuration has imports", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.Imports = []terraformv1alphav1.Import{{Address: "aws_s3_bucket.this", ID: "bucket"}}
+				Setup(configuration)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have created the import blocks", func() {
+				secret := &v1.Secret{}
+				secret.Namespace = ctrl.ControllerNamespace
+				secret.Name = configuration.GetTerraformConfigSecretName()
+
+				found, err := kubernetes.GetIfExists(context.TODO(), cc, secret)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(string(secret.Data[terraformv1alphav1.TerraformImportsConfigMapKey])).To(ContainSubstring("to = aws_s3_bucket.this"))
+			})
+
+			It("should have mounted the import blocks into the plan", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+
+				var items []string
+				for _, volume := range list.Items[0].Spec.Template.Spec.Volumes {
+					if volume.Name == "config" {
+						for _, x := range volume.Secret.Items {
+							items = append(items, x.Key)
+						}
+					}
+				}
+				Expect(items).To(ContainElement(terraformv1alphav1.TerraformImportsConfigMapKey))
+			})
+		})
+
+		When("the plan creates a resource which should have been imported", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Spec.EnableAutoApproval = true
+				configuration.Spec.Imports = []terraformv1alphav1.Import{{Address: "aws_s3_bucket.this", ID: "bucket"}}
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, plan, saved)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should indicate the import did not match", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+				Expect(cond.Message).To(Equal("Terraform plan creates resources which should have been imported (aws_s3_bucket.this), check the spec.imports"))
+			})
+
+			It("should not have created an apply job", func() {
+				list := &batchv1.JobList{}
+
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(len(list.Items)).To(Equal(1))
+			})
+		})
+
+		When("the plan creates resources not found in the imported state", func() {
+			var annotations map[string]string
+
+			JustBeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Annotations = annotations
+				configuration.Spec.EnableAutoApproval = true
+				configuration.Spec.ImportState = &v1.SecretReference{Name: "state"}
+				tfstate := fixtures.NewTerraformState(configuration)
+				tfstate.Namespace = ctrl.ControllerNamespace
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, tfstate, plan, saved)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			When("the configuration has not been approved", func() {
+				BeforeEach(func() {
+					annotations = nil
+				})
+
+				It("should indicate the apply requires approval", func() {
+					Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+					cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+					Expect(cond.Message).To(Equal("Terraform plan creates resources not found in the imported state (aws_s3_bucket.this), waiting for terraform apply annotation to be set to true"))
+				})
+
+				It("should have annotated the configuration for approval", func() {
+					Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+					Expect(configuration.GetAnnotations()[terraformv1alphav1.ApplyAnnotation]).To(Equal("false"))
+				})
+			})
+
+			When("the configuration has been approved", func() {
+				BeforeEach(func() {
+					annotations = map[string]string{terraformv1alphav1.ApplyAnnotation: "true"}
+				})
+
+				It("should have created an apply job", func() {
+					list := &batchv1.JobList{}
+
+					Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+					Expect(len(list.Items)).To(Equal(2))
+				})
+			})
+		})
+	})
+
+	// AFTER SUCCESSFUL APPLY
+	When("terraform apply has been provisioned", func() {
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			// create two successful jobs
+			plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+			plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+			plan.Status.Succeeded = 1
+
+			apply := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformApply)
+			apply.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+			apply.Status.Succeeded = 1
+
+			// create a fake terraform state
+			state := fixtures.NewTerraformState(configuration)
+			state.Namespace = "default"
+
+			Setup(configuration, plan, apply, state)
+			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+		})
+
+		It("should have the conditions", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+			Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
+		})
+
+		It("should indicate the terraform apply has run", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+			cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(corev1alphav1.ReasonReady))
+			Expect(cond.Message).To(Equal("Terraform apply is complete"))
+		})
+
+		It("should a last reconciliation time on the status", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+			Expect(configuration.Status.LastReconcile).ToNot(BeNil())
+			Expect(configuration.Status.LastReconcile.Time).ToNot(BeNil())
+			Expect(configuration.Status.LastReconcile.Generation).To(Equal(int64(0)))
+		})
+
+		It("should a last success reconciliation time on the status", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+			Expect(configuration.Status.LastSuccess).ToNot(BeNil())
+			Expect(configuration.Status.LastSuccess.Time).ToNot(BeNil())
+			Expect(configuration.Status.LastSuccess.Generation).To(Equal(int64(0)))
+		})
+
+		It("should have a version on status", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+			Expect(configuration.Status.TerraformVersion).To(Equal("1.1.9"))
+		})
+
+		It("should have a resource count on the status", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+			Expect(configuration.Status.Resources).To(Equal(1))
+		})
+
+		It("should have a in resource status", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+			Expect(configuration.Status.ResourceStatus).To(Equal(terraformv1alphav1.ResourcesInSync))
+		})
+
+		It("should have created a secret containing the module output", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+			secret := &v1.Secret{}
+			secret.Name = configuration.Spec.WriteConnectionSecretToRef.Name
+			secret.Namespace = configuration.Namespace
+
+			found, err := kubernetes.GetIfExists(context.TODO(), cc, secret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(secret.Data).ToNot(BeNil())
+			Expect(secret.Data).To(HaveKey("TEST_OUTPUT"))
+			Expect(secret.Data["TEST_OUTPUT"]).To(Equal([]byte("test")))
+		})
+
+		It("should have recorded a successful revision", func() {
+			revision := terraformv1alphav1.NewConfigurationRevision(cfgNamespace, "bucket-0")
+
+			Expect(cc.Get(context.TODO(), client.ObjectKeyFromObject(revision), revision)).ToNot(HaveOccurred())
+			Expect(revision.Labels[terraformv1alphav1.ConfigurationNameLabel]).To(Equal("bucket"))
+			Expect(revision.Spec.Module).To(Equal(configuration.Spec.Module))
+			Expect(revision.Spec.Configuration.Module).To(Equal(configuration.Spec.Module))
+			Expect(revision.Spec.VariablesHash).ToNot(BeEmpty())
+			Expect(revision.Status.Outcome).To(Equal(terraformv1alphav1.RevisionSucceeded))
+			Expect(revision.Status.StartTime).ToNot(BeNil())
+			Expect(revision.Status.CompletionTime).ToNot(BeNil())
+		})
+	})
+
+	// NOTIFICATIONS
+	When("a policy defines notification sinks", func() {
+		var notifier *fakeNotifier
+		var sinks []terraformv1alphav1.NotificationConstraint
+
+		BeforeEach(func() {
+			notifier = &fakeNotifier{}
+			sinks = []terraformv1alphav1.NotificationConstraint{{
+				Name: "ops",
+				URL:  "https://hooks.example.com/ops",
+			}}
+		})
+
+		JustBeforeEach(func() {
+			policy := fixtures.NewPolicy("notifications")
+			policy.Spec.Constraints = &terraformv1alphav1.Constraints{Notifications: sinks}
+			Expect(cc.Create(context.TODO(), policy)).To(Succeed())
+
+			ctrl.Notifier = notifier
+			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+		})
+
+		When("the configuration is waiting on approval", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				configuration.Annotations = map[string]string{terraformv1alphav1.ApplyAnnotation: "false"}
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+
+				Setup(configuration, plan)
+			})
+
+			It("should have sent a single plan awaiting approval event", func() {
+				Eventually(notifier.Events).Should(HaveLen(1))
+				Consistently(notifier.Events, "200ms").Should(HaveLen(1))
+
+				event := notifier.Events()[0]
+				Expect(event.Type).To(Equal(terraformv1alphav1.NotificationPlanAwaitingApproval))
+				Expect(event.Configuration).To(Equal("bucket"))
+				Expect(event.Namespace).To(Equal(cfgNamespace))
+				Expect(event.Message).To(Equal("Waiting for terraform apply annotation to be set to true"))
+				Expect(event.ID).ToNot(BeEmpty())
+			})
+
+			It("should have sent the event to the sink", func() {
+				Eventually(notifier.Sinks).Should(HaveLen(1))
+
+				sink := notifier.Sinks()[0]
+				Expect(sink.Name).To(Equal("ops"))
+				Expect(sink.URL).To(Equal("https://hooks.example.com/ops"))
+				Expect(sink.Format).To(Equal(terraformv1alphav1.NotificationFormatJSON))
+			})
+
+			When("the sink is not subscribed to the event", func() {
+				BeforeEach(func() {
+					sinks[0].Events = []terraformv1alphav1.NotificationEvent{terraformv1alphav1.NotificationApplyFailed}
+				})
+
+				It("should not send any events", func() {
+					Consistently(notifier.Events, "200ms").Should(BeEmpty())
+				})
+			})
+
+			When("the sink url is held in a secret", func() {
+				BeforeEach(func() {
+					secret := &v1.Secret{}
+					secret.Namespace = ctrl.ControllerNamespace
+					secret.Name = "slack"
+					secret.Data = map[string][]byte{terraformv1alphav1.NotificationURLSecretKey: []byte("https://hooks.slack.com/secret")}
+					Expect(cc.Create(context.TODO(), secret)).To(Succeed())
+
+					sinks[0].URL = ""
+					sinks[0].Format = terraformv1alphav1.NotificationFormatSlack
+					sinks[0].SecretRef = &v1.SecretReference{Name: "slack"}
+				})
+
+				It("should send the event to the url from the secret", func() {
+					Eventually(notifier.Sinks).Should(HaveLen(1))
+
+					sink := notifier.Sinks()[0]
+					Expect(sink.URL).To(Equal("https://hooks.slack.com/secret"))
+					Expect(sink.Format).To(Equal(terraformv1alphav1.NotificationFormatSlack))
+				})
+			})
+
+			When("the sink selector does not match the configuration", func() {
+				BeforeEach(func() {
+					sinks[0].Selector = &terraformv1alphav1.Selector{
+						Namespace: &metav1.LabelSelector{MatchLabels: map[string]string{"does": "not-match"}},
+					}
+				})
+
+				It("should not send any events", func() {
+					Consistently(notifier.Events, "200ms").Should(BeEmpty())
+				})
+			})
+		})
+
+		When("the terraform apply has completed", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+
+				apply := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformApply)
+				apply.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				apply.Status.Succeeded = 1
+
+				state := fixtures.NewTerraformState(configuration)
+				state.Namespace = "default"
+
+				Setup(configuration, plan, apply, state)
+			})
+
+			It("should have sent a single apply succeeded event", func() {
+				Eventually(notifier.Events).Should(HaveLen(1))
+				Consistently(notifier.Events, "200ms").Should(HaveLen(1))
+
+				event := notifier.Events()[0]
+				Expect(event.Type).To(Equal(terraformv1alphav1.NotificationApplySucceeded))
+				Expect(event.Message).To(Equal("Terraform apply is complete"))
+			})
+		})
+
+		When("the terraform apply has failed", func() {
+			BeforeEach(func() {
+				configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+
+				apply := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformApply)
+				apply.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v1.ConditionTrue}}
+				apply.Status.Failed = 1
+
+				Setup(configuration, plan, apply)
+			})
+
+			It("should have sent an apply failed event", func() {
+				Eventually(notifier.Events).Should(HaveLen(1))
+
+				event := notifier.Events()[0]
+				Expect(event.Type).To(Equal(terraformv1alphav1.NotificationApplyFailed))
+				Expect(event.Message).To(HavePrefix("Terraform apply has failed"))
+			})
+		})
+	})
+
+	// REVISIONS
+	When("the configuration has reached the revision history limit", func() {
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Generation = 3
+
+			var objects []runtime.Object
+			for i := int64(1); i < 3; i++ {
+				revision := terraformv1alphav1.NewConfigurationRevision(cfgNamespace, terraformv1alphav1.GetConfigurationRevisionName("bucket", i))
+				revision.Labels = map[string]string{terraformv1alphav1.ConfigurationUIDLabel: string(configuration.GetUID())}
+				revision.Spec.Generation = i
+				objects = append(objects, revision)
+			}
+
+			Setup(append(objects, configuration)...)
+			ctrl.RevisionHistoryLimit = 2
+			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+		})
+
+		It("should have removed the oldest revision", func() {
+			list := &terraformv1alphav1.ConfigurationRevisionList{}
+
+			Expect(cc.List(context.TODO(), list, client.InNamespace(cfgNamespace))).ToNot(HaveOccurred())
+			Expect(list.Items).To(HaveLen(2))
+			for _, x := range list.Items {
+				Expect(x.Name).ToNot(Equal("bucket-1"))
+			}
+		})
+	})
+
+	// SECRET KEY MAPPINGS
+	When("we have secret key mappings on the configuration", func() {
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Spec.WriteConnectionSecretToRef.Keys = []string{
+				"test_output:mysql_host",
+			}
+
+			// create two successful jobs
+			plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+			plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+			plan.Status.Succeeded = 1
+
+			apply := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformApply)
+			apply.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+			apply.Status.Succeeded = 1
+
+			// create a fake terraform state
+			state := fixtures.NewTerraformState(configuration)
+			state.Namespace = "default"
+
+			Setup(configuration, plan, apply, state)
+			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+		})
+
+		It("should have the conditions", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+			Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
+		})
+
+		It("should indicate the terraform apply has run", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+			cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(corev1alphav1.ReasonReady))
+			Expect(cond.Message).To(Equal("Terraform apply is complete"))
+		})
+
+		It("should have a in resource status", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+			Expect(configuration.Status.ResourceStatus).To(Equal(terraformv1alphav1.ResourcesInSync))
+		})
+
+		It("should have created a secret containing the module output", func() {
+			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+			secret := &v1.Secret{}
+			secret.Name = configuration.Spec.WriteConnectionSecretToRef.Name
+			secret.Namespace = configuration.Namespace
+
+			found, err := kubernetes.GetIfExists(context.TODO(), cc, secret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(secret.Data).ToNot(BeNil())
+			Expect(secret.Data).To(HaveKey("MYSQL_HOST"))
+			Expect(secret.Data["MYSQL_HOST"]).To(Equal([]byte("test")))
+		})
+	})
+	// JOB QUOTAS
+	When("the concurrency quotas for terraform jobs have been reached", func() {
+		var other *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Spec.EnableAutoApproval = true
+			other = fixtures.NewValidBucketConfiguration(cfgNamespace, "other")
+		})
+
+		JustBeforeEach(func() {
+			running := fixtures.NewTerraformJob(other, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+			running.Status.Active = 1
+			Setup(configuration, other, running)
+		})
+
+		When("the global limit has been reached", func() {
+			JustBeforeEach(func() {
+				ctrl.MaxConcurrentJobs = 1
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should indicate the configuration is queued", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Queued (position 1), waiting for other terraform jobs to complete"))
+			})
+
+			It("should requeue the configuration", func() {
+				Expect(rerr).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(queueRequeueInterval))
+			})
+
+			It("should not have created the plan job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+		})
+
+		When("the global limit has not been reached", func() {
+			JustBeforeEach(func() {
+				ctrl.MaxConcurrentJobs = 2
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have created the plan job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(2))
+			})
+		})
+
+		When("a policy limits the jobs in the namespace", func() {
+			JustBeforeEach(func() {
+				policy := fixtures.NewPolicy("quotas")
+				policy.Spec.Constraints = &terraformv1alphav1.Constraints{
+					Concurrency: &terraformv1alphav1.ConcurrencyConstraint{MaxJobs: 1},
+				}
+				Expect(cc.Create(context.TODO(), policy)).To(Succeed())
+
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should indicate the configuration is queued", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Queued (position 1), waiting for other terraform jobs to complete"))
+			})
+
+			It("should not have created the plan job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+		})
+	})
+	// DUPLICATE JOBS
+	When("multiple replicas attempt to create the same terraform job", func() {
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			Setup(configuration)
+			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+		})
+
+		It("should have created the plan job with a deterministic name", func() {
+			list := &batchv1.JobList{}
+			Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+			Expect(list.Items).To(HaveLen(1))
+
+			job := list.Items[0]
+			Expect(job.GenerateName).To(BeEmpty())
+			Expect(job.Name).To(Equal(jobs.Name(configuration.Name, terraformv1alphav1.StageTerraformPlan, job.GetLabels())))
+			Expect(job.Name).To(HavePrefix("bucket-plan-"))
+		})
+
+		It("should not create a second job when the job already exists", func() {
+			list := &batchv1.JobList{}
+			Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+			Expect(list.Items).To(HaveLen(1))
+
+			duplicate := list.Items[0].DeepCopy()
+			duplicate.ResourceVersion = ""
+			Expect(ctrl.createJob(context.TODO(), duplicate)).To(Succeed())
+
+			Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+			Expect(list.Items).To(HaveLen(1))
+		})
+	})
+	// RETRIES
+	When("the terraform plan has failed", func() {
+		var failed *batchv1.Job
+		var pods []*v1.Pod
+
+		BeforeEach(func() {
+			pods = nil
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Spec.Retry = &terraformv1alphav1.Retry{
+				MaxAttempts: 3,
+				Backoff:     &metav1.Duration{Duration: time.Hour},
+			}
+			failed = fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+			failed.Status.Conditions = []batchv1.JobCondition{{
+				Type:               batchv1.JobFailed,
+				Status:             v1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(time.Now()),
+			}}
+			failed.Status.Failed = 1
+		})
+
+		JustBeforeEach(func() {
+			Setup(configuration, failed)
+			for _, pod := range pods {
+				_, err := ctrl.kc.CoreV1().Pods(ctrl.ControllerNamespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+				Expect(err).ToNot(HaveOccurred())
+			}
+			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+		})
+
+		When("no retry policy has been defined", func() {
+			BeforeEach(func() {
+				configuration.Spec.Retry = nil
+			})
+
+			It("should indicate the plan has failed", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonError))
+				Expect(cond.Message).To(Equal("Terraform plan is failed"))
+				Expect(configuration.Status.Retry).To(BeNil())
+			})
+
+			It("should not have created another plan job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+		})
+
+		When("the backoff has not passed", func() {
+			It("should indicate the plan will be retried", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonWarning))
+				Expect(cond.Message).To(HavePrefix("Terraform plan failed on attempt 1 of 3, retrying at "))
+			})
+
+			It("should have the attempts on the status", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				Expect(configuration.Status.Retry).ToNot(BeNil())
+				Expect(configuration.Status.Retry.Stage).To(Equal(terraformv1alphav1.StageTerraformPlan))
+				Expect(configuration.Status.Retry.Attempt).To(Equal(1))
+				Expect(configuration.Status.Retry.MaxAttempts).To(Equal(3))
+				Expect(configuration.Status.Retry.NextAttemptTime).ToNot(BeNil())
+			})
+
+			It("should requeue when the backoff has passed", func() {
+				Expect(rerr).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			})
+
+			It("should not have created another plan job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+		})
+
+		When("the backoff has passed", func() {
+			BeforeEach(func() {
+				failed.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+			})
+
+			It("should have created a plan job for the next attempt", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(2))
+
+				var attempts []string
+				for _, job := range list.Items {
+					attempts = append(attempts, job.Labels[terraformv1alphav1.ConfigurationAttemptLabel])
+				}
+				Expect(attempts).To(ContainElement("2"))
+			})
+
+			It("should have the attempt on the status", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				Expect(configuration.Status.Retry).ToNot(BeNil())
+				Expect(configuration.Status.Retry.Attempt).To(Equal(2))
+				Expect(configuration.Status.Retry.NextAttemptTime).To(BeNil())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+			})
+		})
+
+		When("the attempts have been exhausted", func() {
+			BeforeEach(func() {
+				failed.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+				failed.Labels[terraformv1alphav1.ConfigurationAttemptLabel] = "3"
+			})
+
+			It("should indicate the plan has failed", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonError))
+				Expect(cond.Message).To(Equal("Terraform plan is failed"))
+				Expect(configuration.Status.Retry).ToNot(BeNil())
+				Expect(configuration.Status.Retry.Attempt).To(Equal(3))
+			})
+
+			It("should not have created another plan job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+		})
+
+		When("the failure is not retryable", func() {
+			var detectors []terraform.ErrorDetection
+
+			BeforeEach(func() {
+				failed.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+
+				detectors = terraform.Detectors["*"]
+				terraform.Detectors["*"] = append([]terraform.ErrorDetection{}, detectors...)
+				terraform.Detectors["*"] = append(terraform.Detectors["*"], terraform.ErrorDetection{
+					Regex:   "fake logs",
+					Message: "Provider credentials are invalid",
+				})
+
+				pod := &v1.Pod{}
+				pod.Name = failed.Name
+				pod.Namespace = ctrl.ControllerNamespace
+				pod.Labels = map[string]string{"job-name": failed.Name}
+				pod.Status.Phase = v1.PodFailed
+				pods = append(pods, pod)
+			})
+
+			AfterEach(func() {
+				terraform.Detectors["*"] = detectors
+			})
+
+			It("should indicate the cause of the failure", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+				Expect(cond.Message).To(Equal("Provider credentials are invalid"))
+			})
+
+			It("should not have created another plan job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+		})
+	})
+	// MANUAL RETRIES
+	When("a retry has been requested for the configuration", func() {
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Spec.EnableAutoApproval = true
+		})
+
+		When("the terraform plan has failed", func() {
+			JustBeforeEach(func() {
+				failed := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				failed.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v1.ConditionTrue}}
+				failed.Status.Failed = 1
+
+				configuration.Status.Conditions = nil
+				Setup(configuration, failed)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have created a new plan job", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonError))
+
+				configuration.Annotations = map[string]string{terraformv1alphav1.RetryAnnotation: "1660000000"}
+				Expect(cc.Update(context.TODO(), configuration)).To(Succeed())
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(2))
+
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				cond = configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+			})
+
+			It("should not create a new plan job without a retry", func() {
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+		})
+
+		When("the configuration has been applied", func() {
+			BeforeEach(func() {
+				configuration.Annotations = map[string]string{terraformv1alphav1.RetryAnnotation: "1660000000"}
+				configuration.Status.UpstreamChecksum = "previous"
+				configuration.Status.Conditions = []corev1alphav1.Condition{
+					{
+						Type:               terraformv1alphav1.ConditionTerraformPlan,
+						Status:             metav1.ConditionTrue,
+						Reason:             corev1alphav1.ReasonReady,
+						ObservedGeneration: configuration.GetGeneration(),
+					},
+					{
+						Type:               terraformv1alphav1.ConditionTerraformApply,
+						Status:             metav1.ConditionTrue,
+						Reason:             corev1alphav1.ReasonReady,
+						ObservedGeneration: configuration.GetGeneration(),
+					},
+				}
+				Setup(configuration)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have created a new plan job for the generation", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+				Expect(list.Items[0].Labels[terraformv1alphav1.ConfigurationStageLabel]).To(Equal(terraformv1alphav1.StageTerraformPlan))
+				Expect(list.Items[0].Labels[terraformv1alphav1.ConfigurationUpstreamLabel]).ToNot(BeEmpty())
+			})
+		})
+	})
+
+	// WORKSPACES
+	When("the configuration targets a terraform workspace", func() {
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Spec.Workspace = "blue"
+			configuration.Spec.ImportState = &v1.SecretReference{Name: "state"}
+			imported := fixtures.NewTerraformState(configuration)
+			imported.Namespace = cfgNamespace
+			imported.Name = "state"
+			imported.Data = map[string][]byte{
+				terraformv1alphav1.ImportStateSecretKey: imported.Data[terraformv1alphav1.TerraformStateSecretKey],
+			}
+			Setup(configuration, imported)
+			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+		})
+
+		It("should have created the terraform state within the workspace", func() {
+			Expect(configuration.GetTerraformStateSecretName()).To(Equal("tfstate-blue-" + string(configuration.GetUID())))
+
+			secret := &v1.Secret{}
+			secret.Namespace = ctrl.ControllerNamespace
+			secret.Name = configuration.GetTerraformStateSecretName()
+
+			found, err := kubernetes.GetIfExists(context.TODO(), cc, secret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(secret.GetLabels()).To(HaveKeyWithValue("tfstateWorkspace", "blue"))
+		})
+
+		It("should select the workspace in the plan", func() {
+			list := &batchv1.JobList{}
+			Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+			Expect(list.Items).To(HaveLen(1))
+
+			var args []string
+			for _, x := range list.Items[0].Spec.Template.Spec.Containers {
+				if x.Name == jobs.TerraformContainerName {
+					args = x.Args
+				}
+			}
+			Expect(args).To(ContainElement("--command=/bin/terraform workspace select blue || /bin/terraform workspace new blue"))
+		})
+	})
+
+	// OPERATIONS
+	When("the configuration has replace and target operations", func() {
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Spec.EnableAutoApproval = true
+			configuration.Spec.Operations = &terraformv1alphav1.Operations{
+				Replace: []string{"aws_s3_bucket.this"},
+				Target:  []string{"module.db"},
+			}
+		})
+
+		When("the plan has not been run", func() {
+			BeforeEach(func() {
+				Setup(configuration)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should have passed the operations to the plan", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+
+				var args []string
+				for _, x := range list.Items[0].Spec.Template.Spec.Containers {
+					if x.Name == jobs.TerraformContainerName {
+						args = x.Args
+					}
+				}
+				Expect(args).To(ContainElement(ContainSubstring("-replace='aws_s3_bucket.this' -target='module.db' -out=")))
+			})
+
+			It("should have recorded the operations in the status", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.Operations).To(Equal(configuration.Spec.Operations))
+			})
+
+			It("should require an approval regardless of auto approval", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.NeedsApproval()).To(BeTrue())
+			})
+		})
+
+		When("the plan has completed", func() {
+			BeforeEach(func() {
+				configuration.Annotations = map[string]string{terraformv1alphav1.ApplyAnnotation: "false"}
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, plan, saved)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
+
+			It("should indicate the apply is waiting on an approval", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+				Expect(cond.Message).To(Equal("Terraform plan includes operations (replace: aws_s3_bucket.this; target: module.db), waiting for terraform apply annotation to be set to true"))
+			})
+
+			It("should not have created an apply job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+		})
 
-			apply := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformApply)
-			apply.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
-			apply.Status.Succeeded = 1
+		When("the apply has completed", func() {
+			BeforeEach(func() {
+				configuration.Annotations = map[string]string{terraformv1alphav1.ApplyAnnotation: "true"}
+				configuration.Status.Operations = configuration.Spec.Operations.DeepCopy()
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				apply := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformApply)
+				apply.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				apply.Status.Succeeded = 1
+				state := fixtures.NewTerraformState(configuration)
+				state.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, plan, apply, state)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
 
-			// create a fake terraform state
-			state := fixtures.NewTerraformState(configuration)
-			state.Namespace = "default"
+			It("should indicate the terraform apply has run", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
 
-			Setup(configuration, plan, apply, state)
-			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(cond.Message).To(Equal("Terraform apply is complete"))
+			})
+
+			It("should have cleared the operations", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Spec.Operations).To(BeNil())
+				Expect(configuration.Status.Operations).To(BeNil())
+			})
 		})
+	})
 
-		It("should have the conditions", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
-			Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
+	// PLAN ONLY
+	When("the configuration is plan only", func() {
+		BeforeEach(func() {
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Spec.PlanOnly = true
 		})
 
-		It("should indicate the terraform apply has run", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+		When("the plan has not been run", func() {
+			BeforeEach(func() {
+				Setup(configuration)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
 
-			cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
-			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
-			Expect(cond.Reason).To(Equal(corev1alphav1.ReasonReady))
-			Expect(cond.Message).To(Equal("Terraform apply is complete"))
+			It("should not require an approval", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.GetAnnotations()).ToNot(HaveKey(terraformv1alphav1.ApplyAnnotation))
+			})
+
+			It("should have created the terraform plan", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+				Expect(list.Items[0].Labels).To(HaveKeyWithValue(terraformv1alphav1.ConfigurationStageLabel, terraformv1alphav1.StageTerraformPlan))
+			})
 		})
 
-		It("should a last reconciliation time on the status", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+		When("the plan has completed and the apply has been approved", func() {
+			BeforeEach(func() {
+				configuration.Annotations = map[string]string{terraformv1alphav1.ApplyAnnotation: "true"}
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				plan.Status.Succeeded = 1
+				saved := fixtures.NewTerraformPlan(configuration)
+				saved.Namespace = ctrl.ControllerNamespace
+				Setup(configuration, plan, saved)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 5)
+			})
 
-			Expect(configuration.Status.LastReconcile).ToNot(BeNil())
-			Expect(configuration.Status.LastReconcile.Time).ToNot(BeNil())
-			Expect(configuration.Status.LastReconcile.Generation).To(Equal(int64(0)))
-		})
+			It("should not have created an apply job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
 
-		It("should a last success reconciliation time on the status", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+			It("should indicate the terraform apply is disabled", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
 
-			Expect(configuration.Status.LastSuccess).ToNot(BeNil())
-			Expect(configuration.Status.LastSuccess.Time).ToNot(BeNil())
-			Expect(configuration.Status.LastSuccess.Generation).To(Equal(int64(0)))
-		})
+				cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonWarning))
+				Expect(cond.Message).To(Equal("Configuration is plan only, terraform apply is disabled"))
+			})
 
-		It("should have a version on status", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
-			Expect(configuration.Status.TerraformVersion).To(Equal("1.1.9"))
+			It("should report the plan has changes", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Status.Plan).ToNot(BeNil())
+				Expect(configuration.Status.Plan.HasChanges).To(BeTrue())
+				Expect(configuration.Status.ResourceStatus).To(Equal(terraformv1alphav1.ResourcesOutOfSync))
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(cond.Message).To(Equal("Plan only, terraform plan has changes (1 to add, 1 to destroy)"))
+			})
 		})
+	})
 
-		It("should have a resource count on the status", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
-			Expect(configuration.Status.Resources).To(Equal(1))
+	// DELETION POLICY
+	When("the configuration is deleted with a deletion policy", func() {
+		var state *v1.Secret
+
+		BeforeEach(func() {
+			now := metav1.Now()
+			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
+			configuration.Finalizers = []string{controllerName}
+			configuration.DeletionTimestamp = &now
+			controller.EnsureConditionsRegistered(terraformv1alphav1.DefaultConfigurationConditions, configuration)
+			state = fixtures.NewTerraformState(configuration)
+			state.Namespace = ctrl.ControllerNamespace
 		})
 
-		It("should have a in resource status", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+		When("the deletion policy is orphan", func() {
+			BeforeEach(func() {
+				configuration.Spec.DeletionPolicy = terraformv1alphav1.DeletionPolicyOrphan
+				Setup(configuration, state)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 1)
+			})
 
-			Expect(configuration.Status.ResourceStatus).To(Equal(terraformv1alphav1.ResourcesInSync))
+			It("should not have created any jobs", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list)).ToNot(HaveOccurred())
+				Expect(list.Items).To(BeEmpty())
+			})
+
+			It("should have removed the configuration", func() {
+				err := cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
 		})
 
-		It("should have created a secret containing the module output", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+		When("the deletion policy requires an approval", func() {
+			BeforeEach(func() {
+				configuration.Spec.DeletionPolicy = terraformv1alphav1.DeletionPolicyRequireApproval
+			})
 
-			secret := &v1.Secret{}
-			secret.Name = configuration.Spec.WriteConnectionSecretToRef.Name
-			secret.Namespace = configuration.Namespace
+			When("the destroy plan has not been run", func() {
+				BeforeEach(func() {
+					Setup(configuration, state)
+					result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 1)
+				})
 
-			found, err := kubernetes.GetIfExists(context.TODO(), cc, secret)
-			Expect(err).ToNot(HaveOccurred())
-			Expect(found).To(BeTrue())
-			Expect(secret.Data).ToNot(BeNil())
-			Expect(secret.Data).To(HaveKey("TEST_OUTPUT"))
-			Expect(secret.Data["TEST_OUTPUT"]).To(Equal([]byte("test")))
+				It("should have created the destroy plan job", func() {
+					list := &batchv1.JobList{}
+					Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+					Expect(list.Items).To(HaveLen(1))
+					Expect(list.Items[0].Labels).To(HaveKeyWithValue(terraformv1alphav1.ConfigurationStageLabel, terraformv1alphav1.StageTerraformDestroyPlan))
+
+					var args []string
+					for _, x := range list.Items[0].Spec.Template.Spec.Containers {
+						if x.Name == jobs.TerraformContainerName {
+							args = x.Args
+						}
+					}
+					Expect(args).To(ContainElement(ContainSubstring("--command=/bin/terraform plan -destroy ")))
+				})
+
+				It("should indicate the destroy plan is running", func() {
+					Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+					cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+					Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+					Expect(cond.Message).To(Equal("Terraform destroy plan is running"))
+				})
+			})
+
+			When("the destroy plan has completed", func() {
+				var destroyPlan *batchv1.Job
+
+				BeforeEach(func() {
+					destroyPlan = fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformDestroyPlan)
+					destroyPlan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+					destroyPlan.Status.Succeeded = 1
+				})
+
+				When("the destroy has not been approved", func() {
+					BeforeEach(func() {
+						saved := fixtures.NewTerraformPlan(configuration)
+						saved.Namespace = ctrl.ControllerNamespace
+						saved.Labels[terraformv1alphav1.ConfigurationStageLabel] = terraformv1alphav1.StageTerraformDestroyPlan
+						Setup(configuration, destroyPlan, saved, state)
+						result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 1)
+					})
+
+					It("should indicate the destroy is waiting on an approval", func() {
+						Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+						cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+						Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+						Expect(cond.Message).To(Equal("Terraform destroy plan (1 to add, 1 to destroy), waiting for terraform.appvia.io/destroy-approved annotation to be set to true"))
+					})
+
+					It("should have published the destroy plan summary", func() {
+						Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+						Expect(configuration.Status.Plan).ToNot(BeNil())
+						Expect(configuration.Status.Plan.Summary).To(Equal("1 to add, 1 to destroy"))
+					})
+
+					It("should not have created the destroy job", func() {
+						list := &batchv1.JobList{}
+						Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+						Expect(list.Items).To(HaveLen(1))
+					})
+				})
+
+				When("the destroy has been approved", func() {
+					BeforeEach(func() {
+						configuration.Annotations = map[string]string{terraformv1alphav1.DestroyApprovedAnnotation: "true"}
+						Setup(configuration, destroyPlan, state)
+						result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 1)
+					})
+
+					It("should have created the destroy job", func() {
+						list := &batchv1.JobList{}
+						Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+						Expect(list.Items).To(HaveLen(2))
+
+						var stages []string
+						for _, x := range list.Items {
+							stages = append(stages, x.Labels[terraformv1alphav1.ConfigurationStageLabel])
+						}
+						Expect(stages).To(ContainElement(terraformv1alphav1.StageTerraformDestroy))
+					})
+				})
+			})
 		})
 	})
 
-	// SECRET KEY MAPPINGS
-	When("we have secret key mappings on the configuration", func() {
+	// STATE LOCKS
+	When("the configuration has requested the state lock is released", func() {
+		lockID := "9db590f1-b6fe-c5f2-2678-8804f089deba"
+
 		BeforeEach(func() {
 			configuration = fixtures.NewValidBucketConfiguration(cfgNamespace, "bucket")
-			configuration.Spec.WriteConnectionSecretToRef.Keys = []string{
-				"test_output:mysql_host",
+			configuration.Annotations = map[string]string{
+				terraformv1alphav1.UnlockAnnotation:            lockID,
+				terraformv1alphav1.UnlockRequestedByAnnotation: "jane",
 			}
+			configuration.Status.StateLock = &terraformv1alphav1.StateLockStatus{ID: lockID, Who: "jest@laptop"}
+		})
 
-			// create two successful jobs
-			plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
-			plan.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
-			plan.Status.Succeeded = 1
-
-			apply := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformApply)
-			apply.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
-			apply.Status.Succeeded = 1
+		When("the lock id is invalid", func() {
+			BeforeEach(func() {
+				configuration.Annotations[terraformv1alphav1.UnlockAnnotation] = "-force"
+				Setup(configuration)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
 
-			// create a fake terraform state
-			state := fixtures.NewTerraformState(configuration)
-			state.Namespace = "default"
+			It("should indicate the lock id is invalid", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
 
-			Setup(configuration, plan, apply, state)
-			result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonActionRequired))
+				Expect(cond.Message).To(Equal(`Terraform state lock id "-force" in the terraform.appvia.io/unlock annotation is invalid`))
+			})
 		})
 
-		It("should have the conditions", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
-			Expect(configuration.Status.Conditions).To(HaveLen(defaultConditions))
+		When("a terraform job is running", func() {
+			BeforeEach(func() {
+				plan := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformPlan)
+				plan.Status.Active = 1
+				Setup(configuration, plan)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
+
+			It("should wait for the job to complete", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Message).To(Equal("Waiting for the terraform jobs to complete before releasing the state lock " + lockID))
+			})
+
+			It("should not have created an unlock job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
 		})
 
-		It("should indicate the terraform apply has run", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+		When("the lock has not been released", func() {
+			BeforeEach(func() {
+				Setup(configuration)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 3)
+			})
 
-			cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
-			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
-			Expect(cond.Reason).To(Equal(corev1alphav1.ReasonReady))
-			Expect(cond.Message).To(Equal("Terraform apply is complete"))
+			It("should indicate the lock is being released", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+				Expect(cond.Message).To(Equal("Releasing the terraform state lock " + lockID))
+			})
+
+			It("should have created the unlock job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+				Expect(list.Items[0].Labels).To(HaveKeyWithValue(terraformv1alphav1.ConfigurationStageLabel, terraformv1alphav1.StageTerraformUnlock))
+				Expect(list.Items[0].Labels).To(HaveKeyWithValue(terraformv1alphav1.ConfigurationLockLabel, lockID))
+
+				var args []string
+				for _, x := range list.Items[0].Spec.Template.Spec.Containers {
+					if x.Name == jobs.TerraformContainerName {
+						args = x.Args
+					}
+				}
+				Expect(args).To(ContainElement("--command=/bin/terraform force-unlock -force " + lockID))
+			})
 		})
 
-		It("should have a in resource status", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+		When("the unlock job has failed", func() {
+			var unlock *batchv1.Job
 
-			Expect(configuration.Status.ResourceStatus).To(Equal(terraformv1alphav1.ResourcesInSync))
+			BeforeEach(func() {
+				unlock = fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformUnlock)
+				unlock.Labels[terraformv1alphav1.ConfigurationLockLabel] = lockID
+				unlock.Labels[terraformv1alphav1.ConfigurationAttemptLabel] = "1"
+				unlock.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v1.ConditionTrue}}
+				unlock.Status.Failed = 1
+				Setup(configuration, unlock)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 2)
+			})
+
+			It("should indicate the lock could not be released", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+				cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+				Expect(cond.Reason).To(Equal(corev1alphav1.ReasonError))
+				Expect(cond.Message).To(Equal("Failed to release the terraform state lock " + lockID +
+					", check the logs of the unlock job before requesting the unlock again"))
+			})
+
+			It("should have removed the unlock request and recorded the failed attempt", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Annotations).ToNot(HaveKey(terraformv1alphav1.UnlockAnnotation))
+				Expect(configuration.Annotations).ToNot(HaveKey(terraformv1alphav1.RetryAnnotation))
+				Expect(configuration.Status.StateLock).ToNot(BeNil())
+				Expect(configuration.Status.StateLock.FailedUnlocks).To(Equal(1))
+			})
+
+			It("should have kept the failed unlock job", func() {
+				list := &batchv1.JobList{}
+				Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+				Expect(list.Items).To(HaveLen(1))
+			})
+
+			When("the unlock is requested again", func() {
+				BeforeEach(func() {
+					Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+					configuration.Annotations = map[string]string{terraformv1alphav1.UnlockAnnotation: lockID}
+					Expect(cc.Update(context.TODO(), configuration)).To(Succeed())
+
+					result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 1)
+				})
+
+				It("should have created a new unlock job", func() {
+					list := &batchv1.JobList{}
+					Expect(cc.List(context.TODO(), list, client.InNamespace(ctrl.ControllerNamespace))).ToNot(HaveOccurred())
+					Expect(list.Items).To(HaveLen(2))
+
+					var created *batchv1.Job
+					for i := 0; i < len(list.Items); i++ {
+						if list.Items[i].Name != unlock.Name {
+							created = &list.Items[i]
+						}
+					}
+					Expect(created).ToNot(BeNil())
+					Expect(created.Labels).To(HaveKeyWithValue(terraformv1alphav1.ConfigurationAttemptLabel, "2"))
+					Expect(created.Labels).To(HaveKeyWithValue(terraformv1alphav1.ConfigurationLockLabel, lockID))
+				})
+
+				It("should indicate the lock is being released", func() {
+					Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+
+					cond := configuration.Status.GetCondition(corev1alphav1.ConditionReady)
+					Expect(cond.Reason).To(Equal(corev1alphav1.ReasonInProgress))
+					Expect(cond.Message).To(Equal("Releasing the terraform state lock " + lockID))
+				})
+			})
 		})
 
-		It("should have created a secret containing the module output", func() {
-			Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+		When("the lock has been released", func() {
+			BeforeEach(func() {
+				unlock := fixtures.NewTerraformJob(configuration, ctrl.ControllerNamespace, terraformv1alphav1.StageTerraformUnlock)
+				unlock.Labels[terraformv1alphav1.ConfigurationLockLabel] = lockID
+				unlock.Labels[terraformv1alphav1.ConfigurationAttemptLabel] = "1"
+				unlock.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+				unlock.Status.Succeeded = 1
+				Setup(configuration, unlock)
+				result, _, rerr = controllertests.Roll(context.TODO(), ctrl, configuration, 2)
+			})
 
-			secret := &v1.Secret{}
-			secret.Name = configuration.Spec.WriteConnectionSecretToRef.Name
-			secret.Namespace = configuration.Namespace
+			It("should have removed the unlock request", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Annotations).ToNot(HaveKey(terraformv1alphav1.UnlockAnnotation))
+				Expect(configuration.Annotations).ToNot(HaveKey(terraformv1alphav1.UnlockRequestedByAnnotation))
+				Expect(configuration.Status.StateLock).To(BeNil())
+			})
 
-			found, err := kubernetes.GetIfExists(context.TODO(), cc, secret)
-			Expect(err).ToNot(HaveOccurred())
-			Expect(found).To(BeTrue())
-			Expect(secret.Data).ToNot(BeNil())
-			Expect(secret.Data).To(HaveKey("MYSQL_HOST"))
-			Expect(secret.Data["MYSQL_HOST"]).To(Equal([]byte("test")))
+			It("should have requested the plan is run again", func() {
+				Expect(cc.Get(context.TODO(), configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
+				Expect(configuration.Annotations).To(HaveKey(terraformv1alphav1.RetryAnnotation))
+			})
+
+			It("should have recorded an event", func() {
+				Expect(recorder.Events).To(ContainElement(ContainSubstring(
+					"StateUnlocked: Terraform state lock " + lockID + " has been force released, requested by jane")))
+			})
 		})
 	})
 })
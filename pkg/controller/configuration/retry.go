@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package configuration
+
+import (
+	"context"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/controller"
+	"github.com/appvia/terraform-controller/pkg/utils/jobs"
+)
+
+const (
+	// defaultRetryBackoff is the delay before the first retry when none has been defined
+	defaultRetryBackoff = 30 * time.Second
+	// maxRetryBackoff is the maximum delay between attempts
+	maxRetryBackoff = time.Hour
+)
+
+// ensureTerraformRetry is called when a job for the stage has failed with a retryable error. It returns an empty
+// result when the stage should be attempted again now, otherwise requeues until the backoff has passed, or
+// ignores the configuration once the attempts have been exhausted
+func (c *Controller) ensureTerraformRetry(configuration *terraformv1alphav1.Configuration, job *batchv1.Job, stage string) controller.EnsureFunc {
+	condition := terraformv1alphav1.ConditionTerraformPlan
+	if stage == terraformv1alphav1.StageTerraformApply {
+		condition = terraformv1alphav1.ConditionTerraformApply
+	}
+	cond := controller.ConditionMgr(configuration, condition, c.recorder)
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		retry := c.findRetry(configuration)
+		attempt := jobs.Attempt(job)
+
+		if attempt >= retry.MaxAttempts {
+			if retry.MaxAttempts > 1 {
+				configuration.Status.Retry = &terraformv1alphav1.RetryStatus{
+					Attempt:     attempt,
+					MaxAttempts: retry.MaxAttempts,
+					Stage:       stage,
+				}
+			}
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+
+		// @step: the backoff doubles on each attempt
+		next := jobs.FailedTime(job).Add(retryBackoff(retry, attempt))
+
+		if remaining := time.Until(next); remaining > 0 {
+			configuration.Status.Retry = &terraformv1alphav1.RetryStatus{
+				Attempt:         attempt,
+				MaxAttempts:     retry.MaxAttempts,
+				NextAttemptTime: &metav1.Time{Time: next},
+				Stage:           stage,
+			}
+			cond.Warning("Terraform %s failed on attempt %d of %d, retrying at %s",
+				stage, attempt, retry.MaxAttempts, next.UTC().Format(time.RFC3339))
+
+			return reconcile.Result{RequeueAfter: remaining}, nil
+		}
+		cond.InProgress("Retrying the terraform %s, attempt %d of %d", stage, attempt+1, retry.MaxAttempts)
+
+		return reconcile.Result{}, nil
+	}
+}
+
+// findRetry returns the retry policy for the configuration, any values not defined by the configuration
+// are taken from the controller default
+func (c *Controller) findRetry(configuration *terraformv1alphav1.Configuration) *terraformv1alphav1.Retry {
+	retry := &terraformv1alphav1.Retry{MaxAttempts: 1, Backoff: &metav1.Duration{Duration: defaultRetryBackoff}}
+
+	for _, x := range []*terraformv1alphav1.Retry{c.DefaultRetry, configuration.Spec.Retry} {
+		if x == nil {
+			continue
+		}
+		if x.MaxAttempts > 0 {
+			retry.MaxAttempts = x.MaxAttempts
+		}
+		if x.Backoff != nil && x.Backoff.Duration > 0 {
+			retry.Backoff = x.Backoff
+		}
+	}
+
+	return retry
+}
+
+// newRetryStatus returns the status of the attempts at the stage, or nil when retries are not enabled
+// and this is the first attempt
+func (c *Controller) newRetryStatus(configuration *terraformv1alphav1.Configuration, stage string, attempt int) *terraformv1alphav1.RetryStatus {
+	retry := c.findRetry(configuration)
+	if retry.MaxAttempts <= 1 {
+		return nil
+	}
+
+	return &terraformv1alphav1.RetryStatus{
+		Attempt:     attempt,
+		MaxAttempts: retry.MaxAttempts,
+		Stage:       stage,
+	}
+}
+
+// retryBackoff returns the delay before the attempt following the given attempt
+func retryBackoff(retry *terraformv1alphav1.Retry, attempt int) time.Duration {
+	backoff := retry.Backoff.Duration
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+
+	return backoff
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alphav1 "github.com/appvia/terraform-controller/pkg/apis/core/v1alpha1"
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/utils/kubernetes"
+)
+
+// DefaultRevisionHistoryLimit is the number of revisions retained when no limit is configured
+const DefaultRevisionHistoryLimit = 10
+
+// recordRevision is responsible for creating or updating the revision for the current generation of
+// the configuration, recording the outcome of the plan and apply
+func (c *Controller) recordRevision(ctx context.Context, configuration *terraformv1alphav1.Configuration) error {
+	revision := terraformv1alphav1.NewConfigurationRevision(configuration.Namespace,
+		terraformv1alphav1.GetConfigurationRevisionName(configuration.Name, configuration.GetGeneration()),
+	)
+
+	found, err := kubernetes.GetIfExists(ctx, c.cc, revision)
+	if err != nil {
+		return err
+	}
+	// @note: once applied the revision is a historic record, drift checks do not change it
+	if found && revision.Status.Outcome == terraformv1alphav1.RevisionSucceeded {
+		return nil
+	}
+	original := revision.DeepCopy()
+
+	if !found {
+		revision.Labels = map[string]string{
+			terraformv1alphav1.ConfigurationGenerationLabel: fmt.Sprintf("%d", configuration.GetGeneration()),
+			terraformv1alphav1.ConfigurationNameLabel:       configuration.Name,
+			terraformv1alphav1.ConfigurationUIDLabel:        string(configuration.GetUID()),
+		}
+		revision.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(configuration, terraformv1alphav1.ConfigurationGVK),
+		}
+		revision.Spec = terraformv1alphav1.ConfigurationRevisionSpec{
+			Configuration: *configuration.Spec.DeepCopy(),
+			Generation:    configuration.GetGeneration(),
+			Module:        configuration.Spec.Module,
+			VariablesHash: variablesHash(configuration),
+		}
+		revision.Status.StartTime = &metav1.Time{Time: time.Now()}
+	}
+
+	// @step: update the status of the revision from the configuration
+	generation := configuration.GetGeneration()
+
+	revision.Status.Outcome = getRevisionOutcome(configuration)
+	if revision.IsCompleted() && revision.Status.CompletionTime == nil {
+		revision.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	}
+	if configuration.Status.Plan != nil && configuration.Status.Plan.Generation == generation {
+		revision.Status.Plan = configuration.Status.Plan.DeepCopy()
+	}
+	if configuration.Status.Module != nil {
+		revision.Status.Module = &terraformv1alphav1.ModuleStatus{
+			Revision: configuration.Status.Module.Revision,
+			Source:   configuration.Status.Module.Source,
+		}
+	}
+	if configuration.Status.Costs != nil && configuration.Status.Costs.Enabled {
+		revision.Status.Costs = configuration.Status.Costs.DeepCopy()
+	}
+	if cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPolicy); cond != nil && cond.ObservedGeneration == generation {
+		switch {
+		case cond.Status == metav1.ConditionTrue:
+			revision.Status.Policy = "Passed"
+		case cond.Reason == corev1alphav1.ReasonActionRequired:
+			revision.Status.Policy = "Failed"
+		}
+	}
+	switch {
+	case configuration.Spec.EnableAutoApproval:
+		revision.Status.ApprovedBy = "auto"
+	case configuration.HasApproval():
+		revision.Status.ApprovedBy = configuration.GetAnnotations()[terraformv1alphav1.ApprovedByAnnotation]
+	}
+
+	if !found {
+		if err := c.cc.Create(ctx, revision); err != nil {
+			return err
+		}
+
+		return c.pruneRevisions(ctx, configuration)
+	}
+
+	if equality.Semantic.DeepEqual(original.Status, revision.Status) {
+		return nil
+	}
+
+	return c.cc.Patch(ctx, revision, client.MergeFrom(original))
+}
+
+// pruneRevisions is responsible for removing the oldest revisions of the configuration beyond the
+// revision history limit
+func (c *Controller) pruneRevisions(ctx context.Context, configuration *terraformv1alphav1.Configuration) error {
+	limit := c.RevisionHistoryLimit
+	if limit <= 0 {
+		limit = DefaultRevisionHistoryLimit
+	}
+
+	list := &terraformv1alphav1.ConfigurationRevisionList{}
+	if err := c.cc.List(ctx, list,
+		client.InNamespace(configuration.Namespace),
+		client.MatchingLabels{terraformv1alphav1.ConfigurationUIDLabel: string(configuration.GetUID())},
+	); err != nil {
+		return err
+	}
+	if len(list.Items) <= limit {
+		return nil
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Spec.Generation < list.Items[j].Spec.Generation
+	})
+
+	for i := 0; i < len(list.Items)-limit; i++ {
+		if err := kubernetes.DeleteIfExists(ctx, c.cc, &list.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getRevisionOutcome returns the outcome of the current generation from the conditions
+func getRevisionOutcome(configuration *terraformv1alphav1.Configuration) terraformv1alphav1.RevisionOutcome {
+	generation := configuration.GetGeneration()
+
+	if configuration.Status.IsComplete(corev1alphav1.ConditionReady, generation) {
+		return terraformv1alphav1.RevisionSucceeded
+	}
+
+	outcome := terraformv1alphav1.RevisionInProgress
+	for _, x := range configuration.Status.Conditions {
+		switch {
+		case x.ObservedGeneration != generation:
+			continue
+		case x.Reason == corev1alphav1.ReasonError:
+			return terraformv1alphav1.RevisionFailed
+		case x.Reason == corev1alphav1.ReasonActionRequired:
+			outcome = terraformv1alphav1.RevisionActionRequired
+		}
+	}
+
+	return outcome
+}
+
+// variablesHash returns a hash of the variables of the configuration, or an empty string when the
+// configuration has no variables
+func variablesHash(configuration *terraformv1alphav1.Configuration) string {
+	if !configuration.HasVariables() {
+		return ""
+	}
+	hash := sha256.Sum256(configuration.Spec.Variables.Raw)
+
+	return hex.EncodeToString(hash[:])[:16]
+}
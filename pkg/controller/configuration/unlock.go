@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alphav1 "github.com/appvia/terraform-controller/pkg/apis/core/v1alpha1"
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/controller"
+	"github.com/appvia/terraform-controller/pkg/utils/filters"
+	"github.com/appvia/terraform-controller/pkg/utils/jobs"
+	"github.com/appvia/terraform-controller/pkg/utils/terraform"
+)
+
+// ensureTerraformUnlock is responsible for force releasing the terraform state lock requested via the unlock
+// annotation. Once released an event is recorded on the configuration, and the failed stage is run again
+func (c *Controller) ensureTerraformUnlock(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
+	cond := controller.ConditionMgr(configuration, corev1alphav1.ConditionReady, c.recorder)
+
+	return func(ctx context.Context) (reconcile.Result, error) {
+		id := configuration.GetAnnotations()[terraformv1alphav1.UnlockAnnotation]
+		if id == "" {
+			return reconcile.Result{}, nil
+		}
+		if !terraform.IsValidLockID(id) {
+			cond.ActionRequired("Terraform state lock id %q in the %s annotation is invalid", id, terraformv1alphav1.UnlockAnnotation)
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+
+		list, _ := filters.Jobs(state.jobs).
+			WithName(configuration.GetName()).
+			WithNamespace(configuration.GetNamespace()).
+			WithUID(string(configuration.GetUID())).
+			List()
+
+		// @step: we never release the lock from under a running terraform job
+		for i := 0; i < len(list.Items); i++ {
+			if list.Items[i].GetLabels()[terraformv1alphav1.ConfigurationStageLabel] == terraformv1alphav1.StageTerraformUnlock {
+				continue
+			}
+			if jobs.IsActive(&list.Items[i]) {
+				cond.InProgress("Waiting for the terraform jobs to complete before releasing the state lock %s", id)
+
+				return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+			}
+		}
+
+		// @step: each request to release the lock is run as a new attempt, so a failed unlock can be requested again
+		if configuration.Status.StateLock == nil || configuration.Status.StateLock.ID != id {
+			configuration.Status.StateLock = &terraformv1alphav1.StateLockStatus{ID: id}
+		}
+		attempt := configuration.Status.StateLock.FailedUnlocks + 1
+
+		job, found := filters.Jobs(state.jobs).
+			WithLabel(terraformv1alphav1.ConfigurationAttemptLabel, strconv.Itoa(attempt)).
+			WithLabel(terraformv1alphav1.ConfigurationLockLabel, id).
+			WithName(configuration.GetName()).
+			WithNamespace(configuration.GetNamespace()).
+			WithStage(terraformv1alphav1.StageTerraformUnlock).
+			WithUID(string(configuration.GetUID())).
+			Latest()
+
+		if !found {
+			// @step: the unlock uses the module revision resolved by the controller, if any
+			module := state.module
+			if module == "" && configuration.Status.Module != nil {
+				module = configuration.Status.Module.Source
+			}
+
+			runner, err := jobs.New(configuration, state.provider).NewTerraformUnlock(jobs.Options{
+				AdditionalLabels: map[string]string{
+					terraformv1alphav1.ConfigurationAttemptLabel: strconv.Itoa(attempt),
+					terraformv1alphav1.ConfigurationLockLabel:    id,
+				},
+				BackendSecret:   state.backendSecret,
+				ExecutorImage:   c.ExecutorImage,
+				ExecutorSecrets: c.ExecutorSecrets,
+				LockID:          id,
+				Module:          module,
+				Namespace:       c.ControllerNamespace,
+				Template:        state.jobTemplate,
+				TerraformImage:  GetTerraformImage(configuration, c.TerraformImage),
+			})
+			if err != nil {
+				cond.Failed(err, "Failed to create the terraform unlock job")
+
+				return reconcile.Result{}, err
+			}
+
+			if err := c.createJob(ctx, runner); err != nil {
+				cond.Failed(err, "Failed to create the terraform unlock job")
+
+				return reconcile.Result{}, err
+			}
+			cond.InProgress("Releasing the terraform state lock %s", id)
+
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		switch {
+		case jobs.IsFailed(job):
+			// @step: the request is removed so the unlock can be requested again, which runs a new attempt
+			if err := c.removeUnlockRequest(ctx, configuration, false); err != nil {
+				cond.Failed(err, "Failed to remove the unlock annotation from the configuration")
+
+				return reconcile.Result{}, err
+			}
+			configuration.Status.StateLock.FailedUnlocks++
+			cond.Failed(nil, "Failed to release the terraform state lock %s, check the logs of the unlock job before requesting the unlock again", id)
+
+			return reconcile.Result{}, controller.ErrIgnore
+
+		case !jobs.IsComplete(job):
+			cond.InProgress("Releasing the terraform state lock %s", id)
+
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		// @step: record who released the lock for auditing
+		message := fmt.Sprintf("Terraform state lock %s has been force released", id)
+		if by := configuration.GetAnnotations()[terraformv1alphav1.UnlockRequestedByAnnotation]; by != "" {
+			message = fmt.Sprintf("%s, requested by %s", message, by)
+		}
+		c.recorder.Event(configuration, v1.EventTypeNormal, "StateUnlocked", message)
+
+		// @step: the failed destroy is removed so it can run again
+		if configuration.DeletionTimestamp != nil {
+			for i := 0; i < len(list.Items); i++ {
+				if list.Items[i].GetLabels()[terraformv1alphav1.ConfigurationStageLabel] != terraformv1alphav1.StageTerraformDestroy {
+					continue
+				}
+				if !jobs.IsFailed(&list.Items[i]) {
+					continue
+				}
+
+				err := c.cc.Delete(ctx, &list.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground))
+				if client.IgnoreNotFound(err) != nil {
+					cond.Failed(err, "Failed to delete the terraform job (%s/%s)", list.Items[i].Namespace, list.Items[i].Name)
+
+					return reconcile.Result{}, err
+				}
+			}
+		}
+
+		// @step: remove the request, and ask for the plan to be run again
+		if err := c.removeUnlockRequest(ctx, configuration, configuration.DeletionTimestamp == nil); err != nil {
+			cond.Failed(err, "Failed to remove the unlock annotation from the configuration")
+
+			return reconcile.Result{}, err
+		}
+		configuration.Status.StateLock = nil
+		cond.InProgress("Terraform state lock %s has been released", id)
+
+		return controller.RequeueImmediate, nil
+	}
+}
+
+// removeUnlockRequest is called to remove the unlock annotations from the configuration, optionally asking
+// for the failed stage to be run again
+func (c *Controller) removeUnlockRequest(ctx context.Context, configuration *terraformv1alphav1.Configuration, retry bool) error {
+	// @note: we patch a copy so the conditions held on the configuration are not overwritten
+	updated := configuration.DeepCopy()
+	delete(updated.Annotations, terraformv1alphav1.UnlockAnnotation)
+	delete(updated.Annotations, terraformv1alphav1.UnlockRequestedByAnnotation)
+	if retry {
+		updated.Annotations[terraformv1alphav1.RetryAnnotation] = fmt.Sprintf("%d", time.Now().Unix())
+	}
+
+	return c.cc.Patch(ctx, updated, client.MergeFrom(configuration))
+}
@@ -45,7 +45,8 @@ type Controller struct {
 	recorder record.EventRecorder
 	// CheckInterval is the interval the controller checks to trigger drift on the configurations
 	CheckInterval time.Duration
-	// DriftInterval is the minimum time before triggering drift detection on a configuration
+	// DriftInterval is the minimum time before triggering drift detection on a configuration, used when
+	// no drift schedule is defined on the configuration or a policy
 	DriftInterval time.Duration
 	// DriftThreshold is the maximum number of drift checks to run concurrently
 	DriftThreshold float64
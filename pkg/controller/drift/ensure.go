@@ -20,16 +20,24 @@ package drift
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 	"github.com/appvia/terraform-controller/pkg/controller"
+	"github.com/appvia/terraform-controller/pkg/utils/policies"
+	"github.com/appvia/terraform-controller/pkg/utils/schedule"
 )
 
+// maxJitterRatio divides the period between drift checks to give the maximum jitter, i.e. a tenth
+const maxJitterRatio = 10
+
 // ensureConfigurationReadyForDrift is responsible for checking the configuration is ready for drift
 func (c *Controller) ensureConfigurationReadyForDrift(configuration *terraformv1alphav1.Configuration) controller.EnsureFunc {
 	return func(ctx context.Context) (reconcile.Result, error) {
@@ -59,32 +67,68 @@ func (c *Controller) ensureConfigurationReadyForDrift(configuration *terraformv1
 		// if the plan condition does not exist, we ignore
 		case !configuration.Status.HasCondition(terraformv1alphav1.ConditionTerraformPlan):
 			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
-		// if the apply condition does not exist, we ignore
-		case !configuration.Status.HasCondition(terraformv1alphav1.ConditionTerraformApply):
+		// if the apply condition does not exist, we ignore; plan only configurations are never applied
+		case !configuration.Spec.PlanOnly && !configuration.Status.HasCondition(terraformv1alphav1.ConditionTerraformApply):
+			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
+		// if drift previously detected is waiting to be remediated, we ignore
+		case configuration.IsRemediatingDrift():
 			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
 		// if the last plan for this generation failed, we ignore
 		case configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan).IsFailed(configuration.GetGeneration()):
 			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
 		// if the last apply for this generation failed, we ignore
-		case configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply).IsFailed(configuration.GetGeneration()):
+		case !configuration.Spec.PlanOnly && configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply).IsFailed(configuration.GetGeneration()):
 			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
 		// if the configuration plan is already in progress
 		case configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan).InProgress():
 			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
 		// if the configuration apply is already in progress
-		case configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply).InProgress():
+		case !configuration.Spec.PlanOnly && configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply).InProgress():
 			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
 		// if a plan has not been run on the current generation, we ignore
 		case !configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan).IsComplete(configuration.GetGeneration()):
 			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
 		// if the apply for the generation has not been run, we ignore
-		case !configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply).IsComplete(configuration.GetGeneration()):
+		case !configuration.Spec.PlanOnly && !configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply).IsComplete(configuration.GetGeneration()):
 			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
-		// if the last transition on a plan was less than the interval, i.e we've had activity, we ignore
-		case configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan).LastTransitionTime.Add(c.DriftInterval).After(time.Now()):
+		}
+
+		// @step: the next check is scheduled from the last activity on the configuration, i.e. the last
+		// plan or apply, which includes the last drift check
+		recurring, err := c.findDriftSchedule(ctx, configuration)
+		if err != nil {
+			c.recorder.Eventf(configuration, v1.EventTypeWarning, "DriftSchedule", "Failed to evaluate the drift schedule, %v", err)
+
 			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
-		// if the last transition on a apply was less than the interval, i.e we've had activity, we ignore
-		case configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply).LastTransitionTime.Add(c.DriftInterval).After(time.Now()):
+		}
+		last := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformPlan).LastTransitionTime.Time
+		if applied := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply).LastTransitionTime.Time; applied.After(last) {
+			last = applied
+		}
+		// @note: the drift annotation holds the time the last drift check was triggered
+		if checked, err := strconv.ParseInt(configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation], 10, 64); err == nil {
+			if triggered := time.Unix(checked, 0); triggered.After(last) {
+				last = triggered
+			}
+		}
+
+		next := recurring.Next(last)
+		if next.IsZero() {
+			configuration.Status.NextDriftCheck = nil
+
+			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
+		}
+		// @note: the jitter is derived from the uid so the checks are spread out, but stable between reconciles
+		next = next.Add(schedule.Jitter(string(configuration.GetUID()), recurring.Period(last)/maxJitterRatio)).Truncate(time.Second)
+		configuration.Status.NextDriftCheck = &metav1.Time{Time: next}
+
+		switch {
+		// if the next check is not due yet, we ignore
+		case next.After(time.Now()):
+			if until := time.Until(next); until < c.CheckInterval {
+				return reconcile.Result{RequeueAfter: until}, nil
+			}
+
 			return reconcile.Result{RequeueAfter: c.CheckInterval}, nil
 		// if the number of active configuration running a drift exceeds the max percentage, we ignore
 		case len(list.Items) > 1 && running >= c.DriftThreshold:
@@ -95,6 +139,38 @@ func (c *Controller) ensureConfigurationReadyForDrift(configuration *terraformv1
 	}
 }
 
+// findDriftSchedule returns the drift schedule for the configuration, taken from the configuration, a
+// matching policy or the default drift interval
+func (c *Controller) findDriftSchedule(ctx context.Context, configuration *terraformv1alphav1.Configuration) (*schedule.Recurring, error) {
+	drift := configuration.Spec.DriftSchedule
+	if drift == nil {
+		list := &terraformv1alphav1.PolicyList{}
+		if err := c.cc.List(ctx, list); err != nil {
+			return nil, err
+		}
+
+		if len(list.Items) > 0 {
+			namespace := &v1.Namespace{}
+			if err := c.cc.Get(ctx, client.ObjectKey{Name: configuration.GetNamespace()}, namespace); err != nil {
+				return nil, err
+			}
+
+			policy, err := policies.FindMatchingDriftSchedule(ctx, configuration, namespace, list)
+			if err != nil {
+				return nil, err
+			}
+			if policy != nil {
+				drift = &policy.DriftSchedule
+			}
+		}
+	}
+	if drift == nil {
+		return schedule.NewRecurring("", c.DriftInterval, "")
+	}
+
+	return schedule.NewRecurring(drift.Schedule, drift.GetInterval(), drift.TimeZone)
+}
+
 // ensureDriftDetection is responsible for triggering off drift detection on the configuration
 func (c *Controller) ensureDriftDetection(configuration *terraformv1alphav1.Configuration) controller.EnsureFunc {
 	return func(ctx context.Context) (reconcile.Result, error) {
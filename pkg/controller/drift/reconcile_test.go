@@ -95,6 +95,23 @@ var _ = Describe("Drift Controller", func() {
 					cond.Status = metav1.ConditionFalse
 				},
 			},
+			{
+				Name: "configuration is plan only",
+				Check: func(configuration *terraformv1alphav1.Configuration) {
+					configuration.Spec.PlanOnly = true
+
+					cond := configuration.Status.GetCondition(terraformv1alphav1.ConditionTerraformApply)
+					cond.Reason = corev1alphav1.ReasonWarning
+					cond.Status = metav1.ConditionFalse
+				},
+				ShouldDrift: true,
+			},
+			{
+				Name: "drift is waiting to be remediated",
+				Check: func(configuration *terraformv1alphav1.Configuration) {
+					configuration.Status.Drift = &terraformv1alphav1.DriftStatus{Remediation: terraformv1alphav1.RemediationPending}
+				},
+			},
 			{
 				Name: "terraform plan has failed",
 				Check: func(configuration *terraformv1alphav1.Configuration) {
@@ -150,6 +167,23 @@ var _ = Describe("Drift Controller", func() {
 					cond.Status = metav1.ConditionTrue
 				},
 			},
+			{
+				Name: "drift check was triggered recently",
+				Check: func(configuration *terraformv1alphav1.Configuration) {
+					configuration.Annotations = map[string]string{
+						terraformv1alphav1.DriftAnnotation: fmt.Sprintf("%d", time.Now().Add(-30*time.Minute).Unix()),
+					}
+				},
+			},
+			{
+				Name: "drift check was triggered a while ago",
+				Check: func(configuration *terraformv1alphav1.Configuration) {
+					configuration.Annotations = map[string]string{
+						terraformv1alphav1.DriftAnnotation: fmt.Sprintf("%d", time.Now().Add(-3*time.Hour).Unix()),
+					}
+				},
+				ShouldDrift: true,
+			},
 			{
 				Name: "we have multiple configuration in drift already",
 				Before: func(ctrl *Controller) {
@@ -179,6 +213,47 @@ var _ = Describe("Drift Controller", func() {
 				Name:        "configuration should trigger a drift detection",
 				ShouldDrift: true,
 			},
+			{
+				Name: "configuration has an hourly drift schedule",
+				Check: func(configuration *terraformv1alphav1.Configuration) {
+					configuration.Spec.DriftSchedule = &terraformv1alphav1.DriftSchedule{Schedule: "0 * * * *"}
+				},
+				ShouldDrift: true,
+			},
+			{
+				Name: "configuration has a weekly drift schedule",
+				Check: func(configuration *terraformv1alphav1.Configuration) {
+					configuration.Spec.DriftSchedule = &terraformv1alphav1.DriftSchedule{
+						Interval: &metav1.Duration{Duration: 168 * time.Hour},
+					}
+				},
+			},
+			{
+				Name: "configuration has an invalid drift schedule",
+				Check: func(configuration *terraformv1alphav1.Configuration) {
+					configuration.Spec.DriftSchedule = &terraformv1alphav1.DriftSchedule{Schedule: "0 25 * * *"}
+				},
+			},
+			{
+				Name: "policy defines a weekly drift schedule",
+				Before: func(ctrl *Controller) {
+					Expect(ctrl.cc.Create(ctx, fixtures.NewNamespace(namespace))).To(Succeed())
+					Expect(ctrl.cc.Create(ctx, newDriftSchedulePolicy(168*time.Hour))).To(Succeed())
+				},
+			},
+			{
+				Name: "configuration overrides the drift schedule of the policy",
+				Before: func(ctrl *Controller) {
+					Expect(ctrl.cc.Create(ctx, fixtures.NewNamespace(namespace))).To(Succeed())
+					Expect(ctrl.cc.Create(ctx, newDriftSchedulePolicy(168*time.Hour))).To(Succeed())
+				},
+				Check: func(configuration *terraformv1alphav1.Configuration) {
+					configuration.Spec.DriftSchedule = &terraformv1alphav1.DriftSchedule{
+						Interval: &metav1.Duration{Duration: time.Hour},
+					}
+				},
+				ShouldDrift: true,
+			},
 		}
 
 		for _, c := range cases {
@@ -222,6 +297,7 @@ var _ = Describe("Drift Controller", func() {
 					c.Check(configuration)
 				}
 				Expect(ctrl.cc.Create(ctx, configuration)).To(Succeed())
+				previous := configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]
 
 				It("should not return an error", func() {
 					result, _, rerr := controllertests.Roll(ctx, ctrl, configuration, 1)
@@ -236,6 +312,7 @@ var _ = Describe("Drift Controller", func() {
 						Expect(ctrl.cc.Get(ctx, configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
 						Expect(configuration.GetAnnotations()).ToNot(BeEmpty())
 						Expect(configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]).ToNot(BeEmpty())
+						Expect(configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]).ToNot(Equal(previous))
 					})
 
 					It("should have raised a event indicating the trigger", func() {
@@ -245,12 +322,82 @@ var _ = Describe("Drift Controller", func() {
 					})
 
 				default:
-					It("should not have a drift annotation", func() {
+					It("should not have a new drift annotation", func() {
 						Expect(ctrl.cc.Get(ctx, configuration.GetNamespacedName(), configuration)).ToNot(HaveOccurred())
-						Expect(configuration.GetAnnotations()).To(BeEmpty())
+						Expect(configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]).To(Equal(previous))
 					})
 				}
 			})
 		}
 	})
+
+	When("configuration has a drift schedule", func() {
+		var ctrl *Controller
+		var configuration *terraformv1alphav1.Configuration
+		var last time.Time
+
+		BeforeEach(func() {
+			ctrl = &Controller{
+				CheckInterval:  5 * time.Minute,
+				DriftInterval:  2 * time.Hour,
+				DriftThreshold: 0.2,
+				cc:             fake.NewFakeClientWithScheme(schema.GetScheme()),
+				recorder:       &controllertests.FakeRecorder{},
+			}
+			last = time.Now().Add(-5 * time.Hour).Truncate(time.Second)
+
+			configuration = fixtures.NewValidBucketConfiguration(namespace, "test")
+			configuration.Spec.EnableDriftDetection = true
+			configuration.Spec.DriftSchedule = &terraformv1alphav1.DriftSchedule{
+				Interval: &metav1.Duration{Duration: 10 * time.Hour},
+			}
+			controller.EnsureConditionsRegistered(terraformv1alphav1.DefaultConfigurationConditions, configuration)
+			for _, name := range []corev1alphav1.ConditionType{terraformv1alphav1.ConditionTerraformPlan, terraformv1alphav1.ConditionTerraformApply} {
+				cond := configuration.Status.GetCondition(name)
+				cond.Reason = corev1alphav1.ReasonComplete
+				cond.LastTransitionTime = metav1.NewTime(last)
+				cond.ObservedGeneration = configuration.GetGeneration()
+				cond.Status = metav1.ConditionTrue
+			}
+			Expect(ctrl.cc.Create(ctx, configuration)).To(Succeed())
+		})
+
+		It("should show the next drift check in the status", func() {
+			_, _, rerr := controllertests.Roll(ctx, ctrl, configuration, 1)
+			Expect(rerr).To(BeNil())
+			Expect(ctrl.cc.Get(ctx, configuration.GetNamespacedName(), configuration)).To(Succeed())
+
+			next := configuration.Status.NextDriftCheck
+			Expect(next).ToNot(BeNil())
+			Expect(next.Time.Before(last.Add(10 * time.Hour))).To(BeFalse())
+			Expect(next.Time.Before(last.Add(11 * time.Hour))).To(BeTrue())
+		})
+
+		It("should schedule the same check on every reconcile", func() {
+			_, _, rerr := controllertests.Roll(ctx, ctrl, configuration, 1)
+			Expect(rerr).To(BeNil())
+			Expect(ctrl.cc.Get(ctx, configuration.GetNamespacedName(), configuration)).To(Succeed())
+			first := configuration.Status.NextDriftCheck.DeepCopy()
+
+			_, _, rerr = controllertests.Roll(ctx, ctrl, configuration, 1)
+			Expect(rerr).To(BeNil())
+			Expect(ctrl.cc.Get(ctx, configuration.GetNamespacedName(), configuration)).To(Succeed())
+			Expect(configuration.Status.NextDriftCheck.Time.Equal(first.Time)).To(BeTrue())
+		})
+	})
 })
+
+// newDriftSchedulePolicy returns a policy defining a drift schedule for all configurations
+func newDriftSchedulePolicy(interval time.Duration) *terraformv1alphav1.Policy {
+	policy := &terraformv1alphav1.Policy{}
+	policy.Name = "drift"
+	policy.Spec.Constraints = &terraformv1alphav1.Constraints{
+		DriftSchedule: &terraformv1alphav1.DriftScheduleConstraint{
+			DriftSchedule: terraformv1alphav1.DriftSchedule{
+				Interval: &metav1.Duration{Duration: interval},
+			},
+		},
+	}
+
+	return policy
+}
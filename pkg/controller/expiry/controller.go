@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2022 Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package expiry
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/record"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+)
+
+const controllerName = "expiry.terraform.appvia.io"
+
+// Controller handles the expiry of configurations with a time to live
+type Controller struct {
+	// cc is the kubernetes client to the cluster
+	cc client.Client
+	// recorder is the kubernetes event recorder
+	recorder record.EventRecorder
+	// WarningPeriod is the duration before the expiry a warning event is raised on the configuration
+	WarningPeriod time.Duration
+}
+
+// Add is called to setup the manager for the controller
+func (c *Controller) Add(mgr manager.Manager) error {
+	log.WithFields(log.Fields{
+		"warning": c.WarningPeriod.String(),
+	}).Info("adding the expiry controller")
+
+	if c.WarningPeriod < 0 {
+		return fmt.Errorf("warning period must be greater than or equal to 0")
+	}
+
+	c.cc = mgr.GetClient()
+	c.recorder = mgr.GetEventRecorderFor(controllerName)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&terraformv1alphav1.Configuration{}).
+		Named(controllerName).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 1}).
+		WithEventFilter(&predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				switch {
+				case !hasExpiry(e.ObjectNew.(*terraformv1alphav1.Configuration)):
+					return false
+				case !e.ObjectNew.GetDeletionTimestamp().IsZero():
+					return false
+				}
+
+				// @note: we only care about changes to the ttl or the expires-at annotation, the
+				// reconciliation is otherwise requeued for the time of the warning or expiry
+				return e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration() ||
+					!reflect.DeepEqual(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations())
+			},
+			CreateFunc: func(e event.CreateEvent) bool {
+				return hasExpiry(e.Object.(*terraformv1alphav1.Configuration)) && e.Object.GetDeletionTimestamp().IsZero()
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return false
+			},
+		}).
+		Complete(c)
+}
+
+// hasExpiry returns true if the configuration has a ttl or expiry annotation
+func hasExpiry(configuration *terraformv1alphav1.Configuration) bool {
+	return configuration.Spec.TTL != nil || configuration.GetAnnotations()[terraformv1alphav1.ExpiresAtAnnotation] != ""
+}
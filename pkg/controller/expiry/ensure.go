@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2022 Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package expiry
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/controller"
+)
+
+// ensureExpiryTime is responsible for calculating when the configuration expires, and recording it
+// on the status
+func (c *Controller) ensureExpiryTime(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
+	return func(ctx context.Context) (reconcile.Result, error) {
+		expires, found, err := configuration.GetExpiryTime()
+		if err != nil {
+			c.recorder.Event(configuration, v1.EventTypeWarning, "Expiry", err.Error())
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+		if !found {
+			configuration.Status.ExpiresAt = nil
+
+			return reconcile.Result{}, controller.ErrIgnore
+		}
+		state.expires = expires
+
+		configuration.Status.ExpiresAt = &metav1.Time{Time: expires}
+
+		return reconcile.Result{}, nil
+	}
+}
+
+// ensureExpiryWarning is responsible for raising a warning on the configuration ahead of the expiry
+func (c *Controller) ensureExpiryWarning(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
+	return func(ctx context.Context) (reconcile.Result, error) {
+		now := time.Now()
+
+		switch {
+		case !now.Before(state.expires):
+			return reconcile.Result{}, nil
+
+		case now.Before(state.expires.Add(-c.WarningPeriod)):
+			// @note: we requeue for the time the warning should be raised
+			return reconcile.Result{RequeueAfter: state.expires.Add(-c.WarningPeriod).Sub(now)}, nil
+		}
+
+		c.recorder.Eventf(configuration, v1.EventTypeWarning, "Expiry",
+			"Configuration expires at %s and will be deleted, set the %s annotation to extend",
+			state.expires.Format(time.RFC3339), terraformv1alphav1.ExpiresAtAnnotation)
+
+		return reconcile.Result{RequeueAfter: state.expires.Sub(now)}, nil
+	}
+}
+
+// ensureExpired is responsible for deleting the expired configuration, the resources are destroyed
+// by the configuration controller as with any other deletion
+func (c *Controller) ensureExpired(configuration *terraformv1alphav1.Configuration, state *state) controller.EnsureFunc {
+	return func(ctx context.Context) (reconcile.Result, error) {
+		log.WithFields(log.Fields{
+			"expires":   state.expires.Format(time.RFC3339),
+			"name":      configuration.GetName(),
+			"namespace": configuration.GetNamespace(),
+		}).Info("configuration has expired, deleting the configuration")
+
+		if err := c.cc.Delete(ctx, configuration); err != nil {
+			if err := client.IgnoreNotFound(err); err != nil {
+				c.recorder.Event(configuration, v1.EventTypeWarning, "Expiry", "Failed to delete the expired configuration")
+
+				return reconcile.Result{}, err
+			}
+		}
+		c.recorder.Eventf(configuration, v1.EventTypeWarning, "Expired",
+			"Configuration expired at %s and is being deleted", state.expires.Format(time.RFC3339))
+
+		expiredMetric.WithLabelValues(configuration.GetNamespace()).Inc()
+
+		return reconcile.Result{}, controller.ErrIgnore
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (C) 2022 Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package expiry
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/schema"
+	controllertests "github.com/appvia/terraform-controller/test"
+	"github.com/appvia/terraform-controller/test/fixtures"
+)
+
+func TestReconcile(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Running Test Suite")
+}
+
+var _ = Describe("Expiry Controller", func() {
+	logrus.SetOutput(ioutil.Discard)
+
+	ctx := context.TODO()
+	namespace := "default"
+
+	var ctrl *Controller
+	var events *controllertests.FakeRecorder
+	var configuration *terraformv1alphav1.Configuration
+	var result reconcile.Result
+	var rerr error
+
+	BeforeEach(func() {
+		events = &controllertests.FakeRecorder{}
+		ctrl = &Controller{
+			WarningPeriod: time.Hour,
+			cc:            fake.NewFakeClientWithScheme(schema.GetScheme()),
+			recorder:      events,
+		}
+
+		configuration = fixtures.NewValidBucketConfiguration(namespace, "test")
+		configuration.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	})
+
+	When("the configuration does not expire", func() {
+		BeforeEach(func() {
+			Expect(ctrl.cc.Create(ctx, configuration)).To(Succeed())
+			result, _, rerr = controllertests.Roll(ctx, ctrl, configuration, 1)
+		})
+
+		It("should not requeue", func() {
+			Expect(rerr).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+		})
+
+		It("should not have raised any events", func() {
+			Expect(events.Events).To(BeEmpty())
+		})
+	})
+
+	When("the expires-at annotation is invalid", func() {
+		BeforeEach(func() {
+			configuration.Annotations = map[string]string{terraformv1alphav1.ExpiresAtAnnotation: "tomorrow"}
+			Expect(ctrl.cc.Create(ctx, configuration)).To(Succeed())
+			result, _, rerr = controllertests.Roll(ctx, ctrl, configuration, 1)
+		})
+
+		It("should have raised a warning event", func() {
+			Expect(events.Events).To(Equal([]string{
+				`(default/test) Warning Expiry: terraform.appvia.io/expires-at annotation: "tomorrow" is not a valid RFC3339 time`,
+			}))
+		})
+	})
+
+	When("the configuration expires outside of the warning period", func() {
+		BeforeEach(func() {
+			configuration.Spec.TTL = &metav1.Duration{Duration: 5 * time.Hour}
+			Expect(ctrl.cc.Create(ctx, configuration)).To(Succeed())
+			result, _, rerr = controllertests.Roll(ctx, ctrl, configuration, 1)
+		})
+
+		It("should requeue for the warning period", func() {
+			Expect(rerr).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", 2*time.Hour, time.Minute))
+		})
+
+		It("should have recorded the expiry on the status", func() {
+			Expect(ctrl.cc.Get(ctx, configuration.GetNamespacedName(), configuration)).To(Succeed())
+			Expect(configuration.Status.ExpiresAt).ToNot(BeNil())
+			Expect(configuration.Status.ExpiresAt.Time).To(BeTemporally("~", configuration.CreationTimestamp.Add(5*time.Hour), time.Second))
+		})
+
+		It("should not have raised any events", func() {
+			Expect(events.Events).To(BeEmpty())
+		})
+	})
+
+	When("the configuration expires within the warning period", func() {
+		BeforeEach(func() {
+			configuration.Spec.TTL = &metav1.Duration{Duration: 150 * time.Minute}
+			Expect(ctrl.cc.Create(ctx, configuration)).To(Succeed())
+			result, _, rerr = controllertests.Roll(ctx, ctrl, configuration, 1)
+		})
+
+		It("should requeue for the expiry", func() {
+			Expect(rerr).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Minute, time.Minute))
+		})
+
+		It("should have raised a warning event", func() {
+			Expect(events.Events).To(HaveLen(1))
+			Expect(events.Events[0]).To(ContainSubstring("Warning Expiry: Configuration expires at "))
+			Expect(events.Events[0]).To(ContainSubstring("set the terraform.appvia.io/expires-at annotation to extend"))
+		})
+
+		It("should not have deleted the configuration", func() {
+			Expect(ctrl.cc.Get(ctx, configuration.GetNamespacedName(), configuration)).To(Succeed())
+		})
+	})
+
+	When("the configuration has expired", func() {
+		BeforeEach(func() {
+			configuration.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+			Expect(ctrl.cc.Create(ctx, configuration)).To(Succeed())
+			result, _, rerr = controllertests.Roll(ctx, ctrl, configuration, 1)
+		})
+
+		It("should not return an error", func() {
+			Expect(rerr).ToNot(HaveOccurred())
+		})
+
+		It("should have deleted the configuration", func() {
+			err := ctrl.cc.Get(ctx, configuration.GetNamespacedName(), configuration)
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should have raised an expired event", func() {
+			Expect(events.Events).To(HaveLen(1))
+			Expect(events.Events[0]).To(ContainSubstring("Warning Expired: Configuration expired at "))
+		})
+	})
+
+	When("the expiry has been extended via the annotation", func() {
+		BeforeEach(func() {
+			configuration.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+			configuration.Annotations = map[string]string{
+				terraformv1alphav1.ExpiresAtAnnotation: time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
+			}
+			Expect(ctrl.cc.Create(ctx, configuration)).To(Succeed())
+			result, _, rerr = controllertests.Roll(ctx, ctrl, configuration, 1)
+		})
+
+		It("should not have deleted the configuration", func() {
+			Expect(ctrl.cc.Get(ctx, configuration.GetNamespacedName(), configuration)).To(Succeed())
+		})
+
+		It("should requeue for the warning period", func() {
+			Expect(result.RequeueAfter).To(BeNumerically("~", 23*time.Hour, time.Minute))
+		})
+	})
+})
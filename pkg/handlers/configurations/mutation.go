@@ -49,6 +49,15 @@ func (m *mutator) Default(ctx context.Context, obj runtime.Object) error {
 		return fmt.Errorf("expected terraform configuration, not %T", obj)
 	}
 
+	// @step: record the identity approving the configuration
+	m.mutateApprovedBy(ctx, o)
+
+	// @step: record the identity requesting the state lock is released
+	m.mutateUnlockRequestedBy(ctx, o)
+
+	// @step: record the identity approving the destroy of the configuration
+	m.mutateDestroyApprovedBy(ctx, o)
+
 	// @step: retrieve a list of all policies
 	list := &terraformv1alphav1.PolicyList{}
 	if err := m.cc.List(ctx, list); err != nil {
@@ -65,6 +74,63 @@ func (m *mutator) Default(ctx context.Context, obj runtime.Object) error {
 	return nil
 }
 
+// mutateApprovedBy is called to record the user who approved the apply of the configuration, the
+// annotation is removed when the approval is revoked
+func (m *mutator) mutateApprovedBy(ctx context.Context, o *terraformv1alphav1.Configuration) {
+	if !o.HasApproval() {
+		delete(o.Annotations, terraformv1alphav1.ApprovedByAnnotation)
+
+		return
+	}
+	if o.Annotations[terraformv1alphav1.ApprovedByAnnotation] != "" {
+		return
+	}
+
+	request, err := admission.RequestFromContext(ctx)
+	if err != nil || request.UserInfo.Username == "" {
+		return
+	}
+	o.Annotations[terraformv1alphav1.ApprovedByAnnotation] = request.UserInfo.Username
+}
+
+// mutateUnlockRequestedBy is called to record the user who requested the terraform state lock is
+// released, the annotation is removed along with the request
+func (m *mutator) mutateUnlockRequestedBy(ctx context.Context, o *terraformv1alphav1.Configuration) {
+	if o.GetAnnotations()[terraformv1alphav1.UnlockAnnotation] == "" {
+		delete(o.Annotations, terraformv1alphav1.UnlockRequestedByAnnotation)
+
+		return
+	}
+	if o.Annotations[terraformv1alphav1.UnlockRequestedByAnnotation] != "" {
+		return
+	}
+
+	request, err := admission.RequestFromContext(ctx)
+	if err != nil || request.UserInfo.Username == "" {
+		return
+	}
+	o.Annotations[terraformv1alphav1.UnlockRequestedByAnnotation] = request.UserInfo.Username
+}
+
+// mutateDestroyApprovedBy is called to record the user who approved the destroy of the configuration, the
+// annotation is removed when the approval is revoked
+func (m *mutator) mutateDestroyApprovedBy(ctx context.Context, o *terraformv1alphav1.Configuration) {
+	if !o.IsDestroyApproved() {
+		delete(o.Annotations, terraformv1alphav1.DestroyApprovedByAnnotation)
+
+		return
+	}
+	if o.Annotations[terraformv1alphav1.DestroyApprovedByAnnotation] != "" {
+		return
+	}
+
+	request, err := admission.RequestFromContext(ctx)
+	if err != nil || request.UserInfo.Username == "" {
+		return
+	}
+	o.Annotations[terraformv1alphav1.DestroyApprovedByAnnotation] = request.UserInfo.Username
+}
+
 // mutateOnDefaults is called to validate the module policy enforced
 func (m *mutator) mutateOnDefaults(ctx context.Context, list *terraformv1alphav1.PolicyList, o *terraformv1alphav1.Configuration) error {
 
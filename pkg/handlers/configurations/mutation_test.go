@@ -23,10 +23,13 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 	"github.com/appvia/terraform-controller/pkg/schema"
@@ -245,4 +248,82 @@ var _ = Describe("Configuration Mutation", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	When("the configuration is approved", func() {
+		BeforeEach(func() {
+			policies = nil
+			before = fixtures.NewValidBucketConfiguration("default", "test")
+			before.Annotations = map[string]string{terraformv1alphav1.ApplyAnnotation: "true"}
+		})
+
+		It("should record who approved the configuration", func() {
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "jane"}},
+			})
+			Expect(m.Default(ctx, after)).To(Succeed())
+			Expect(after.Annotations[terraformv1alphav1.ApprovedByAnnotation]).To(Equal("jane"))
+		})
+
+		It("should not overwrite the existing approver", func() {
+			after.Annotations[terraformv1alphav1.ApprovedByAnnotation] = "john"
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "jane"}},
+			})
+			Expect(m.Default(ctx, after)).To(Succeed())
+			Expect(after.Annotations[terraformv1alphav1.ApprovedByAnnotation]).To(Equal("john"))
+		})
+
+		It("should remove the approver when the approval is revoked", func() {
+			after.Annotations[terraformv1alphav1.ApplyAnnotation] = "false"
+			after.Annotations[terraformv1alphav1.ApprovedByAnnotation] = "john"
+			Expect(m.Default(context.Background(), after)).To(Succeed())
+			Expect(after.Annotations).ToNot(HaveKey(terraformv1alphav1.ApprovedByAnnotation))
+		})
+	})
+
+	When("the configuration has requested the state lock is released", func() {
+		BeforeEach(func() {
+			policies = nil
+			before = fixtures.NewValidBucketConfiguration("default", "test")
+			before.Annotations = map[string]string{terraformv1alphav1.UnlockAnnotation: "9db590f1-b6fe-c5f2-2678-8804f089deba"}
+		})
+
+		It("should record who requested the unlock", func() {
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "jane"}},
+			})
+			Expect(m.Default(ctx, after)).To(Succeed())
+			Expect(after.Annotations[terraformv1alphav1.UnlockRequestedByAnnotation]).To(Equal("jane"))
+		})
+
+		It("should remove the requester along with the request", func() {
+			delete(after.Annotations, terraformv1alphav1.UnlockAnnotation)
+			after.Annotations[terraformv1alphav1.UnlockRequestedByAnnotation] = "john"
+			Expect(m.Default(context.Background(), after)).To(Succeed())
+			Expect(after.Annotations).ToNot(HaveKey(terraformv1alphav1.UnlockRequestedByAnnotation))
+		})
+	})
+
+	When("the configuration destroy has been approved", func() {
+		BeforeEach(func() {
+			policies = nil
+			before = fixtures.NewValidBucketConfiguration("default", "test")
+			before.Annotations = map[string]string{terraformv1alphav1.DestroyApprovedAnnotation: "true"}
+		})
+
+		It("should record who approved the destroy", func() {
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "jane"}},
+			})
+			Expect(m.Default(ctx, after)).To(Succeed())
+			Expect(after.Annotations[terraformv1alphav1.DestroyApprovedByAnnotation]).To(Equal("jane"))
+		})
+
+		It("should remove the approver when the approval is revoked", func() {
+			after.Annotations[terraformv1alphav1.DestroyApprovedAnnotation] = "false"
+			after.Annotations[terraformv1alphav1.DestroyApprovedByAnnotation] = "john"
+			Expect(m.Default(context.Background(), after)).To(Succeed())
+			Expect(after.Annotations).ToNot(HaveKey(terraformv1alphav1.DestroyApprovedByAnnotation))
+		})
+	})
 })
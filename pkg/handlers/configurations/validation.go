@@ -31,6 +31,8 @@ import (
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 	"github.com/appvia/terraform-controller/pkg/utils"
 	"github.com/appvia/terraform-controller/pkg/utils/kubernetes"
+	"github.com/appvia/terraform-controller/pkg/utils/schedule"
+	"github.com/appvia/terraform-controller/pkg/utils/terraform"
 )
 
 type validator struct {
@@ -88,6 +90,11 @@ func (v *validator) validate(ctx context.Context, before, configuration *terrafo
 		}
 
 	default:
+		// @note: the workspace forms part of the state location, changing it would orphan the state
+		if before.GetWorkspace() != configuration.GetWorkspace() {
+			return errors.New("spec.workspace cannot be changed")
+		}
+
 		if !v.versioning {
 			switch {
 			case configuration.Spec.TerraformVersion == "":
@@ -105,6 +112,71 @@ func (v *validator) validate(ctx context.Context, before, configuration *terrafo
 		return err
 	}
 
+	// @step: check the value from sources
+	if err := validateValueFrom(configuration); err != nil {
+		return err
+	}
+
+	// @step: check any resources being imported
+	if err := validateImports(configuration); err != nil {
+		return err
+	}
+
+	// @step: check the replace and target operations are valid
+	if err := validateOperations(configuration); err != nil {
+		return err
+	}
+
+	// @step: check the state lock being released is valid
+	if id, found := configuration.GetAnnotations()[terraformv1alphav1.UnlockAnnotation]; found && !terraform.IsValidLockID(id) {
+		return fmt.Errorf("%s annotation: %q is not a valid state lock id", terraformv1alphav1.UnlockAnnotation, id)
+	}
+
+	// @step: check the time to live and expiry are valid
+	if ttl := configuration.Spec.TTL; ttl != nil && ttl.Duration <= 0 {
+		return errors.New("spec.ttl must be greater than zero")
+	}
+	if _, _, err := configuration.GetExpiryTime(); err != nil {
+		return err
+	}
+
+	// @step: check the drift remediation can be performed
+	if configuration.GetDriftRemediation() != terraformv1alphav1.DriftRemediationNone {
+		switch {
+		case !configuration.Spec.EnableDriftDetection:
+			return errors.New("spec.driftRemediation requires spec.enableDriftDetection to be enabled")
+		case configuration.Spec.PlanOnly:
+			return errors.New("spec.driftRemediation cannot be used with spec.planOnly")
+		}
+	}
+
+	// @step: check the apply window is valid
+	if err := validateApplyWindow(configuration); err != nil {
+		return err
+	}
+
+	// @step: check the drift schedule is valid
+	if drift := configuration.Spec.DriftSchedule; drift != nil {
+		if _, err := schedule.NewRecurring(drift.Schedule, drift.GetInterval(), drift.TimeZone); err != nil {
+			return fmt.Errorf("spec.driftSchedule is invalid, %v", err)
+		}
+	}
+
+	// @step: check the retry policy is valid
+	if retry := configuration.Spec.Retry; retry != nil {
+		switch {
+		case retry.MaxAttempts < 0:
+			return errors.New("spec.retry.maxAttempts must be greater than zero")
+		case retry.Backoff != nil && retry.Backoff.Duration < 0:
+			return errors.New("spec.retry.backoff must not be negative")
+		}
+	}
+
+	// @step: check the dependencies do not form a cycle
+	if err := validateDependencies(ctx, v.cc, configuration); err != nil {
+		return err
+	}
+
 	// @step: grab the namespace of the configuration
 	namespace := &v1.Namespace{}
 	namespace.Name = configuration.Namespace
@@ -155,6 +227,130 @@ func validateConnectionSecret(configuration *terraformv1alphav1.Configuration) e
 	return nil
 }
 
+// validateValueFrom checks the value from sources are valid
+func validateValueFrom(configuration *terraformv1alphav1.Configuration) error {
+	for i, x := range configuration.Spec.ValueFrom {
+		switch {
+		case x.Key == "":
+			return fmt.Errorf("spec.valueFrom[%d].key is empty", i)
+		case x.Secret == "" && x.Configuration == "":
+			return fmt.Errorf("spec.valueFrom[%d] requires either a secret or configuration", i)
+		case x.Secret != "" && x.Configuration != "":
+			return fmt.Errorf("spec.valueFrom[%d] can only have one of secret or configuration", i)
+		case x.Configuration == configuration.Name:
+			return fmt.Errorf("spec.valueFrom[%d].configuration cannot reference itself", i)
+		}
+	}
+
+	return nil
+}
+
+// validateDependencies checks the dependencies are valid and would not create a circular dependency
+// with the other configurations in the namespace
+func validateDependencies(ctx context.Context, cc client.Client, configuration *terraformv1alphav1.Configuration) error {
+	for i, x := range configuration.Spec.DependsOn {
+		switch {
+		case x == "":
+			return fmt.Errorf("spec.dependsOn[%d] is empty", i)
+		case x == configuration.Name:
+			return fmt.Errorf("spec.dependsOn[%d] cannot reference itself", i)
+		}
+	}
+
+	dependencies := configuration.GetDependencies()
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	list := &terraformv1alphav1.ConfigurationList{}
+	if err := cc.List(ctx, list, client.InNamespace(configuration.Namespace)); err != nil {
+		return err
+	}
+
+	graph := map[string][]string{configuration.Name: dependencies}
+	for _, x := range list.Items {
+		if x.Name != configuration.Name {
+			graph[x.Name] = x.GetDependencies()
+		}
+	}
+
+	// @step: walk the dependencies looking for a path back to the configuration
+	visited := make(map[string]bool)
+	pending := append([]string{}, dependencies...)
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+
+		switch {
+		case name == configuration.Name:
+			return errors.New("spec.dependsOn creates a circular dependency between configurations")
+		case visited[name]:
+			continue
+		}
+		visited[name] = true
+		pending = append(pending, graph[name]...)
+	}
+
+	return nil
+}
+
+// validateApplyWindow checks the schedule and time zone of the apply window
+func validateApplyWindow(configuration *terraformv1alphav1.Configuration) error {
+	window := configuration.Spec.ApplyWindow
+	if window == nil {
+		return nil
+	}
+	if _, err := schedule.NewWindow(window.Schedule, window.Duration.Duration, window.TimeZone); err != nil {
+		return fmt.Errorf("spec.applyWindow is invalid, %v", err)
+	}
+
+	return nil
+}
+
+// validateImports checks the existing resources being imported are valid
+func validateImports(configuration *terraformv1alphav1.Configuration) error {
+	if configuration.Spec.ImportState != nil && configuration.Spec.ImportState.Name == "" {
+		return errors.New("spec.importState.name is empty")
+	}
+
+	addresses := make(map[string]bool)
+	for i, x := range configuration.Spec.Imports {
+		switch {
+		case x.Address == "":
+			return fmt.Errorf("spec.imports[%d].address is empty", i)
+		case !terraform.IsValidAddress(x.Address):
+			return fmt.Errorf("spec.imports[%d].address: %q is not a valid terraform address", i, x.Address)
+		case x.ID == "":
+			return fmt.Errorf("spec.imports[%d].id is empty", i)
+		case addresses[x.Address]:
+			return fmt.Errorf("spec.imports[%d].address: %s is imported more than once", i, x.Address)
+		}
+		addresses[x.Address] = true
+	}
+
+	return nil
+}
+
+// validateOperations checks the replace and target addresses are valid
+func validateOperations(configuration *terraformv1alphav1.Configuration) error {
+	if configuration.Spec.Operations == nil {
+		return nil
+	}
+
+	for i, x := range configuration.Spec.Operations.Replace {
+		if !terraform.IsValidAddress(x) {
+			return fmt.Errorf("spec.operations.replace[%d]: %q is not a valid terraform address", i, x)
+		}
+	}
+	for i, x := range configuration.Spec.Operations.Target {
+		if !terraform.IsValidAddress(x) {
+			return fmt.Errorf("spec.operations.target[%d]: %q is not a valid terraform address", i, x)
+		}
+	}
+
+	return nil
+}
+
 // validateProvider is called to ensure the configuration is valid and inline with current provider policy
 func validateProvider(ctx context.Context, cc client.Client, configuration *terraformv1alphav1.Configuration, namespace *v1.Namespace) error {
 	provider := &terraformv1alphav1.Provider{}
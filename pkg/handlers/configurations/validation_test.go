@@ -19,6 +19,7 @@ package configurations
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -100,6 +101,352 @@ var _ = Describe("Configuration Validation", func() {
 		})
 	})
 
+	When("we have value from sources", func() {
+		var configuration *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
+			v = &validator{cc: cc, versioning: true}
+
+			Expect(cc.Create(ctx, fixtures.NewValidAWSReadyProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name)))).To(Succeed())
+			configuration = fixtures.NewValidBucketConfiguration(namespace, name)
+		})
+
+		It("should fail when both a secret and configuration are defined", func() {
+			configuration.Spec.ValueFrom = []terraformv1alphav1.ValueFromSource{{Key: "vpc_id", Secret: "vpc", Configuration: "vpc"}}
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.valueFrom[0] can only have one of secret or configuration"))
+		})
+
+		It("should fail when the configuration references itself", func() {
+			configuration.Spec.ValueFrom = []terraformv1alphav1.ValueFromSource{{Key: "vpc_id", Configuration: configuration.Name}}
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.valueFrom[0].configuration cannot reference itself"))
+		})
+
+		It("should not fail when referencing another configuration", func() {
+			configuration.Spec.ValueFrom = []terraformv1alphav1.ValueFromSource{{Key: "vpc_id", Configuration: "vpc"}}
+
+			Expect(v.ValidateCreate(ctx, configuration)).To(Succeed())
+		})
+	})
+
+	When("we have dependencies", func() {
+		var configuration *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
+			v = &validator{cc: cc, versioning: true}
+
+			Expect(cc.Create(ctx, fixtures.NewValidAWSReadyProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name)))).To(Succeed())
+			configuration = fixtures.NewValidBucketConfiguration(namespace, name)
+		})
+
+		It("should fail when the configuration depends on itself", func() {
+			configuration.Spec.DependsOn = []string{configuration.Name}
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.dependsOn[0] cannot reference itself"))
+		})
+
+		It("should fail when the dependencies are circular", func() {
+			database := fixtures.NewValidBucketConfiguration(namespace, "database")
+			database.Spec.DependsOn = []string{"vpc"}
+			vpc := fixtures.NewValidBucketConfiguration(namespace, "vpc")
+			vpc.Spec.ValueFrom = []terraformv1alphav1.ValueFromSource{{Key: "bucket", Configuration: configuration.Name}}
+			Expect(cc.Create(ctx, database)).To(Succeed())
+			Expect(cc.Create(ctx, vpc)).To(Succeed())
+
+			configuration.Spec.DependsOn = []string{"database"}
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.dependsOn creates a circular dependency between configurations"))
+		})
+
+		It("should not fail when depending on another configuration", func() {
+			configuration.Spec.DependsOn = []string{"vpc"}
+
+			Expect(v.ValidateCreate(ctx, configuration)).To(Succeed())
+		})
+	})
+
+	When("we have an apply window", func() {
+		var configuration *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
+			v = &validator{cc: cc, versioning: true}
+
+			Expect(cc.Create(ctx, fixtures.NewValidAWSReadyProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name)))).To(Succeed())
+			configuration = fixtures.NewValidBucketConfiguration(namespace, name)
+			configuration.Spec.ApplyWindow = &terraformv1alphav1.ApplyWindow{
+				Schedule: "0 9 * * mon-thu",
+				Duration: metav1.Duration{Duration: 8 * time.Hour},
+			}
+		})
+
+		It("should fail when the schedule is invalid", func() {
+			configuration.Spec.ApplyWindow.Schedule = "0 9 * *"
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.applyWindow is invalid, expected 5 fields in cron expression \"0 9 * *\", found 4"))
+		})
+
+		It("should fail when the duration is missing", func() {
+			configuration.Spec.ApplyWindow.Duration = metav1.Duration{}
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.applyWindow is invalid, window duration must be greater than zero"))
+		})
+
+		It("should not fail when the window is valid", func() {
+			configuration.Spec.ApplyWindow.TimeZone = "America/New_York"
+
+			Expect(v.ValidateCreate(ctx, configuration)).To(Succeed())
+		})
+	})
+
+	When("we have a retry policy", func() {
+		var configuration *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
+			v = &validator{cc: cc, versioning: true}
+
+			Expect(cc.Create(ctx, fixtures.NewValidAWSReadyProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name)))).To(Succeed())
+			configuration = fixtures.NewValidBucketConfiguration(namespace, name)
+			configuration.Spec.Retry = &terraformv1alphav1.Retry{
+				MaxAttempts: 3,
+				Backoff:     &metav1.Duration{Duration: time.Minute},
+			}
+		})
+
+		It("should fail when the backoff is negative", func() {
+			configuration.Spec.Retry.Backoff.Duration = -time.Minute
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.retry.backoff must not be negative"))
+		})
+
+		It("should not fail when the retry policy is valid", func() {
+			Expect(v.ValidateCreate(ctx, configuration)).To(Succeed())
+		})
+	})
+
+	When("we have replace and target operations", func() {
+		var configuration *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
+			v = &validator{cc: cc, versioning: true}
+
+			Expect(cc.Create(ctx, fixtures.NewValidAWSReadyProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name)))).To(Succeed())
+			configuration = fixtures.NewValidBucketConfiguration(namespace, name)
+			configuration.Spec.Operations = &terraformv1alphav1.Operations{
+				Replace: []string{"aws_s3_bucket.this"},
+				Target:  []string{"module.db"},
+			}
+		})
+
+		It("should fail when an address is invalid", func() {
+			configuration.Spec.Operations.Target = append(configuration.Spec.Operations.Target, "module.db; rm -rf /")
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`spec.operations.target[1]: "module.db; rm -rf /" is not a valid terraform address`))
+		})
+
+		It("should not fail when the operations are valid", func() {
+			Expect(v.ValidateCreate(ctx, configuration)).To(Succeed())
+		})
+	})
+
+	When("we have requested the state lock is released", func() {
+		var configuration *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
+			v = &validator{cc: cc, versioning: true}
+
+			Expect(cc.Create(ctx, fixtures.NewValidAWSReadyProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name)))).To(Succeed())
+			configuration = fixtures.NewValidBucketConfiguration(namespace, name)
+			configuration.Annotations = map[string]string{terraformv1alphav1.UnlockAnnotation: "9db590f1-b6fe-c5f2-2678-8804f089deba"}
+		})
+
+		It("should fail when the lock id is invalid", func() {
+			configuration.Annotations[terraformv1alphav1.UnlockAnnotation] = "-force"
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`terraform.appvia.io/unlock annotation: "-force" is not a valid state lock id`))
+		})
+
+		It("should not fail when the lock id is valid", func() {
+			Expect(v.ValidateCreate(ctx, configuration)).To(Succeed())
+		})
+	})
+
+	When("we have a configuration which expires", func() {
+		var configuration *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
+			v = &validator{cc: cc, versioning: true}
+
+			Expect(cc.Create(ctx, fixtures.NewValidAWSReadyProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name)))).To(Succeed())
+			configuration = fixtures.NewValidBucketConfiguration(namespace, name)
+			configuration.Spec.TTL = &metav1.Duration{Duration: 24 * time.Hour}
+		})
+
+		It("should fail when the ttl is not positive", func() {
+			configuration.Spec.TTL.Duration = 0
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.ttl must be greater than zero"))
+		})
+
+		It("should fail when the expires-at annotation is invalid", func() {
+			configuration.Annotations = map[string]string{terraformv1alphav1.ExpiresAtAnnotation: "tomorrow"}
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`terraform.appvia.io/expires-at annotation: "tomorrow" is not a valid RFC3339 time`))
+		})
+
+		It("should not fail when the expiry is valid", func() {
+			configuration.Annotations = map[string]string{terraformv1alphav1.ExpiresAtAnnotation: "2030-01-02T15:04:05Z"}
+
+			Expect(v.ValidateCreate(ctx, configuration)).To(Succeed())
+		})
+	})
+
+	When("we have a configuration with drift remediation", func() {
+		var configuration *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
+			v = &validator{cc: cc, versioning: true}
+
+			Expect(cc.Create(ctx, fixtures.NewValidAWSReadyProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name)))).To(Succeed())
+			configuration = fixtures.NewValidBucketConfiguration(namespace, name)
+			configuration.Spec.DriftRemediation = terraformv1alphav1.DriftRemediationAuto
+			configuration.Spec.EnableDriftDetection = true
+		})
+
+		It("should fail when drift detection is not enabled", func() {
+			configuration.Spec.EnableDriftDetection = false
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.driftRemediation requires spec.enableDriftDetection to be enabled"))
+		})
+
+		It("should fail when the configuration is plan only", func() {
+			configuration.Spec.PlanOnly = true
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.driftRemediation cannot be used with spec.planOnly"))
+		})
+
+		It("should not fail when drift detection is enabled", func() {
+			Expect(v.ValidateCreate(ctx, configuration)).To(Succeed())
+		})
+	})
+
+	When("we have a configuration with a drift schedule", func() {
+		var configuration *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
+			v = &validator{cc: cc, versioning: true}
+
+			Expect(cc.Create(ctx, fixtures.NewValidAWSReadyProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name)))).To(Succeed())
+			configuration = fixtures.NewValidBucketConfiguration(namespace, name)
+			configuration.Spec.EnableDriftDetection = true
+			configuration.Spec.DriftSchedule = &terraformv1alphav1.DriftSchedule{
+				Interval: &metav1.Duration{Duration: 168 * time.Hour},
+			}
+		})
+
+		It("should fail when the interval is not positive", func() {
+			configuration.Spec.DriftSchedule.Interval.Duration = 0
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.driftSchedule is invalid, interval must be greater than zero"))
+		})
+
+		It("should fail when the schedule is invalid", func() {
+			configuration.Spec.DriftSchedule = &terraformv1alphav1.DriftSchedule{Schedule: "every hour"}
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`spec.driftSchedule is invalid, expected 5 fields in cron expression "every hour", found 2`))
+		})
+
+		It("should not fail when the schedule is valid", func() {
+			Expect(v.ValidateCreate(ctx, configuration)).To(Succeed())
+		})
+	})
+
+	When("we are importing existing resources", func() {
+		var configuration *terraformv1alphav1.Configuration
+
+		BeforeEach(func() {
+			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
+			v = &validator{cc: cc, versioning: true}
+
+			Expect(cc.Create(ctx, fixtures.NewValidAWSReadyProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name)))).To(Succeed())
+			configuration = fixtures.NewValidBucketConfiguration(namespace, name)
+		})
+
+		It("should fail when an import has no id", func() {
+			configuration.Spec.Imports = []terraformv1alphav1.Import{{Address: "aws_s3_bucket.this"}}
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.imports[0].id is empty"))
+		})
+
+		It("should fail when an address is not a valid terraform address", func() {
+			configuration.Spec.Imports = []terraformv1alphav1.Import{
+				{Address: "aws_s3_bucket.this\n}\nresource \"null_resource\" \"x\" {", ID: "bucket"},
+			}
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`spec.imports[0].address: "aws_s3_bucket.this\n}\nresource \"null_resource\" \"x\" {" is not a valid terraform address`))
+		})
+
+		It("should fail when an address is imported twice", func() {
+			configuration.Spec.Imports = []terraformv1alphav1.Import{
+				{Address: "aws_s3_bucket.this", ID: "one"},
+				{Address: "aws_s3_bucket.this", ID: "two"},
+			}
+
+			err := v.ValidateCreate(ctx, configuration)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.imports[1].address: aws_s3_bucket.this is imported more than once"))
+		})
+
+		It("should not fail when the imports are valid", func() {
+			configuration.Spec.Imports = []terraformv1alphav1.Import{{Address: "aws_s3_bucket.this", ID: "bucket"}}
+
+			Expect(v.ValidateCreate(ctx, configuration)).To(Succeed())
+		})
+	})
+
 	When("updating an existing configuration", func() {
 		BeforeEach(func() {
 			cc = fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(fixtures.NewNamespace("default")).Build()
@@ -147,6 +494,26 @@ var _ = Describe("Configuration Validation", func() {
 				})
 			})
 		})
+
+		When("changing the workspace of the configuration", func() {
+			It("should deny the change", func() {
+				before := fixtures.NewValidBucketConfiguration(namespace, "test")
+				after := before.DeepCopy()
+				after.Spec.Workspace = "blue"
+
+				err := v.ValidateUpdate(ctx, before, after)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("spec.workspace cannot be changed"))
+			})
+
+			It("should permit setting the default workspace explicitly", func() {
+				before := fixtures.NewValidBucketConfiguration(namespace, "test")
+				after := before.DeepCopy()
+				after.Spec.Workspace = terraformv1alphav1.DefaultWorkspace
+
+				Expect(v.ValidateUpdate(ctx, before, after)).To(Succeed())
+			})
+		})
 	})
 
 	When("creating a configuration", func() {
@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 
@@ -31,6 +32,7 @@ import (
 
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 	"github.com/appvia/terraform-controller/pkg/utils"
+	"github.com/appvia/terraform-controller/pkg/utils/schedule"
 )
 
 type validator struct {
@@ -91,6 +93,92 @@ func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.O
 	if err := validateModuleConstraint(o); err != nil {
 		return err
 	}
+	if err := validateApplyWindowConstraint(o); err != nil {
+		return err
+	}
+	if err := validateDriftScheduleConstraint(o); err != nil {
+		return err
+	}
+	if err := validateNotificationConstraints(o); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateApplyWindowConstraint ensures the schedule and time zone of the apply window are valid
+func validateApplyWindowConstraint(policy *terraformv1alphav1.Policy) error {
+	switch {
+	case policy.Spec.Constraints == nil, policy.Spec.Constraints.ApplyWindow == nil:
+		return nil
+	}
+	window := policy.Spec.Constraints.ApplyWindow
+
+	if _, err := schedule.NewWindow(window.Schedule, window.Duration.Duration, window.TimeZone); err != nil {
+		return fmt.Errorf("spec.constraints.applyWindow is invalid, %v", err)
+	}
+
+	return nil
+}
+
+// validateDriftScheduleConstraint ensures the schedule or interval of the drift schedule are valid
+func validateDriftScheduleConstraint(policy *terraformv1alphav1.Policy) error {
+	switch {
+	case policy.Spec.Constraints == nil, policy.Spec.Constraints.DriftSchedule == nil:
+		return nil
+	}
+	drift := policy.Spec.Constraints.DriftSchedule
+
+	if _, err := schedule.NewRecurring(drift.Schedule, drift.GetInterval(), drift.TimeZone); err != nil {
+		return fmt.Errorf("spec.constraints.driftSchedule is invalid, %v", err)
+	}
+
+	return nil
+}
+
+// validateNotificationConstraints ensures the notification sinks are valid
+func validateNotificationConstraints(policy *terraformv1alphav1.Policy) error {
+	if policy.Spec.Constraints == nil {
+		return nil
+	}
+
+	names := make(map[string]bool)
+
+	for i, sink := range policy.Spec.Constraints.Notifications {
+		switch {
+		case sink.Name == "":
+			return fmt.Errorf("spec.constraints.notifications[%d].name cannot be empty", i)
+		case names[sink.Name]:
+			return fmt.Errorf("spec.constraints.notifications[%d].name %q is duplicated", i, sink.Name)
+		case sink.URL == "" && sink.SecretRef == nil:
+			return fmt.Errorf("spec.constraints.notifications[%d] must have a url or secretRef", i)
+		case sink.URL != "" && sink.SecretRef != nil:
+			return fmt.Errorf("spec.constraints.notifications[%d] cannot have both a url and secretRef", i)
+		case sink.SecretRef != nil && sink.SecretRef.Name == "":
+			return fmt.Errorf("spec.constraints.notifications[%d].secretRef.name cannot be empty", i)
+		}
+		names[sink.Name] = true
+
+		if sink.URL != "" {
+			if u, err := url.Parse(sink.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("spec.constraints.notifications[%d].url must be a valid http or https url", i)
+			}
+		}
+
+		if sink.Selector != nil {
+			if sink.Selector.Namespace != nil {
+				if _, err := metav1.LabelSelectorAsSelector(sink.Selector.Namespace); err != nil {
+					return fmt.Errorf("spec.constraints.notifications[%d].selector.namespace is invalid, %v", i, err)
+				}
+			}
+
+			if sink.Selector.Resource != nil {
+				if _, err := metav1.LabelSelectorAsSelector(sink.Selector.Resource); err != nil {
+					return fmt.Errorf("spec.constraints.notifications[%d].selector.resource is invalid, %v", i, err)
+				}
+			}
+		}
+	}
 
 	return nil
 }
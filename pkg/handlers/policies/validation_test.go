@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -107,6 +108,110 @@ var _ = Describe("Module Constraints", func() {
 			Expect(err.Error()).To(ContainSubstring("spec.constraints.modules.allowed[0] is invalid"))
 		})
 	})
+
+	When("creating a policy with an apply window", func() {
+		BeforeEach(func() {
+			policy.Spec.Constraints.ApplyWindow = &terraformv1alphav1.ApplyWindowConstraint{
+				ApplyWindow: terraformv1alphav1.ApplyWindow{
+					Schedule: "0 9 * * mon-thu",
+					Duration: metav1.Duration{Duration: 8 * time.Hour},
+					TimeZone: "Europe/London",
+				},
+			}
+		})
+
+		It("should fail on an invalid schedule", func() {
+			policy.Spec.Constraints.ApplyWindow.Schedule = "0 25 * * *"
+			err = v.ValidateCreate(context.TODO(), policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.constraints.applyWindow is invalid, value 25 in hour field must be between 0 and 23"))
+		})
+
+		It("should fail on an invalid time zone", func() {
+			policy.Spec.Constraints.ApplyWindow.TimeZone = "Europe/Nowhere"
+			err = v.ValidateCreate(context.TODO(), policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.constraints.applyWindow is invalid, invalid time zone \"Europe/Nowhere\""))
+		})
+
+		It("should not fail on a valid window", func() {
+			Expect(v.ValidateCreate(context.TODO(), policy)).To(Succeed())
+		})
+	})
+
+	When("creating a policy with a drift schedule", func() {
+		BeforeEach(func() {
+			policy.Spec.Constraints.DriftSchedule = &terraformv1alphav1.DriftScheduleConstraint{
+				DriftSchedule: terraformv1alphav1.DriftSchedule{Schedule: "0 * * * *"},
+			}
+		})
+
+		It("should fail on an invalid schedule", func() {
+			policy.Spec.Constraints.DriftSchedule.Schedule = "0 25 * * *"
+			err = v.ValidateCreate(context.TODO(), policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.constraints.driftSchedule is invalid, value 25 in hour field must be between 0 and 23"))
+		})
+
+		It("should fail when both a schedule and interval are defined", func() {
+			policy.Spec.Constraints.DriftSchedule.Interval = &metav1.Duration{Duration: time.Hour}
+			err = v.ValidateCreate(context.TODO(), policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.constraints.driftSchedule is invalid, schedule and interval are mutually exclusive"))
+		})
+
+		It("should not fail on a valid schedule", func() {
+			Expect(v.ValidateCreate(context.TODO(), policy)).To(Succeed())
+		})
+	})
+
+	When("creating a policy with notification sinks", func() {
+		BeforeEach(func() {
+			policy.Spec.Constraints.Notifications = []terraformv1alphav1.NotificationConstraint{
+				{Name: "ops", URL: "https://hooks.example.com/ops"},
+				{Name: "slack", Format: terraformv1alphav1.NotificationFormatSlack, SecretRef: &v1.SecretReference{Name: "slack"}},
+			}
+		})
+
+		It("should fail when the sink has no name", func() {
+			policy.Spec.Constraints.Notifications[0].Name = ""
+			err = v.ValidateCreate(context.TODO(), policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.constraints.notifications[0].name cannot be empty"))
+		})
+
+		It("should fail when the sink names are duplicated", func() {
+			policy.Spec.Constraints.Notifications[1].Name = "ops"
+			err = v.ValidateCreate(context.TODO(), policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`spec.constraints.notifications[1].name "ops" is duplicated`))
+		})
+
+		It("should fail when the sink has no url or secret", func() {
+			policy.Spec.Constraints.Notifications[0].URL = ""
+			err = v.ValidateCreate(context.TODO(), policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.constraints.notifications[0] must have a url or secretRef"))
+		})
+
+		It("should fail when the sink has both a url and secret", func() {
+			policy.Spec.Constraints.Notifications[1].URL = "https://hooks.example.com/slack"
+			err = v.ValidateCreate(context.TODO(), policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.constraints.notifications[1] cannot have both a url and secretRef"))
+		})
+
+		It("should fail on an invalid url", func() {
+			policy.Spec.Constraints.Notifications[0].URL = "ftp://hooks.example.com"
+			err = v.ValidateCreate(context.TODO(), policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("spec.constraints.notifications[0].url must be a valid http or https url"))
+		})
+
+		It("should not fail on valid sinks", func() {
+			Expect(v.ValidateCreate(context.TODO(), policy)).To(Succeed())
+		})
+	})
 })
 
 var _ = Describe("Policy Validation", func() {
@@ -19,6 +19,7 @@ package providers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -89,5 +90,20 @@ func (v *validator) Validate(ctx context.Context, provider *terraformv1alphav1.P
 
 	}
 
+	if backend := provider.Spec.Backend; backend != nil {
+		switch {
+		case !terraformv1alphav1.IsSupportedBackendType(backend.Type):
+			return fmt.Errorf("spec.backend.type: %s is not supported", backend.Type)
+		case backend.Configuration != nil && len(backend.Configuration.Raw) > 0 && !json.Valid(backend.Configuration.Raw):
+			return errors.New("spec.backend.configuration: must be valid json")
+		case backend.SecretRef == nil:
+			break
+		case backend.SecretRef.Name == "":
+			return errors.New("spec.backend.secretRef.name: name is required")
+		case backend.SecretRef.Namespace != "" && backend.SecretRef.Namespace != v.jobNamespace:
+			return errors.New("spec.backend.secretRef.namespace: must be in same namespace as the controller")
+		}
+	}
+
 	return nil
 }
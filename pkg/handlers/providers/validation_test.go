@@ -23,10 +23,13 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 	"github.com/appvia/terraform-controller/pkg/schema"
 	"github.com/appvia/terraform-controller/test/fixtures"
 )
@@ -163,4 +166,41 @@ var _ = Describe("Provider Validation", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	When("creating a provider with a backend", func() {
+		It("should throw error when the backend type is not supported", func() {
+			policy := fixtures.NewValidAWSProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name))
+			policy.Spec.Backend = &terraformv1alphav1.Backend{Type: "invalid"}
+			msg := "spec.backend.type: invalid is not supported"
+
+			err := v.ValidateCreate(ctx, policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(msg))
+		})
+
+		It("should throw error when the backend secret is not in the controller namespace", func() {
+			policy := fixtures.NewValidAWSProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name))
+			policy.Spec.Backend = &terraformv1alphav1.Backend{
+				Type:      terraformv1alphav1.S3BackendType,
+				SecretRef: &v1.SecretReference{Name: "backend", Namespace: "other"},
+			}
+			msg := "spec.backend.secretRef.namespace: must be in same namespace as the controller"
+
+			err := v.ValidateCreate(ctx, policy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(msg))
+		})
+
+		It("should not throw an error when the backend is valid", func() {
+			policy := fixtures.NewValidAWSProvider(name, fixtures.NewValidAWSProviderSecret(namespace, name))
+			policy.Spec.Backend = &terraformv1alphav1.Backend{
+				Type:          terraformv1alphav1.S3BackendType,
+				Configuration: &runtime.RawExtension{Raw: []byte(`{"bucket": "state"}`)},
+				SecretRef:     &v1.SecretReference{Name: "backend", Namespace: namespace},
+			}
+
+			err := v.ValidateCreate(ctx, policy)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
 })
@@ -1,6 +1,7 @@
 // Code generated by go-bindata. (@generated) DO NOT EDIT.
 
 //Package register generated by go-bindata.// sources:
+// charts/terraform-controller/crds/terraform.appvia.io_configurationrevisions.yaml
 // charts/terraform-controller/crds/terraform.appvia.io_configurations.yaml
 // charts/terraform-controller/crds/terraform.appvia.io_policies.yaml
 // charts/terraform-controller/crds/terraform.appvia.io_providers.yaml
@@ -58,11 +59,414 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
+var _chartsTerraformControllerCrdsTerraformAppviaIo_configurationrevisionsYaml = []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.9.2
+  creationTimestamp: null
+  name: configurationrevisions.terraform.appvia.io
+spec:
+  group: terraform.appvia.io
+  names:
+    categories:
+      - terraform
+    kind: ConfigurationRevision
+    listKind: ConfigurationRevisionList
+    plural: configurationrevisions
+    singular: configurationrevision
+  scope: Namespaced
+  versions:
+    - additionalPrinterColumns:
+        - jsonPath: .metadata.labels.terraform\.appvia\.io/configuration
+          name: Configuration
+          type: string
+        - jsonPath: .spec.generation
+          name: Generation
+          type: integer
+        - jsonPath: .status.plan.summary
+          name: Plan
+          type: string
+        - jsonPath: .status.approvedBy
+          name: Approved By
+          type: string
+        - jsonPath: .status.outcome
+          name: Outcome
+          type: string
+        - jsonPath: .metadata.creationTimestamp
+          name: Age
+          type: date
+      name: v1alpha1
+      schema:
+        openAPIV3Schema:
+          description: ConfigurationRevision is a historic record of a generation of a configuration
+          properties:
+            apiVersion:
+              description: 'APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources'
+              type: string
+            kind:
+              description: 'Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds'
+              type: string
+            metadata:
+              type: object
+            spec:
+              description: ConfigurationRevisionSpec is a snapshot of the configuration at a given generation
+              properties:
+                configuration:
+                  description: Configuration is a copy of the configuration specification at the generation, used to rollback the configuration to this revision
+                  properties:
+                    applyWindow:
+                      description: ApplyWindow restricts when an approved terraform apply is permitted to run. Outside of the window the apply is held until the window next opens, plans are not affected. This takes precedence over any window defined by a policy.
+                      properties:
+                        duration:
+                          description: Duration is how long the window remains open once opened, i.e. 8h
+                          type: string
+                        schedule:
+                          description: Schedule is a cron expression (minute hour day-of-month month day-of-week) defining when the window opens, i.e. "0 9 * * mon-thu" opens the window at 09:00 Monday to Thursday
+                          type: string
+                        timeZone:
+                          description: TimeZone is the IANA time zone the schedule is evaluated in i.e. Europe/London, defaults to UTC
+                          type: string
+                      required:
+                        - duration
+                        - schedule
+                      type: object
+                    auth:
+                      description: Auth is used to configure any options required when the source of the terraform module is private or requires credentials to retrieve. This could be SSH keys or git user/pass or AWS credentials for an s3 bucket.
+                      properties:
+                        name:
+                          description: name is unique within a namespace to reference a secret resource.
+                          type: string
+                        namespace:
+                          description: namespace defines the space within which the secret name must be unique.
+                          type: string
+                      type: object
+                      x-kubernetes-map-type: atomic
+                    deletionPolicy:
+                      description: DeletionPolicy defines what happens to the resources when the configuration is deleted, i.e. Delete, Orphan or RequireApproval, defaulting to Delete. RequireApproval produces a terraform plan -destroy and waits for the terraform.appvia.io/destroy-approved annotation before the resources are destroyed.
+                      enum:
+                        - Delete
+                        - Orphan
+                        - RequireApproval
+                      type: string
+                    dependsOn:
+                      description: DependsOn is a collection of configuration names within the same namespace which must be ready before this configuration is planned. The configuration will also not be destroyed while other configurations still depend on it.
+                      items:
+                        type: string
+                      type: array
+                    destroyProtection:
+                      description: DestroyProtection provides the ability to require a manual approval when the terraform plan destroys or replaces resources, regardless of auto approval being enabled.
+                      properties:
+                        allResources:
+                          description: AllResources indicates any resource being destroyed or replaced requires approval
+                          type: boolean
+                        resourceTypes:
+                          description: ResourceTypes is a collection of terraform resource types i.e. aws_db_instance, which require approval before being destroyed or replaced
+                          items:
+                            type: string
+                          type: array
+                      type: object
+                    driftRemediation:
+                      description: DriftRemediation defines the action taken when drift detection finds changes terraform would make, i.e. none, auto or approval, defaulting to none. In auto the current generation is applied, subject to any apply window and policy. In approval the terraform apply annotation is set to false, and the apply waits for an approval.
+                      enum:
+                        - none
+                        - auto
+                        - approval
+                      type: string
+                    driftSchedule:
+                      description: DriftSchedule defines how often drift detection is run on the configuration, overriding any schedule defined by a policy and the controller default. This is only used when drift detection is enabled.
+                      properties:
+                        interval:
+                          description: Interval is the time between drift checks i.e. 1h or 168h, this cannot be used with the schedule
+                          type: string
+                        schedule:
+                          description: Schedule is a cron expression (minute hour day-of-month month day-of-week) defining when drift is checked, i.e. "0 * * * *" checks every hour
+                          type: string
+                        timeZone:
+                          description: TimeZone is the IANA time zone the schedule is evaluated in i.e. Europe/London, defaults to UTC
+                          type: string
+                      type: object
+                    enableAutoApproval:
+                      description: EnableAutoApproval when enabled indicates the configuration does not need to be manually approved. On a change to the configuration, the controller will automatically approve the configuration. Note it still needs to adhere to any checks or policies.
+                      type: boolean
+                    enableDriftDetection:
+                      description: EnableDriftDetection when enabled run periodic reconciliation configurations looking for any drift between the expected and current state. If any drift is detected the status is changed and a kubernetes event raised.
+                      type: boolean
+                    importState:
+                      description: ImportState is a reference to a secret in the configuration namespace containing an existing terraform state under the terraform.tfstate key. The state is copied into the configuration state before the first plan, and is ignored once a state exists. Any resources created by the plan while this is set require an approval.
+                      properties:
+                        name:
+                          description: name is unique within a namespace to reference a secret resource.
+                          type: string
+                        namespace:
+                          description: namespace defines the space within which the secret name must be unique.
+                          type: string
+                      type: object
+                      x-kubernetes-map-type: atomic
+                    imports:
+                      description: Imports is a collection of existing resources which should be imported into the state of the configuration. These are rendered as terraform import blocks (requires terraform 1.5 or above), and the configuration will not be applied if the plan attempts to create any of the addresses instead.
+                      items:
+                        description: Import defines an existing resource which should be imported into the terraform state
+                        properties:
+                          address:
+                            description: Address is the terraform resource address the resource is imported into i.e. aws_s3_bucket.this or module.vpc.aws_vpc.this
+                            type: string
+                          id:
+                            description: ID is the provider specific identifier of the existing resource
+                            type: string
+                        required:
+                          - address
+                          - id
+                        type: object
+                      type: array
+                    module:
+                      description: Module is the URL to the source of the terraform module. The format of the URL is a direct implementation of terraform's module reference. Please see the following repository for more details https://github.com/hashicorp/go-getter
+                      type: string
+                    moduleUpdatePolicy:
+                      description: ModuleUpdatePolicy indicates the controller should report newer versions of the module which have been released, i.e. none, patch or minor. Note the module must reference a semantic version tag or registry version.
+                      enum:
+                        - none
+                        - patch
+                        - minor
+                      type: string
+                    operations:
+                      description: Operations are one-shot replace and target operations included in the next terraform plan. The apply always requires an approval, and the operations are removed once the apply has completed successfully.
+                      properties:
+                        replace:
+                          description: Replace is a collection of resource addresses terraform should replace, regardless of any changes, i.e. aws_instance.this (terraform plan -replace)
+                          items:
+                            type: string
+                          type: array
+                        target:
+                          description: Target is a collection of resource or module addresses terraform should limit the plan to, i.e. module.db (terraform plan -target)
+                          items:
+                            type: string
+                          type: array
+                      type: object
+                    planOnly:
+                      description: PlanOnly when enabled runs the configuration in a read-only mode. Terraform plans are produced on changes to the configuration and drift, but the plan is never applied, regardless of the approval. The status reports if applying the plan would result in any changes.
+                      type: boolean
+                    providerRef:
+                      description: ProviderRef is the reference to the provider which should be used to execute this configuration.
+                      properties:
+                        name:
+                          description: Name is the name of the provider which contains the credentials to use for this configuration.
+                          type: string
+                        namespace:
+                          description: Namespace is the namespace of the provider itself.
+                          type: string
+                      required:
+                        - name
+                      type: object
+                    retry:
+                      description: Retry defines how failed terraform plan and apply jobs are retried for the same generation, defaulting to the controller policy. Failures caused by misconfigured provider credentials are never retried.
+                      properties:
+                        backoff:
+                          description: Backoff is the delay before the first retry, which is doubled on each subsequent attempt, i.e. 30s
+                          type: string
+                        maxAttempts:
+                          description: MaxAttempts is the maximum number of attempts at running a stage for a generation, including the first. A value of one disables retries
+                          minimum: 1
+                          type: integer
+                      type: object
+                    terraformVersion:
+                      description: TerraformVersion provides the ability to override the default terraform version. Before changing this field its best to consult with platform administrator. As the value of this field is used to change the tag of the terraform container image.
+                      type: string
+                    ttl:
+                      description: TTL is the time to live of the configuration from the time it was created. Once expired the configuration is deleted, destroying the resources, i.e. ephemeral preview environments. The expiry can be extended via the terraform.appvia.io/expires-at annotation.
+                      type: string
+                    valueFrom:
+                      description: ValueFromSource is a collection of value from sources, where the source of the value is is taken from a secret
+                      items:
+                        description: ValueFromSource defines a value which is taken from a secret or the output of another configuration
+                        properties:
+                          configuration:
+                            description: Configuration is the name of a configuration in the same namespace, the key is the name of the terraform output. The configuration will wait for the referenced configuration to be ready before running.
+                            type: string
+                          key:
+                            description: Key is the key in the secret which we should used for the value
+                            type: string
+                          optional:
+                            description: Optional indicates the secret can be optional, i.e if the secret does not exist, or the key is not contained in the secret, we ignore the error
+                            type: boolean
+                          secret:
+                            description: Secret is the name of the secret in the configuration namespace
+                            type: string
+                        required:
+                          - key
+                        type: object
+                      type: array
+                    variables:
+                      description: Variables provides the inputs for the terraform module itself. These are passed to the terraform executor and used to execute the plan, apply and destroy phases.
+                      type: object
+                      x-kubernetes-preserve-unknown-fields: true
+                    workspace:
+                      description: Workspace is the name of the terraform workspace the configuration is executed within, defaulting to the default workspace. The workspace forms part of the state location and permits multiple copies of the same stack, or existing workspace based stacks, to be managed. The workspace cannot be changed once set.
+                      maxLength: 32
+                      pattern: ^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+                      type: string
+                    writeConnectionSecretToRef:
+                      description: WriteConnectionSecretToRef is the name for a secret. On execution of the terraform module any module outputs are written to this secret. The outputs are automatically uppercased and ready to be consumed as environment variables. WriteConnectionSecretRef is the secret where the terraform outputs will be written.
+                      properties:
+                        keys:
+                          description: Keys is a collection of name used to filter the terraform output. By default all keys from the output of the terraform state are written to the connection secret. Here we can define exactly which keys we want from that output.
+                          items:
+                            type: string
+                          type: array
+                        name:
+                          description: Name is the of the secret where you want to the terraform output to be written. The terraform outputs will be written to the secret as a key value pair. All are uppercased can read to be consumed by the workload.
+                          type: string
+                      required:
+                        - name
+                      type: object
+                  required:
+                    - module
+                    - providerRef
+                  type: object
+                generation:
+                  description: Generation is the generation of the configuration the revision was recorded for
+                  format: int64
+                  type: integer
+                module:
+                  description: Module is the source of the terraform module used by the revision
+                  type: string
+                variablesHash:
+                  description: VariablesHash is a hash of the variables used by the revision
+                  type: string
+              required:
+                - configuration
+                - generation
+              type: object
+            status:
+              description: ConfigurationRevisionStatus is the recorded outcome of the revision
+              properties:
+                approvedBy:
+                  description: ApprovedBy is the identity who approved the terraform apply, or 'auto' when the configuration has auto approval enabled
+                  type: string
+                completionTime:
+                  description: CompletionTime is the time the revision was either applied or failed
+                  format: date-time
+                  type: string
+                costs:
+                  description: Costs is the predicted cost of the revision
+                  properties:
+                    enabled:
+                      description: Enabled indicates if the cost integration was enabled when this configuration was last executed.
+                      type: boolean
+                    hourly:
+                      description: Hourly is the hourly estimated cost of the configuration
+                      type: string
+                    monthly:
+                      description: Monthly is the monthly estimated cost of the configuration
+                      type: string
+                  type: object
+                module:
+                  description: Module is the revision the terraform module resolved to, used to rollback the configuration to the same version of the module
+                  properties:
+                    revision:
+                      description: Revision is the immutable revision the module resolved to, i.e. a commit sha or registry version
+                      type: string
+                    source:
+                      description: Source is the source of the module pinned to the revision
+                      type: string
+                    update:
+                      description: Update is a newer version of the module permitted by the module update policy
+                      type: string
+                  required:
+                    - revision
+                    - source
+                  type: object
+                outcome:
+                  description: Outcome is the current outcome of the revision
+                  type: string
+                plan:
+                  description: Plan is a summary of the terraform plan for the revision
+                  properties:
+                    add:
+                      description: Add is the number of resources which will be created
+                      type: integer
+                    change:
+                      description: Change is the number of resources which will be updated in-place
+                      type: integer
+                    destroy:
+                      description: Destroy is the number of resources which will be destroyed
+                      type: integer
+                    generation:
+                      description: Generation is the generation of the configuration the plan was produced for
+                      format: int64
+                      type: integer
+                    hasChanges:
+                      description: HasChanges indicates applying the plan would result in changes to the resources
+                      type: boolean
+                    import:
+                      description: Import is the number of existing resources which will be imported
+                      type: integer
+                    replace:
+                      description: Replace is the number of resources which will be destroyed and recreated
+                      type: integer
+                    resources:
+                      description: Resources is a list of the resources affected by the plan. Note the list is capped in size, with truncated indicating resources have been omitted.
+                      items:
+                        description: PlanResource is a resource affected by the terraform plan
+                        properties:
+                          action:
+                            description: Action is the action terraform will perform on the resource i.e. create, update, delete, replace or import
+                            type: string
+                          address:
+                            description: Address is the terraform address of the resource
+                            type: string
+                        required:
+                          - action
+                          - address
+                        type: object
+                      type: array
+                    summary:
+                      description: Summary is a human readable summary of the plan i.e. 1 to add, 3 to destroy
+                      type: string
+                    truncated:
+                      description: Truncated indicates the list of resources was capped
+                      type: boolean
+                  required:
+                    - add
+                    - change
+                    - destroy
+                    - replace
+                  type: object
+                policy:
+                  description: Policy is the result of the security policy checks i.e. Passed or Failed
+                  type: string
+                startTime:
+                  description: StartTime is the time the controller started to reconcile the revision
+                  format: date-time
+                  type: string
+              type: object
+          type: object
+      served: true
+      storage: true
+      subresources: {}
+  preserveUnknownFields: false
+`)
+
+func chartsTerraformControllerCrdsTerraformAppviaIo_configurationrevisionsYamlBytes() ([]byte, error) {
+	return _chartsTerraformControllerCrdsTerraformAppviaIo_configurationrevisionsYaml, nil
+}
+
+func chartsTerraformControllerCrdsTerraformAppviaIo_configurationrevisionsYaml() (*asset, error) {
+	bytes, err := chartsTerraformControllerCrdsTerraformAppviaIo_configurationrevisionsYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "charts/terraform-controller/crds/terraform.appvia.io_configurationrevisions.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _chartsTerraformControllerCrdsTerraformAppviaIo_configurationsYaml = []byte(`apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
 metadata:
   annotations:
-    controller-gen.kubebuilder.io/version: v0.9.0
+    controller-gen.kubebuilder.io/version: v0.9.2
   creationTimestamp: null
   name: configurations.terraform.appvia.io
 spec:
@@ -81,6 +485,9 @@ spec:
         - jsonPath: .spec.writeConnectionSecretToRef.name
           name: Secret
           type: string
+        - jsonPath: .status.plan.summary
+          name: Plan
+          type: string
         - jsonPath: .status.costs.monthly
           name: Estimated
           type: string
@@ -106,6 +513,22 @@ spec:
             spec:
               description: ConfigurationSpec defines the desired state of a terraform
               properties:
+                applyWindow:
+                  description: ApplyWindow restricts when an approved terraform apply is permitted to run. Outside of the window the apply is held until the window next opens, plans are not affected. This takes precedence over any window defined by a policy.
+                  properties:
+                    duration:
+                      description: Duration is how long the window remains open once opened, i.e. 8h
+                      type: string
+                    schedule:
+                      description: Schedule is a cron expression (minute hour day-of-month month day-of-week) defining when the window opens, i.e. "0 9 * * mon-thu" opens the window at 09:00 Monday to Thursday
+                      type: string
+                    timeZone:
+                      description: TimeZone is the IANA time zone the schedule is evaluated in i.e. Europe/London, defaults to UTC
+                      type: string
+                  required:
+                    - duration
+                    - schedule
+                  type: object
                 auth:
                   description: Auth is used to configure any options required when the source of the terraform module is private or requires credentials to retrieve. This could be SSH keys or git user/pass or AWS credentials for an s3 bucket.
                   properties:
@@ -116,15 +539,111 @@ spec:
                       description: namespace defines the space within which the secret name must be unique.
                       type: string
                   type: object
+                  x-kubernetes-map-type: atomic
+                deletionPolicy:
+                  description: DeletionPolicy defines what happens to the resources when the configuration is deleted, i.e. Delete, Orphan or RequireApproval, defaulting to Delete. RequireApproval produces a terraform plan -destroy and waits for the terraform.appvia.io/destroy-approved annotation before the resources are destroyed.
+                  enum:
+                    - Delete
+                    - Orphan
+                    - RequireApproval
+                  type: string
+                dependsOn:
+                  description: DependsOn is a collection of configuration names within the same namespace which must be ready before this configuration is planned. The configuration will also not be destroyed while other configurations still depend on it.
+                  items:
+                    type: string
+                  type: array
+                destroyProtection:
+                  description: DestroyProtection provides the ability to require a manual approval when the terraform plan destroys or replaces resources, regardless of auto approval being enabled.
+                  properties:
+                    allResources:
+                      description: AllResources indicates any resource being destroyed or replaced requires approval
+                      type: boolean
+                    resourceTypes:
+                      description: ResourceTypes is a collection of terraform resource types i.e. aws_db_instance, which require approval before being destroyed or replaced
+                      items:
+                        type: string
+                      type: array
+                  type: object
+                driftRemediation:
+                  description: DriftRemediation defines the action taken when drift detection finds changes terraform would make, i.e. none, auto or approval, defaulting to none. In auto the current generation is applied, subject to any apply window and policy. In approval the terraform apply annotation is set to false, and the apply waits for an approval.
+                  enum:
+                    - none
+                    - auto
+                    - approval
+                  type: string
+                driftSchedule:
+                  description: DriftSchedule defines how often drift detection is run on the configuration, overriding any schedule defined by a policy and the controller default. This is only used when drift detection is enabled.
+                  properties:
+                    interval:
+                      description: Interval is the time between drift checks i.e. 1h or 168h, this cannot be used with the schedule
+                      type: string
+                    schedule:
+                      description: Schedule is a cron expression (minute hour day-of-month month day-of-week) defining when drift is checked, i.e. "0 * * * *" checks every hour
+                      type: string
+                    timeZone:
+                      description: TimeZone is the IANA time zone the schedule is evaluated in i.e. Europe/London, defaults to UTC
+                      type: string
+                  type: object
                 enableAutoApproval:
                   description: EnableAutoApproval when enabled indicates the configuration does not need to be manually approved. On a change to the configuration, the controller will automatically approve the configuration. Note it still needs to adhere to any checks or policies.
                   type: boolean
                 enableDriftDetection:
                   description: EnableDriftDetection when enabled run periodic reconciliation configurations looking for any drift between the expected and current state. If any drift is detected the status is changed and a kubernetes event raised.
                   type: boolean
+                importState:
+                  description: ImportState is a reference to a secret in the configuration namespace containing an existing terraform state under the terraform.tfstate key. The state is copied into the configuration state before the first plan, and is ignored once a state exists. Any resources created by the plan while this is set require an approval.
+                  properties:
+                    name:
+                      description: name is unique within a namespace to reference a secret resource.
+                      type: string
+                    namespace:
+                      description: namespace defines the space within which the secret name must be unique.
+                      type: string
+                  type: object
+                  x-kubernetes-map-type: atomic
+                imports:
+                  description: Imports is a collection of existing resources which should be imported into the state of the configuration. These are rendered as terraform import blocks (requires terraform 1.5 or above), and the configuration will not be applied if the plan attempts to create any of the addresses instead.
+                  items:
+                    description: Import defines an existing resource which should be imported into the terraform state
+                    properties:
+                      address:
+                        description: Address is the terraform resource address the resource is imported into i.e. aws_s3_bucket.this or module.vpc.aws_vpc.this
+                        type: string
+                      id:
+                        description: ID is the provider specific identifier of the existing resource
+                        type: string
+                    required:
+                      - address
+                      - id
+                    type: object
+                  type: array
                 module:
                   description: Module is the URL to the source of the terraform module. The format of the URL is a direct implementation of terraform's module reference. Please see the following repository for more details https://github.com/hashicorp/go-getter
                   type: string
+                moduleUpdatePolicy:
+                  description: ModuleUpdatePolicy indicates the controller should report newer versions of the module which have been released, i.e. none, patch or minor. Note the module must reference a semantic version tag or registry version.
+                  enum:
+                    - none
+                    - patch
+                    - minor
+                  type: string
+                operations:
+                  description: Operations are one-shot replace and target operations included in the next terraform plan. The apply always requires an approval, and the operations are removed once the apply has completed successfully.
+                  properties:
+                    replace:
+                      description: Replace is a collection of resource addresses terraform should replace, regardless of any changes, i.e. aws_instance.this (terraform plan -replace)
+                      items:
+                        type: string
+                      type: array
+                    target:
+                      description: Target is a collection of resource or module addresses terraform should limit the plan to, i.e. module.db (terraform plan -target)
+                      items:
+                        type: string
+                      type: array
+                  type: object
+                planOnly:
+                  description: PlanOnly when enabled runs the configuration in a read-only mode. Terraform plans are produced on changes to the configuration and drift, but the plan is never applied, regardless of the approval. The status reports if applying the plan would result in any changes.
+                  type: boolean
                 providerRef:
                   description: ProviderRef is the reference to the provider which should be used to execute this configuration.
                   properties:
@@ -137,14 +656,31 @@ spec:
                   required:
                     - name
                   type: object
+                retry:
+                  description: Retry defines how failed terraform plan and apply jobs are retried for the same generation, defaulting to the controller policy. Failures caused by misconfigured provider credentials are never retried.
+                  properties:
+                    backoff:
+                      description: Backoff is the delay before the first retry, which is doubled on each subsequent attempt, i.e. 30s
+                      type: string
+                    maxAttempts:
+                      description: MaxAttempts is the maximum number of attempts at running a stage for a generation, including the first. A value of one disables retries
+                      minimum: 1
+                      type: integer
+                  type: object
                 terraformVersion:
                   description: TerraformVersion provides the ability to override the default terraform version. Before changing this field its best to consult with platform administrator. As the value of this field is used to change the tag of the terraform container image.
                   type: string
+                ttl:
+                  description: TTL is the time to live of the configuration from the time it was created. Once expired the configuration is deleted, destroying the resources, i.e. ephemeral preview environments. The expiry can be extended via the terraform.appvia.io/expires-at annotation.
+                  type: string
                 valueFrom:
                   description: ValueFromSource is a collection of value from sources, where the source of the value is is taken from a secret
                   items:
-                    description: ValueFromSource defines a value which is taken from a secret
+                    description: ValueFromSource defines a value which is taken from a secret or the output of another configuration
                     properties:
+                      configuration:
+                        description: Configuration is the name of a configuration in the same namespace, the key is the name of the terraform output. The configuration will wait for the referenced configuration to be ready before running.
+                        type: string
                       key:
                         description: Key is the key in the secret which we should used for the value
                         type: string
@@ -156,13 +692,17 @@ spec:
                         type: string
                     required:
                       - key
-                      - secret
                     type: object
                   type: array
                 variables:
                   description: Variables provides the inputs for the terraform module itself. These are passed to the terraform executor and used to execute the plan, apply and destroy phases.
                   type: object
                   x-kubernetes-preserve-unknown-fields: true
+                workspace:
+                  description: Workspace is the name of the terraform workspace the configuration is executed within, defaulting to the default workspace. The workspace forms part of the state location and permits multiple copies of the same stack, or existing workspace based stacks, to be managed. The workspace cannot be changed once set.
+                  maxLength: 32
+                  pattern: ^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+                  type: string
                 writeConnectionSecretToRef:
                   description: WriteConnectionSecretToRef is the name for a secret. On execution of the terraform module any module outputs are written to this secret. The outputs are automatically uppercased and ready to be consumed as environment variables. WriteConnectionSecretRef is the secret where the terraform outputs will be written.
                   properties:
@@ -250,9 +790,62 @@ spec:
                       description: Monthly is the monthly estimated cost of the configuration
                       type: string
                   type: object
+                drift:
+                  description: Drift is a summary of the last drift detection, the full report is held in a secret in the configuration namespace
+                  properties:
+                    checkedAt:
+                      description: CheckedAt is the time the drift detection was last performed
+                      format: date-time
+                      type: string
+                    configChanges:
+                      description: ConfigChanges is the number of resources which applying the configuration would change
+                      type: integer
+                    refreshOnlyChanges:
+                      description: RefreshOnlyChanges is the number of resources changed outside of terraform which applying the configuration would not change
+                      type: integer
+                    remediation:
+                      description: Remediation is the state of the remediation of the drift i.e. Pending or Applied, only set when spec.driftRemediation is enabled
+                      type: string
+                    reportSecret:
+                      description: ReportSecret is the name of the secret in the configuration namespace containing the full drift report, including the attributes which have changed
+                      type: string
+                    resources:
+                      description: Resources is a list of the drifted resources. Note the list is capped in size, with truncated indicating resources have been omitted.
+                      items:
+                        description: DriftResource is a resource which has drifted from the configuration
+                        properties:
+                          action:
+                            description: Action is the action terraform would perform on the resource i.e. create, update, delete or replace
+                            type: string
+                          address:
+                            description: Address is the terraform address of the resource
+                            type: string
+                          kind:
+                            description: Kind indicates if the drift is a config change, which applying the configuration would correct, or a refresh-only change, which only updates the state
+                            type: string
+                        required:
+                          - action
+                          - address
+                          - kind
+                        type: object
+                      type: array
+                    summary:
+                      description: Summary is a human readable summary of the drift i.e. 1 config change, 2 refresh-only changes
+                      type: string
+                    truncated:
+                      description: Truncated indicates the list of resources was capped
+                      type: boolean
+                  required:
+                    - configChanges
+                    - refreshOnlyChanges
+                  type: object
                 driftTimestamp:
                   description: DriftTimestamp is the timestamp of the last drift detection
                   type: string
+                expiresAt:
+                  description: ExpiresAt is the time the configuration expires and will be deleted
+                  format: date-time
+                  type: string
                 lastReconcile:
                   description: LastReconcile describes the generation and time of the last reconciliation
                   properties:
@@ -277,15 +870,150 @@ spec:
                       format: date-time
                       type: string
                   type: object
+                module:
+                  description: Module is the source of the module resolved to an immutable revision, the revision is used by the plan and apply, with the configuration planned again when it changes
+                  properties:
+                    revision:
+                      description: Revision is the immutable revision the module resolved to, i.e. a commit sha or registry version
+                      type: string
+                    source:
+                      description: Source is the source of the module pinned to the revision
+                      type: string
+                    update:
+                      description: Update is a newer version of the module permitted by the module update policy
+                      type: string
+                  required:
+                    - revision
+                    - source
+                  type: object
+                nextDriftCheck:
+                  description: NextDriftCheck is the time the next drift check on the configuration is scheduled
+                  format: date-time
+                  type: string
+                operations:
+                  description: Operations are the replace and target operations included in the current plan, these are cleared once applied
+                  properties:
+                    replace:
+                      description: Replace is a collection of resource addresses terraform should replace, regardless of any changes, i.e. aws_instance.this (terraform plan -replace)
+                      items:
+                        type: string
+                      type: array
+                    target:
+                      description: Target is a collection of resource or module addresses terraform should limit the plan to, i.e. module.db (terraform plan -target)
+                      items:
+                        type: string
+                      type: array
+                  type: object
+                plan:
+                  description: Plan is a summary of the changes in the last terraform plan
+                  properties:
+                    add:
+                      description: Add is the number of resources which will be created
+                      type: integer
+                    change:
+                      description: Change is the number of resources which will be updated in-place
+                      type: integer
+                    destroy:
+                      description: Destroy is the number of resources which will be destroyed
+                      type: integer
+                    generation:
+                      description: Generation is the generation of the configuration the plan was produced for
+                      format: int64
+                      type: integer
+                    hasChanges:
+                      description: HasChanges indicates applying the plan would result in changes to the resources
+                      type: boolean
+                    import:
+                      description: Import is the number of existing resources which will be imported
+                      type: integer
+                    replace:
+                      description: Replace is the number of resources which will be destroyed and recreated
+                      type: integer
+                    resources:
+                      description: Resources is a list of the resources affected by the plan. Note the list is capped in size, with truncated indicating resources have been omitted.
+                      items:
+                        description: PlanResource is a resource affected by the terraform plan
+                        properties:
+                          action:
+                            description: Action is the action terraform will perform on the resource i.e. create, update, delete, replace or import
+                            type: string
+                          address:
+                            description: Address is the terraform address of the resource
+                            type: string
+                        required:
+                          - action
+                          - address
+                        type: object
+                      type: array
+                    summary:
+                      description: Summary is a human readable summary of the plan i.e. 1 to add, 3 to destroy
+                      type: string
+                    truncated:
+                      description: Truncated indicates the list of resources was capped
+                      type: boolean
+                  required:
+                    - add
+                    - change
+                    - destroy
+                    - replace
+                  type: object
+                planResets:
+                  description: PlanResets is the number of times a saved plan has been discarded as missing or stale, the jobs of the replacement plan are labelled with it so they are never confused with the old
+                  type: integer
                 resourceStatus:
                   description: ResourceStatus indicates the status of the resources and if the resources are insync with the configuration
                   type: string
                 resources:
                   description: Resources is the number of managed cloud resources which are currently under management. This field is taken from the terraform state itself.
                   type: integer
+                retry:
+                  description: Retry is the status of the attempts at running the current stage, when failed jobs are being retried
+                  properties:
+                    attempt:
+                      description: Attempt is the current attempt at running the stage for the generation
+                      type: integer
+                    maxAttempts:
+                      description: MaxAttempts is the maximum number of attempts permitted
+                      type: integer
+                    nextAttemptTime:
+                      description: NextAttemptTime is when the next attempt will be made, if any
+                      format: date-time
+                      type: string
+                    stage:
+                      description: Stage is the stage being retried, i.e. plan or apply
+                      type: string
+                  required:
+                    - attempt
+                    - maxAttempts
+                    - stage
+                  type: object
+                stateLock:
+                  description: StateLock is the holder of the terraform state lock when the last job failed to acquire it, the lock can be force released using the unlock annotation
+                  properties:
+                    created:
+                      description: Created is when the lock was acquired
+                      type: string
+                    failedUnlocks:
+                      description: FailedUnlocks is the number of failed attempts at force releasing the lock, each request to release the lock is run as a new attempt
+                      type: integer
+                    id:
+                      description: ID is the identifier of the lock, used to force release the lock
+                      type: string
+                    operation:
+                      description: Operation is the terraform operation which acquired the lock, i.e. OperationTypeApply
+                      type: string
+                    who:
+                      description: Who is the identity which acquired the lock, i.e. user@hostname
+                      type: string
+                  required:
+                    - id
+                  type: object
                 terraformVersion:
                   description: TerraformVersion is the version of terraform which was last used to run this configuration
                   type: string
+                upstreamChecksum:
+                  description: UpstreamChecksum is a checksum of the outputs taken from other configurations (spec.valueFrom[].configuration) and the resolved module revision when the configuration was last applied
+                  type: string
               type: object
           type: object
       served: true
@@ -314,7 +1042,7 @@ var _chartsTerraformControllerCrdsTerraformAppviaIo_policiesYaml = []byte(`apiVe
 kind: CustomResourceDefinition
 metadata:
   annotations:
-    controller-gen.kubebuilder.io/version: v0.9.0
+    controller-gen.kubebuilder.io/version: v0.9.2
   creationTimestamp: null
   name: policies.terraform.appvia.io
 spec:
@@ -351,6 +1079,88 @@ spec:
                 constraints:
                   description: Constraints provides a series or constraints that must be enforced on the selectored terraform configurations.
                   properties:
+                    applyWindow:
+                      description: ApplyWindow provides the ability to restrict when the selected configurations are permitted to apply, unless the configuration defines its own apply window
+                      properties:
+                        duration:
+                          description: Duration is how long the window remains open once opened, i.e. 8h
+                          type: string
+                        schedule:
+                          description: Schedule is a cron expression (minute hour day-of-month month day-of-week) defining when the window opens, i.e. "0 9 * * mon-thu" opens the window at 09:00 Monday to Thursday
+                          type: string
+                        selector:
+                          description: Selector is the selector on the namespace or labels on the configuration. By leaving this fields empty you can implicitly selecting all configurations.
+                          properties:
+                            namespace:
+                              description: Namespace is used to filter a configuration based on the namespace labels of where it exists
+                              properties:
+                                matchExpressions:
+                                  description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                  items:
+                                    description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                    properties:
+                                      key:
+                                        description: key is the label key that the selector applies to.
+                                        type: string
+                                      operator:
+                                        description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                        type: string
+                                      values:
+                                        description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                        items:
+                                          type: string
+                                        type: array
+                                    required:
+                                      - key
+                                      - operator
+                                    type: object
+                                  type: array
+                                matchLabels:
+                                  additionalProperties:
+                                    type: string
+                                  description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                  type: object
+                              type: object
+                              x-kubernetes-map-type: atomic
+                            resource:
+                              description: Resource provides the ability to filter a configuration based on it's labels
+                              properties:
+                                matchExpressions:
+                                  description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                  items:
+                                    description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                    properties:
+                                      key:
+                                        description: key is the label key that the selector applies to.
+                                        type: string
+                                      operator:
+                                        description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                        type: string
+                                      values:
+                                        description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                        items:
+                                          type: string
+                                        type: array
+                                    required:
+                                      - key
+                                      - operator
+                                    type: object
+                                  type: array
+                                matchLabels:
+                                  additionalProperties:
+                                    type: string
+                                  description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                  type: object
+                              type: object
+                              x-kubernetes-map-type: atomic
+                          type: object
+                        timeZone:
+                          description: TimeZone is the IANA time zone the schedule is evaluated in i.e. Europe/London, defaults to UTC
+                          type: string
+                      required:
+                        - duration
+                        - schedule
+                      type: object
                     checkov:
                       description: Checkov provides the ability to enforce a set of security standards on all configurations. These can be configured to target specific resources based on namespace and resource labels
                       properties:
@@ -377,6 +1187,7 @@ spec:
                                     description: namespace defines the space within which the secret name must be unique.
                                     type: string
                                 type: object
+                                x-kubernetes-map-type: atomic
                               url:
                                 description: URL is the source external checks - this is usually a git repository. The notation for this is https://github.com/hashicorp/go-getter
                                 type: string
@@ -415,6 +1226,7 @@ spec:
                                   description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
                                   type: object
                               type: object
+                              x-kubernetes-map-type: atomic
                             resource:
                               description: Resource provides the ability to filter a configuration based on it's labels
                               properties:
@@ -445,6 +1257,7 @@ spec:
                                   description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
                                   type: object
                               type: object
+                              x-kubernetes-map-type: atomic
                           type: object
                         skipChecks:
                           description: SkipChecks is a collection of checkov checks which you can defined as skipped. The security scan will ignore any failures on these checks.
@@ -452,6 +1265,239 @@ spec:
                             type: string
                           type: array
                       type: object
+                    concurrency:
+                      description: Concurrency provides the ability to limit the number of terraform plan and apply jobs which can run at the same time for the configurations in each of the selected namespaces
+                      properties:
+                        maxJobs:
+                          description: MaxJobs is the maximum number of terraform plan and apply jobs which can be running at any one time for the configurations within a namespace, further configurations are queued
+                          minimum: 1
+                          type: integer
+                        selector:
+                          description: Selector is the selector on the namespace or labels on the configuration. By leaving this fields empty you can implicitly selecting all configurations.
+                          properties:
+                            namespace:
+                              description: Namespace is used to filter a configuration based on the namespace labels of where it exists
+                              properties:
+                                matchExpressions:
+                                  description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                  items:
+                                    description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                    properties:
+                                      key:
+                                        description: key is the label key that the selector applies to.
+                                        type: string
+                                      operator:
+                                        description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                        type: string
+                                      values:
+                                        description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                        items:
+                                          type: string
+                                        type: array
+                                    required:
+                                      - key
+                                      - operator
+                                    type: object
+                                  type: array
+                                matchLabels:
+                                  additionalProperties:
+                                    type: string
+                                  description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                  type: object
+                              type: object
+                              x-kubernetes-map-type: atomic
+                            resource:
+                              description: Resource provides the ability to filter a configuration based on it's labels
+                              properties:
+                                matchExpressions:
+                                  description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                  items:
+                                    description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                    properties:
+                                      key:
+                                        description: key is the label key that the selector applies to.
+                                        type: string
+                                      operator:
+                                        description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                        type: string
+                                      values:
+                                        description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                        items:
+                                          type: string
+                                        type: array
+                                    required:
+                                      - key
+                                      - operator
+                                    type: object
+                                  type: array
+                                matchLabels:
+                                  additionalProperties:
+                                    type: string
+                                  description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                  type: object
+                              type: object
+                              x-kubernetes-map-type: atomic
+                          type: object
+                      required:
+                        - maxJobs
+                      type: object
+                    destroyProtection:
+                      description: DestroyProtection provides the ability to require approval when the terraform plan of the selected configurations destroys or replaces resources, even when the configuration has auto approval enabled.
+                      properties:
+                        allResources:
+                          description: AllResources indicates any resource being destroyed or replaced requires approval
+                          type: boolean
+                        resourceTypes:
+                          description: ResourceTypes is a collection of terraform resource types i.e. aws_db_instance, which require approval before being destroyed or replaced
+                          items:
+                            type: string
+                          type: array
+                        selector:
+                          description: Selector is the selector on the namespace or labels on the configuration. By leaving this fields empty you can implicitly selecting all configurations.
+                          properties:
+                            namespace:
+                              description: Namespace is used to filter a configuration based on the namespace labels of where it exists
+                              properties:
+                                matchExpressions:
+                                  description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                  items:
+                                    description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                    properties:
+                                      key:
+                                        description: key is the label key that the selector applies to.
+                                        type: string
+                                      operator:
+                                        description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                        type: string
+                                      values:
+                                        description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                        items:
+                                          type: string
+                                        type: array
+                                    required:
+                                      - key
+                                      - operator
+                                    type: object
+                                  type: array
+                                matchLabels:
+                                  additionalProperties:
+                                    type: string
+                                  description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                  type: object
+                              type: object
+                              x-kubernetes-map-type: atomic
+                            resource:
+                              description: Resource provides the ability to filter a configuration based on it's labels
+                              properties:
+                                matchExpressions:
+                                  description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                  items:
+                                    description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                    properties:
+                                      key:
+                                        description: key is the label key that the selector applies to.
+                                        type: string
+                                      operator:
+                                        description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                        type: string
+                                      values:
+                                        description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                        items:
+                                          type: string
+                                        type: array
+                                    required:
+                                      - key
+                                      - operator
+                                    type: object
+                                  type: array
+                                matchLabels:
+                                  additionalProperties:
+                                    type: string
+                                  description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                  type: object
+                              type: object
+                              x-kubernetes-map-type: atomic
+                          type: object
+                      type: object
+                    driftSchedule:
+                      description: DriftSchedule provides the ability to define how often drift detection is run on the selected configurations, unless the configuration defines its own schedule
+                      properties:
+                        interval:
+                          description: Interval is the time between drift checks i.e. 1h or 168h, this cannot be used with the schedule
+                          type: string
+                        schedule:
+                          description: Schedule is a cron expression (minute hour day-of-month month day-of-week) defining when drift is checked, i.e. "0 * * * *" checks every hour
+                          type: string
+                        selector:
+                          description: Selector is the selector on the namespace or labels on the configuration. By leaving this fields empty you can implicitly selecting all configurations.
+                          properties:
+                            namespace:
+                              description: Namespace is used to filter a configuration based on the namespace labels of where it exists
+                              properties:
+                                matchExpressions:
+                                  description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                  items:
+                                    description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                    properties:
+                                      key:
+                                        description: key is the label key that the selector applies to.
+                                        type: string
+                                      operator:
+                                        description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                        type: string
+                                      values:
+                                        description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                        items:
+                                          type: string
+                                        type: array
+                                    required:
+                                      - key
+                                      - operator
+                                    type: object
+                                  type: array
+                                matchLabels:
+                                  additionalProperties:
+                                    type: string
+                                  description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                  type: object
+                              type: object
+                              x-kubernetes-map-type: atomic
+                            resource:
+                              description: Resource provides the ability to filter a configuration based on it's labels
+                              properties:
+                                matchExpressions:
+                                  description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                  items:
+                                    description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                    properties:
+                                      key:
+                                        description: key is the label key that the selector applies to.
+                                        type: string
+                                      operator:
+                                        description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                        type: string
+                                      values:
+                                        description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                        items:
+                                          type: string
+                                        type: array
+                                    required:
+                                      - key
+                                      - operator
+                                    type: object
+                                  type: array
+                                matchLabels:
+                                  additionalProperties:
+                                    type: string
+                                  description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                  type: object
+                              type: object
+                              x-kubernetes-map-type: atomic
+                          type: object
+                        timeZone:
+                          description: TimeZone is the IANA time zone the schedule is evaluated in i.e. Europe/London, defaults to UTC
+                          type: string
+                      type: object
                     modules:
                       description: Modules provides the ability to control the source for all terraform modules. Allowing platform teams to control where the modules can be downloaded from.
                       properties:
@@ -493,6 +1539,7 @@ spec:
                                   description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
                                   type: object
                               type: object
+                              x-kubernetes-map-type: atomic
                             resource:
                               description: Resource provides the ability to filter a configuration based on it's labels
                               properties:
@@ -523,8 +1570,125 @@ spec:
                                   description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
                                   type: object
                               type: object
+                              x-kubernetes-map-type: atomic
                           type: object
                       type: object
+                    notifications:
+                      description: Notifications is a collection of sinks which the lifecycle events of the selected configurations, i.e. a plan waiting on approval or drift being detected, are sent to
+                      items:
+                        description: NotificationConstraint defines a sink the lifecycle events of the selected configurations are sent to
+                        properties:
+                          events:
+                            description: Events is the collection of events sent to the sink, by leaving this field empty all events are sent
+                            items:
+                              description: NotificationEvent is a lifecycle event of a configuration which can be notified
+                              enum:
+                                - ApplyFailed
+                                - ApplySucceeded
+                                - CostIncreased
+                                - DriftDetected
+                                - PlanAwaitingApproval
+                                - PolicyFailed
+                              type: string
+                            type: array
+                          format:
+                            default: json
+                            description: Format is the format of the payload posted to the sink, either json, slack or cloudevents
+                            enum:
+                              - cloudevents
+                              - json
+                              - slack
+                            type: string
+                          name:
+                            description: Name is the name of the sink, unique within the policy
+                            type: string
+                          secretRef:
+                            description: SecretRef is a reference to a secret holding the url of the sink under the url key, this should be used when the url holds a token, i.e. a slack webhook. When the namespace is not defined the secret is taken from the controller namespace
+                            properties:
+                              name:
+                                description: name is unique within a namespace to reference a secret resource.
+                                type: string
+                              namespace:
+                                description: namespace defines the space within which the secret name must be unique.
+                                type: string
+                            type: object
+                            x-kubernetes-map-type: atomic
+                          selector:
+                            description: Selector is the selector on the namespace or labels on the configuration. By leaving this fields empty you can implicitly selecting all configurations.
+                            properties:
+                              namespace:
+                                description: Namespace is used to filter a configuration based on the namespace labels of where it exists
+                                properties:
+                                  matchExpressions:
+                                    description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                    items:
+                                      description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                      properties:
+                                        key:
+                                          description: key is the label key that the selector applies to.
+                                          type: string
+                                        operator:
+                                          description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                          type: string
+                                        values:
+                                          description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                          items:
+                                            type: string
+                                          type: array
+                                      required:
+                                        - key
+                                        - operator
+                                      type: object
+                                    type: array
+                                  matchLabels:
+                                    additionalProperties:
+                                      type: string
+                                    description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                    type: object
+                                type: object
+                                x-kubernetes-map-type: atomic
+                              resource:
+                                description: Resource provides the ability to filter a configuration based on it's labels
+                                properties:
+                                  matchExpressions:
+                                    description: matchExpressions is a list of label selector requirements. The requirements are ANDed.
+                                    items:
+                                      description: A label selector requirement is a selector that contains values, a key, and an operator that relates the key and values.
+                                      properties:
+                                        key:
+                                          description: key is the label key that the selector applies to.
+                                          type: string
+                                        operator:
+                                          description: operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists and DoesNotExist.
+                                          type: string
+                                        values:
+                                          description: values is an array of string values. If the operator is In or NotIn, the values array must be non-empty. If the operator is Exists or DoesNotExist, the values array must be empty. This array is replaced during a strategic merge patch.
+                                          items:
+                                            type: string
+                                          type: array
+                                      required:
+                                        - key
+                                        - operator
+                                      type: object
+                                    type: array
+                                  matchLabels:
+                                    additionalProperties:
+                                      type: string
+                                    description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
+                                    type: object
+                                type: object
+                                x-kubernetes-map-type: atomic
+                            type: object
+                          url:
+                            description: URL is the http endpoint the events are posted to
+                            type: string
+                        required:
+                          - name
+                        type: object
+                      type: array
+                      x-kubernetes-list-map-keys:
+                        - name
+                      x-kubernetes-list-type: map
                   type: object
                 defaults:
                   description: Defaults provides the ability to target specific terraform module based on namespace or resource labels and automatically inject variables into the configurations.
@@ -569,6 +1733,7 @@ spec:
                                 description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
                                 type: object
                             type: object
+                            x-kubernetes-map-type: atomic
                         type: object
                       variables:
                         description: Variables is a collection of variables to inject into the configuration
@@ -691,7 +1856,7 @@ var _chartsTerraformControllerCrdsTerraformAppviaIo_providersYaml = []byte(`apiV
 kind: CustomResourceDefinition
 metadata:
   annotations:
-    controller-gen.kubebuilder.io/version: v0.9.0
+    controller-gen.kubebuilder.io/version: v0.9.2
   creationTimestamp: null
   name: providers.terraform.appvia.io
 spec:
@@ -731,6 +1896,36 @@ spec:
             spec:
               description: ProviderSpec defines the desired state of a provider
               properties:
+                backend:
+                  description: Backend provides the ability to store the terraform state of configurations using this provider in an alternative backend. When not defined the controller default is used.
+                  properties:
+                    configuration:
+                      description: Configuration is the terraform backend configuration i.e. bucket, region or endpoint. Note the location of the state within the backend i.e. key or prefix is generated per configuration.
+                      type: object
+                      x-kubernetes-preserve-unknown-fields: true
+                    secretRef:
+                      description: SecretRef is a reference to a secret in the controller namespace containing the environment variables used to authenticate to the backend i.e. AWS_ACCESS_KEY_ID or ARM_ACCESS_KEY. These are used by the terraform jobs and by the controller when reading the state.
+                      properties:
+                        name:
+                          description: name is unique within a namespace to reference a secret resource.
+                          type: string
+                        namespace:
+                          description: namespace defines the space within which the secret name must be unique.
+                          type: string
+                      type: object
+                      x-kubernetes-map-type: atomic
+                    type:
+                      description: Type is the type of terraform backend, currently supported backends are kubernetes, s3, gcs, azurerm and http
+                      enum:
+                        - azurerm
+                        - gcs
+                        - http
+                        - kubernetes
+                        - s3
+                      type: string
+                  required:
+                    - type
+                  type: object
                 configuration:
                   description: Configuration is optional configuration to the provider. This is terraform provider specific.
                   type: object
@@ -748,6 +1943,7 @@ spec:
                       description: namespace defines the space within which the secret name must be unique.
                       type: string
                   type: object
+                  x-kubernetes-map-type: atomic
                 selector:
                   description: Selector provider the ability to filter who can use this provider. If empty, all users in the cluster is permitted to use the provider. Otherrise you can specify a selector which can use namespace and resource labels
                   properties:
@@ -781,6 +1977,7 @@ spec:
                           description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
                           type: object
                       type: object
+                      x-kubernetes-map-type: atomic
                     resource:
                       description: Resource provides the ability to filter a configuration based on it's labels
                       properties:
@@ -811,6 +2008,7 @@ spec:
                           description: matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent to an element of matchExpressions, whose key field is "key", the operator is "In", and the values array contains only "value". The requirements are ANDed.
                           type: object
                       type: object
+                      x-kubernetes-map-type: atomic
                   type: object
                 serviceAccount:
                   description: ServiceAccount is the name of a service account to use when the provider source is 'injected'. The service account should exist in the terraform controller namespace and be configure per cloud vendor requirements for pod identity.
@@ -818,6 +2016,9 @@ spec:
                 source:
                   description: Source defines the type of credentials the provider is wrapper, this could be wrapping a static secret or using a managed identity. The currently supported values are secret and injected.
                   type: string
+                summary:
+                  description: Summary provides a human readable description of the provider
+                  type: string
               required:
                 - provider
                 - source
@@ -1088,9 +2289,10 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"charts/terraform-controller/crds/terraform.appvia.io_configurations.yaml": chartsTerraformControllerCrdsTerraformAppviaIo_configurationsYaml,
-	"charts/terraform-controller/crds/terraform.appvia.io_policies.yaml":       chartsTerraformControllerCrdsTerraformAppviaIo_policiesYaml,
-	"charts/terraform-controller/crds/terraform.appvia.io_providers.yaml":      chartsTerraformControllerCrdsTerraformAppviaIo_providersYaml,
+	"charts/terraform-controller/crds/terraform.appvia.io_configurationrevisions.yaml": chartsTerraformControllerCrdsTerraformAppviaIo_configurationrevisionsYaml,
+	"charts/terraform-controller/crds/terraform.appvia.io_configurations.yaml":         chartsTerraformControllerCrdsTerraformAppviaIo_configurationsYaml,
+	"charts/terraform-controller/crds/terraform.appvia.io_policies.yaml":               chartsTerraformControllerCrdsTerraformAppviaIo_policiesYaml,
+	"charts/terraform-controller/crds/terraform.appvia.io_providers.yaml":              chartsTerraformControllerCrdsTerraformAppviaIo_providersYaml,
 	"webhooks/manifests.yaml": webhooksManifestsYaml,
 }
 
@@ -1098,11 +2300,13 @@ var _bindata = map[string]func() (*asset, error){
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"}
 // AssetDir("data/img") would return []string{"a.png", "b.png"}
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error
@@ -1135,17 +2339,18 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	"charts": &bintree{nil, map[string]*bintree{
-		"terraform-controller": &bintree{nil, map[string]*bintree{
-			"crds": &bintree{nil, map[string]*bintree{
-				"terraform.appvia.io_configurations.yaml": &bintree{chartsTerraformControllerCrdsTerraformAppviaIo_configurationsYaml, map[string]*bintree{}},
-				"terraform.appvia.io_policies.yaml":       &bintree{chartsTerraformControllerCrdsTerraformAppviaIo_policiesYaml, map[string]*bintree{}},
-				"terraform.appvia.io_providers.yaml":      &bintree{chartsTerraformControllerCrdsTerraformAppviaIo_providersYaml, map[string]*bintree{}},
+	"charts": {nil, map[string]*bintree{
+		"terraform-controller": {nil, map[string]*bintree{
+			"crds": {nil, map[string]*bintree{
+				"terraform.appvia.io_configurationrevisions.yaml": {chartsTerraformControllerCrdsTerraformAppviaIo_configurationrevisionsYaml, map[string]*bintree{}},
+				"terraform.appvia.io_configurations.yaml":         {chartsTerraformControllerCrdsTerraformAppviaIo_configurationsYaml, map[string]*bintree{}},
+				"terraform.appvia.io_policies.yaml":               {chartsTerraformControllerCrdsTerraformAppviaIo_policiesYaml, map[string]*bintree{}},
+				"terraform.appvia.io_providers.yaml":              {chartsTerraformControllerCrdsTerraformAppviaIo_providersYaml, map[string]*bintree{}},
 			}},
 		}},
 	}},
-	"webhooks": &bintree{nil, map[string]*bintree{
-		"manifests.yaml": &bintree{webhooksManifestsYaml, map[string]*bintree{}},
+	"webhooks": {nil, map[string]*bintree{
+		"manifests.yaml": {webhooksManifestsYaml, map[string]*bintree{}},
 	}},
 }}
 
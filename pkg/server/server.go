@@ -19,6 +19,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -27,20 +28,27 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 	"github.com/appvia/terraform-controller/pkg/apiserver"
 	"github.com/appvia/terraform-controller/pkg/controller/configuration"
 	"github.com/appvia/terraform-controller/pkg/controller/drift"
+	"github.com/appvia/terraform-controller/pkg/controller/expiry"
 	"github.com/appvia/terraform-controller/pkg/controller/policy"
 	"github.com/appvia/terraform-controller/pkg/controller/provider"
 	"github.com/appvia/terraform-controller/pkg/register"
 	"github.com/appvia/terraform-controller/pkg/schema"
 	k8sutils "github.com/appvia/terraform-controller/pkg/utils/kubernetes"
+	"github.com/appvia/terraform-controller/pkg/utils/modules"
+	"github.com/appvia/terraform-controller/pkg/utils/notifications"
 	"github.com/appvia/terraform-controller/pkg/version"
 )
 
@@ -62,6 +70,11 @@ func New(cfg *rest.Config, config Config) (*Server, error) {
 		return nil, fmt.Errorf("drift threshold must be greater than 0")
 	}
 
+	backend, err := newDefaultBackend(config)
+	if err != nil {
+		return nil, err
+	}
+
 	log.WithFields(log.Fields{
 		"gitsha":  version.GitCommit,
 		"version": version.Version,
@@ -111,7 +124,7 @@ func New(cfg *rest.Config, config Config) (*Server, error) {
 	}
 
 	options := manager.Options{
-		LeaderElection:                false,
+		LeaderElection:                config.EnableLeaderElection,
 		LeaderElectionID:              "controller.terraform.appvia.io",
 		LeaderElectionNamespace:       namespace,
 		LeaderElectionReleaseOnCancel: true,
@@ -121,8 +134,14 @@ func New(cfg *rest.Config, config Config) (*Server, error) {
 		SyncPeriod:                    &config.ResyncPeriod,
 	}
 
+	if config.EnableLeaderElection {
+		log.WithField("namespace", namespace).Info("enabling the leader election for the controllers")
+	}
+
 	if config.EnableWebhook {
 		log.Info("creating the webhook server for validation and mutations")
+		// @note: the manager does not require the leader election to run the webhook server, so it is
+		// served by every replica
 		options.WebhookServer = &webhook.Server{
 			CertDir:  config.TLSDir,
 			CertName: config.TLSCert,
@@ -141,9 +160,28 @@ func New(cfg *rest.Config, config Config) (*Server, error) {
 		log.Info("enabling the infracost integration")
 	}
 
+	var resolver modules.Resolver
+	if config.EnableModulePinning {
+		log.Info("enabling the module pinning, modules are resolved to an immutable revision")
+		resolver = modules.New(config.GitHubToken)
+	}
+
+	notifier := notifications.New(notifications.Options{
+		Attempts: config.NotificationAttempts,
+		Backoff:  config.NotificationBackoff,
+	})
+
+	retry := &terraformv1alphav1.Retry{
+		MaxAttempts: config.RetryMaxAttempts,
+		Backoff:     &metav1.Duration{Duration: config.RetryBackoff},
+	}
+
 	if err := (&configuration.Controller{
 		ControllerNamespace:     config.Namespace,
+		DefaultBackend:          backend,
+		DefaultRetry:            retry,
 		EnableInfracosts:        (config.InfracostsSecretName != ""),
+		EnableStateLocking:      config.EnableStateLocking,
 		EnableTerraformVersions: config.EnableTerraformVersions,
 		EnableWatchers:          config.EnableWatchers,
 		ExecutorImage:           config.ExecutorImage,
@@ -151,7 +189,13 @@ func New(cfg *rest.Config, config Config) (*Server, error) {
 		InfracostsImage:         config.InfracostsImage,
 		InfracostsSecretName:    config.InfracostsSecretName,
 		JobTemplate:             config.JobTemplate,
+		MaxConcurrentJobs:       config.MaxConcurrentJobs,
+		MaxConcurrentReconciles: config.MaxConcurrentReconciles,
+		ModuleResolver:          resolver,
+		Notifier:                notifier,
 		PolicyImage:             config.PolicyImage,
+		RevisionHistoryLimit:    config.RevisionHistoryLimit,
+		StateLockTimeout:        config.StateLockTimeout,
 		TerraformImage:          config.TerraformImage,
 	}).Add(mgr); err != nil {
 		return nil, fmt.Errorf("failed to create the configuration controller, error: %v", err)
@@ -165,6 +209,12 @@ func New(cfg *rest.Config, config Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to create the drift controller, error: %v", err)
 	}
 
+	if err := (&expiry.Controller{
+		WarningPeriod: config.ExpiryWarning,
+	}).Add(mgr); err != nil {
+		return nil, fmt.Errorf("failed to create the expiry controller, error: %v", err)
+	}
+
 	if err := (&provider.Controller{
 		ControllerNamespace: config.Namespace,
 	}).Add(mgr); err != nil {
@@ -192,6 +242,8 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 	}
 
+	// @note: the apiserver is served outside of the manager, so by every replica regardless of the
+	// leader election
 	go func() {
 		log.Info("starting the api server")
 		if err := s.hs.Serve(s.listener); err != nil {
@@ -201,3 +253,26 @@ func (s *Server) Start(ctx context.Context) error {
 
 	return s.mgr.Start(ctrl.SetupSignalHandler())
 }
+
+// newDefaultBackend returns the default terraform state backend from the controller configuration
+func newDefaultBackend(config Config) (*terraformv1alphav1.Backend, error) {
+	backend := &terraformv1alphav1.Backend{Type: terraformv1alphav1.BackendType(config.BackendType)}
+	if backend.Type == "" {
+		backend.Type = terraformv1alphav1.KubernetesBackendType
+	}
+	if !terraformv1alphav1.IsSupportedBackendType(backend.Type) {
+		return nil, fmt.Errorf("backend type: %q is not supported", config.BackendType)
+	}
+
+	if config.BackendConfig != "" {
+		if !json.Valid([]byte(config.BackendConfig)) {
+			return nil, fmt.Errorf("backend configuration must be valid json")
+		}
+		backend.Configuration = &runtime.RawExtension{Raw: []byte(config.BackendConfig)}
+	}
+	if config.BackendSecret != "" {
+		backend.SecretRef = &v1.SecretReference{Name: config.BackendSecret, Namespace: config.Namespace}
+	}
+
+	return backend, nil
+}
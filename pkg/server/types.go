@@ -25,10 +25,22 @@ type Config struct {
 	ExecutorSecrets []string
 	// APIServerPort is the port to listen on
 	APIServerPort int
+	// BackendConfig is the json encoded configuration for the default terraform state backend
+	BackendConfig string
+	// BackendSecret is the name of the secret containing the credentials for the default backend
+	BackendSecret string
+	// BackendType is the default terraform state backend type
+	BackendType string
 	// DriftControllerInterval is the interval for the controller to check for drift
 	DriftControllerInterval time.Duration
-	// DriftInterval is the minimum interval between drift checks
+	// DriftInterval is the minimum interval between drift checks, unless a drift schedule is defined
 	DriftInterval time.Duration
+	// EnableLeaderElection indicates the controllers are only run by the elected leader
+	EnableLeaderElection bool
+	// EnableModulePinning indicates modules are resolved to an immutable revision
+	EnableModulePinning bool
+	// EnableStateLocking indicates terraform acquires the state lock when running
+	EnableStateLocking bool
 	// EnableWebhook enables the webhook registration
 	EnableWebhook bool
 	// EnableWatchers enables the creation of watcher jobs
@@ -37,6 +49,10 @@ type Config struct {
 	EnableTerraformVersions bool
 	// ExecutorImage is the image to use for the executor
 	ExecutorImage string
+	// ExpiryWarning is the duration before a configuration expires a warning event is raised
+	ExpiryWarning time.Duration
+	// GitHubToken is an optional token used when resolving github modules
+	GitHubToken string
 	// InfracostsSecretName is the name of the secret that contains the cost token and endpoint
 	InfracostsSecretName string
 	// InfracostsImage is the image to use for infracosts
@@ -46,16 +62,32 @@ type Config struct {
 	// DriftThreshold is the max number of drifts we are running to run - this prevents the
 	// controller from running many configurations at the same time
 	DriftThreshold float64
+	// MaxConcurrentJobs is the maximum number of terraform jobs running at any one time
+	MaxConcurrentJobs int
+	// MaxConcurrentReconciles is the number of configurations reconciled concurrently
+	MaxConcurrentReconciles int
 	// MetricsPort is the port to listen on
 	MetricsPort int
 	// Namespace is namespace the controller is running
 	Namespace string
+	// NotificationAttempts is the maximum number of attempts at delivering an event to a notification sink
+	NotificationAttempts int
+	// NotificationBackoff is the delay before a failed notification is retried, doubled on each attempt
+	NotificationBackoff time.Duration
 	// PolicyImage is the image to use for policy
 	PolicyImage string
 	// RegisterCRDs indicated we register our crds
 	RegisterCRDs bool
+	// RetryBackoff is the default delay before a failed job is first retried
+	RetryBackoff time.Duration
+	// RetryMaxAttempts is the default maximum number of attempts at running a failed stage
+	RetryMaxAttempts int
+	// RevisionHistoryLimit is the maximum number of revisions retained per configuration
+	RevisionHistoryLimit int
 	// ResyncPeriod is the period to resync the controller manager
 	ResyncPeriod time.Duration
+	// StateLockTimeout is the duration terraform waits to acquire the state lock
+	StateLockTimeout time.Duration
 	// TerraformImage is the image to use for terraform
 	TerraformImage string
 	// TLSDir is the directory where the TLS certificates are stored
@@ -23,6 +23,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 
 	terraformv1alpha1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/utils/jobs"
 )
 
 // Filter provides a filter for jobs
@@ -96,10 +97,14 @@ func (j *Filter) Latest() (*batchv1.Job, bool) {
 		return nil, false
 	}
 
-	// @step: find the latest item in the list
+	// @step: find the latest item in the list, jobs created within the same second are ordered
+	// by their attempt at running the stage
 	latest := &list.Items[0]
 	for i := 0; i < len(list.Items); i++ {
-		if list.Items[i].CreationTimestamp.After(latest.CreationTimestamp.Time) {
+		switch {
+		case list.Items[i].CreationTimestamp.After(latest.CreationTimestamp.Time):
+			latest = &list.Items[i]
+		case list.Items[i].CreationTimestamp.Equal(&latest.CreationTimestamp) && jobs.Attempt(&list.Items[i]) > jobs.Attempt(latest):
 			latest = &list.Items[i]
 		}
 	}
@@ -138,3 +138,32 @@ func TestFilterList(t *testing.T) {
 		assert.Equal(t, c.Expected, len(list.Items))
 	}
 }
+
+func TestFilterLatestAttempt(t *testing.T) {
+	created := metav1.Time{Time: time.Now().Truncate(time.Second)}
+	list := batchv1.JobList{
+		Items: []batchv1.Job{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: created,
+					Labels: map[string]string{
+						terraformv1alpha1.ConfigurationAttemptLabel: "2",
+						terraformv1alpha1.ConfigurationStageLabel:   "plan",
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: created,
+					Labels: map[string]string{
+						terraformv1alpha1.ConfigurationStageLabel: "plan",
+					},
+				},
+			},
+		},
+	}
+
+	job, found := Jobs(&list).WithStage("plan").Latest()
+	assert.True(t, found)
+	assert.Equal(t, "2", job.Labels[terraformv1alpha1.ConfigurationAttemptLabel])
+}
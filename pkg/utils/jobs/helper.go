@@ -1,8 +1,13 @@
 package jobs
 
 import (
+	"strconv"
+	"time"
+
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 )
 
 // IsFailed returns true of the job has failed
@@ -31,3 +36,24 @@ func IsComplete(job *batchv1.Job) bool {
 func IsActive(job *batchv1.Job) bool {
 	return !IsComplete(job) && !IsFailed(job)
 }
+
+// Attempt returns the attempt at running the stage the job was created for, defaulting to one
+func Attempt(job *batchv1.Job) int {
+	attempt, err := strconv.Atoi(job.GetLabels()[terraformv1alphav1.ConfigurationAttemptLabel])
+	if err != nil || attempt < 1 {
+		return 1
+	}
+
+	return attempt
+}
+
+// FailedTime returns the time the job failed, falling back to the creation time of the job
+func FailedTime(job *batchv1.Job) time.Time {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue && !condition.LastTransitionTime.IsZero() {
+			return condition.LastTransitionTime.Time
+		}
+	}
+
+	return job.CreationTimestamp.Time
+}
@@ -19,10 +19,14 @@ package jobs
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"text/template"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
@@ -32,6 +36,7 @@ import (
 
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 	"github.com/appvia/terraform-controller/pkg/utils"
+	"github.com/appvia/terraform-controller/pkg/utils/terraform"
 )
 
 // DefaultServiceAccount is the default service account to use for the job if no override is given
@@ -44,8 +49,12 @@ const TerraformContainerName = "terraform"
 type Options struct {
 	// AdditionalLabels are additional labels added to the job
 	AdditionalLabels map[string]string
+	// BackendSecret is the name of the secret containing the credentials for the state backend
+	BackendSecret string
 	// EnableInfraCosts is the flag to enable cost analysis
 	EnableInfraCosts bool
+	// EnableStateLocking indicates terraform should acquire the state lock
+	EnableStateLocking bool
 	// ExecutorImage is the image to use for the terraform jobs
 	ExecutorImage string
 	// ExecutorSecrets is a list of additional secrets to add to the job
@@ -54,12 +63,18 @@ type Options struct {
 	InfracostsImage string
 	// InfracostsSecret is the name of the secret contain the infracost token and url
 	InfracostsSecret string
+	// LockID is the identifier of the state lock released by the unlock job
+	LockID string
+	// Module is the source of the module pinned to a revision, defaults to the configuration module
+	Module string
 	// Namespace is the location of the jobs
 	Namespace string
 	// PolicyConstraint is a matching constraint for this policy
 	PolicyConstraint *terraformv1alphav1.PolicyConstraint
 	// PolicyImage is image to use for checkov
 	PolicyImage string
+	// StateLockTimeout is the duration terraform waits to acquire the state lock
+	StateLockTimeout time.Duration
 	// Template is the source for the job template if overridden by the controller
 	Template []byte
 	// TerraformImage is the image to use for the terraform jobs
@@ -158,6 +173,12 @@ func (r *Render) NewTerraformPlan(options Options) (*batchv1.Job, error) {
 	return r.createTerraformFromTemplate(options, terraformv1alphav1.StageTerraformPlan)
 }
 
+// NewTerraformDrift is responsible for creating a batch job to run terraform plan -refresh-only, checking
+// the resources for drift, followed by a terraform plan to classify any drift found
+func (r *Render) NewTerraformDrift(options Options) (*batchv1.Job, error) {
+	return r.createTerraformFromTemplate(options, terraformv1alphav1.StageTerraformDrift)
+}
+
 // NewTerraformApply is responsible for creating a batch job to run terraform apply
 func (r *Render) NewTerraformApply(options Options) (*batchv1.Job, error) {
 	return r.createTerraformFromTemplate(options, terraformv1alphav1.StageTerraformApply)
@@ -168,12 +189,52 @@ func (r *Render) NewTerraformDestroy(options Options) (*batchv1.Job, error) {
 	return r.createTerraformFromTemplate(options, terraformv1alphav1.StageTerraformDestroy)
 }
 
+// NewTerraformDestroyPlan is responsible for creating a batch job to run terraform plan -destroy
+func (r *Render) NewTerraformDestroyPlan(options Options) (*batchv1.Job, error) {
+	return r.createTerraformFromTemplate(options, terraformv1alphav1.StageTerraformDestroyPlan)
+}
+
+// NewTerraformUnlock is used to create a terraform job which force releases the state lock
+func (r *Render) NewTerraformUnlock(options Options) (*batchv1.Job, error) {
+	if !terraform.IsValidLockID(options.LockID) {
+		return nil, fmt.Errorf("invalid state lock id: %q", options.LockID)
+	}
+
+	return r.createTerraformFromTemplate(options, terraformv1alphav1.StageTerraformUnlock)
+}
+
 // createTerraformFromTemplate is used to render the terraform job from the parameters and the template
 func (r *Render) createTerraformFromTemplate(options Options, stage string) (*batchv1.Job, error) {
-	var arguments string
+	var arguments []string
+
+	// @note: variables are baked into the saved plan, terraform refuses them when applying a plan file
+	if r.configuration.HasVariables() && stage != terraformv1alphav1.StageTerraformApply {
+		arguments = append(arguments, fmt.Sprintf("--var-file %s", terraformv1alphav1.TerraformVariablesConfigMapKey))
+	}
+	// @note: likewise the operations are only passed to the plan, the apply uses the saved plan
+	if r.configuration.HasOperations() && stage == terraformv1alphav1.StageTerraformPlan {
+		for _, x := range r.configuration.Spec.Operations.Replace {
+			if !terraform.IsValidAddress(x) {
+				return nil, fmt.Errorf("invalid replace address: %q", x)
+			}
+			arguments = append(arguments, fmt.Sprintf("-replace='%s'", x))
+		}
+		for _, x := range r.configuration.Spec.Operations.Target {
+			if !terraform.IsValidAddress(x) {
+				return nil, fmt.Errorf("invalid target address: %q", x)
+			}
+			arguments = append(arguments, fmt.Sprintf("-target='%s'", x))
+		}
+	}
 
-	if r.configuration.HasVariables() {
-		arguments = fmt.Sprintf("--var-file %s", terraformv1alphav1.TerraformVariablesConfigMapKey)
+	locking := "-lock=false"
+	if options.EnableStateLocking {
+		locking = fmt.Sprintf("-lock-timeout=%s", options.StateLockTimeout)
+	}
+
+	module := r.configuration.Spec.Module
+	if options.Module != "" {
+		module = options.Module
 	}
 
 	params := map[string]interface{}{
@@ -193,22 +254,29 @@ func (r *Render) createTerraformFromTemplate(options Options, stage string) (*ba
 			"ServiceAccount": pointer.StringPtrDerefOr(r.provider.Spec.ServiceAccount, ""),
 			"Source":         string(r.provider.Spec.Source),
 		},
+		// @note: import blocks are only required to plan, the destroy and drift are only concerned with the state
+		"EnableImports": r.configuration.HasImports() &&
+			stage != terraformv1alphav1.StageTerraformDestroy && stage != terraformv1alphav1.StageTerraformDestroyPlan &&
+			stage != terraformv1alphav1.StageTerraformDrift,
 		"EnableInfraCosts":       options.EnableInfraCosts,
 		"EnableVariables":        r.configuration.HasVariables(),
 		"ExecutorSecrets":        options.ExecutorSecrets,
 		"ImagePullPolicy":        "IfNotPresent",
+		"LockArguments":          locking,
+		"LockID":                 options.LockID,
 		"Policy":                 options.PolicyConstraint,
 		"ServiceAccount":         DefaultServiceAccount,
 		"Stage":                  stage,
-		"TerraformArguments":     arguments,
+		"TerraformArguments":     strings.Join(arguments, " "),
 		"TerraformContainerName": TerraformContainerName,
 		"Configuration": map[string]interface{}{
 			"Generation": fmt.Sprintf("%d", r.configuration.GetGeneration()),
-			"Module":     r.configuration.Spec.Module,
+			"Module":     module,
 			"Name":       r.configuration.Name,
 			"Namespace":  r.configuration.Namespace,
 			"UUID":       string(r.configuration.GetUID()),
 			"Variables":  r.configuration.Spec.Variables,
+			"Workspace":  r.configuration.GetWorkspace(),
 		},
 		"Images": map[string]interface{}{
 			"Executor":   options.ExecutorImage,
@@ -217,10 +285,13 @@ func (r *Render) createTerraformFromTemplate(options Options, stage string) (*ba
 			"Policy":     options.PolicyImage,
 		},
 		"Secrets": map[string]interface{}{
+			"Backend":          options.BackendSecret,
 			"Config":           r.configuration.GetTerraformConfigSecretName(),
 			"Infracosts":       options.InfracostsSecret,
 			"InfracostsReport": r.configuration.GetTerraformCostSecretName(),
 			"PolicyReport":     r.configuration.GetTerraformPolicySecretName(),
+			"TerraformDrift":   r.configuration.GetTerraformDriftPlanSecretName(),
+			"TerraformPlan":    r.configuration.GetTerraformPlanSecretName(),
 		},
 	}
 
@@ -246,5 +317,33 @@ func (r *Render) createTerraformFromTemplate(options Options, stage string) (*ba
 		return nil, err
 	}
 
+	// @note: the name is derived from the labels, so two replicas racing to create the same job
+	// will collide rather than both running it. This overrides any generated name in the template
+	job.GenerateName = ""
+	job.Name = Name(r.configuration.Name, stage, job.GetLabels())
+
 	return job, nil
 }
+
+// Name returns a deterministic name for a job from the configuration name, the stage and the labels
+// which identify the job, i.e. the generation and uid of the configuration
+func Name(name, stage string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(hash, "%s=%s\n", k, labels[k])
+	}
+
+	// @note: the job name is used as a label on the pods, so must not exceed 63 characters
+	prefix := fmt.Sprintf("%s-%s", name, stage)
+	if len(prefix) > 54 {
+		prefix = strings.TrimRight(prefix[:54], "-.")
+	}
+
+	return fmt.Sprintf("%s-%s", prefix, hex.EncodeToString(hash.Sum(nil))[:8])
+}
@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package modules
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Masterminds/semver"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/cmd/search"
+	"github.com/appvia/terraform-controller/pkg/cmd/search/github"
+	"github.com/appvia/terraform-controller/pkg/cmd/search/terraform"
+)
+
+// Reference is a module source resolved to an immutable revision
+type Reference struct {
+	// Revision is the commit sha or registry version the module resolved to
+	Revision string
+	// Source is the module source pinned to the revision
+	Source string
+	// Version is the version the module source referenced, i.e. the git ref or registry version
+	Version string
+	// Versions is a list of the released versions of the module
+	Versions []string
+}
+
+// Resolver is used to resolve module sources to an immutable revision
+type Resolver interface {
+	// Resolve returns the reference for the module source, or nil when the source is not supported
+	Resolve(ctx context.Context, source string) (*Reference, error)
+}
+
+type resolver struct {
+	// token is an optional token used when calling the github api
+	token string
+}
+
+// New returns a module resolver for github and terraform registry sources
+func New(token string) Resolver {
+	return &resolver{token: token}
+}
+
+// Resolve returns the reference for the module source, or nil when the source is not supported
+func (r *resolver) Resolve(ctx context.Context, source string) (*Reference, error) {
+	if module, found := parseRegistrySource(source); found {
+		handler, err := terraform.New(module.Registry)
+		if err != nil {
+			return nil, err
+		}
+
+		return r.resolveRegistry(ctx, handler, module)
+	}
+
+	if module, found := parseGitHubSource(source); found {
+		handler, err := github.New(module.Registry, r.token)
+		if err != nil {
+			return nil, err
+		}
+
+		return r.resolveGitHub(ctx, handler, source, module)
+	}
+
+	return nil, nil
+}
+
+// resolveGitHub resolves the git ref of the module to a commit sha
+func (r *resolver) resolveGitHub(ctx context.Context, handler search.Interface, source string, module search.Module) (*Reference, error) {
+	revision, err := handler.Revision(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+
+	// @note: repositories without any tags simply have no versions to report
+	versions, _ := handler.Versions(ctx, module)
+
+	pinned, err := pinGitSource(source, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reference{
+		Revision: revision,
+		Source:   pinned,
+		Version:  module.Version,
+		Versions: versions,
+	}, nil
+}
+
+// resolveRegistry resolves the registry module to a version and the source of the version
+func (r *resolver) resolveRegistry(ctx context.Context, handler search.Interface, module search.Module) (*Reference, error) {
+	versions, err := handler.Versions(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+
+	// @step: when no version is defined we use the latest release
+	if module.Version == "" {
+		latest, found := FindLatest(versions)
+		if !found {
+			return nil, fmt.Errorf("no versions found for module %s/%s/%s", module.Namespace, module.Name, module.Provider)
+		}
+		module.Version = latest
+	}
+
+	revision, err := handler.Revision(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned, err := handler.ResolveSource(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reference{
+		Revision: revision,
+		Source:   pinned,
+		Version:  module.Version,
+		Versions: versions,
+	}, nil
+}
+
+// FindLatest returns the latest semantic version from the list
+func FindLatest(versions []string) (string, bool) {
+	var latest *semver.Version
+
+	for _, x := range versions {
+		v, err := semver.NewVersion(x)
+		if err != nil || v.Prerelease() != "" {
+			continue
+		}
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+		}
+	}
+	if latest == nil {
+		return "", false
+	}
+
+	return latest.Original(), true
+}
+
+// FindUpdate returns the latest version newer than the current version which is permitted by the
+// update policy, i.e. patch only permits newer patch releases of the same minor version
+func FindUpdate(current string, versions []string, policy terraformv1alphav1.ModuleUpdatePolicy) (string, bool) {
+	switch policy {
+	case terraformv1alphav1.ModuleUpdatePolicyPatch, terraformv1alphav1.ModuleUpdatePolicyMinor:
+	default:
+		return "", false
+	}
+
+	version, err := semver.NewVersion(current)
+	if err != nil {
+		return "", false
+	}
+
+	var permitted []string
+	for _, x := range versions {
+		v, err := semver.NewVersion(x)
+		switch {
+		case err != nil, !v.GreaterThan(version), v.Major() != version.Major():
+			continue
+		case policy == terraformv1alphav1.ModuleUpdatePolicyPatch && v.Minor() != version.Minor():
+			continue
+		}
+		permitted = append(permitted, x)
+	}
+
+	return FindLatest(permitted)
+}
+
+// parseGitHubSource parses a github module source, i.e. github.com/appvia/terraform-aws-vpc?ref=v1.0.0
+func parseGitHubSource(source string) (search.Module, bool) {
+	u, err := parseSource(source)
+	if err != nil || u.Hostname() != "github.com" {
+		return search.Module{}, false
+	}
+
+	// @note: the path may contain a subdirectory i.e. /appvia/repo.git//modules/vpc
+	path := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "//", 2)[0]
+	items := strings.Split(strings.TrimSuffix(path, ".git"), "/")
+	if len(items) != 2 || items[0] == "" || items[1] == "" {
+		return search.Module{}, false
+	}
+
+	return search.Module{
+		Name:      items[1],
+		Namespace: items[0],
+		Registry:  "https://github.com/" + items[0],
+		Version:   u.Query().Get("ref"),
+	}, true
+}
+
+// parseRegistrySource parses a terraform registry module source, i.e.
+// registry.terraform.io/terraform-aws-modules/vpc/aws?version=3.14.0
+func parseRegistrySource(source string) (search.Module, bool) {
+	u, err := parseSource(source)
+	if err != nil || u.Hostname() != "registry.terraform.io" {
+		return search.Module{}, false
+	}
+
+	items := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(items) != 3 {
+		return search.Module{}, false
+	}
+
+	return search.Module{
+		Name:      items[1],
+		Namespace: items[0],
+		Provider:  items[2],
+		Registry:  "https://" + u.Host,
+		Version:   u.Query().Get("version"),
+	}, true
+}
+
+// parseSource parses the go-getter module source into a url
+func parseSource(source string) (*url.URL, error) {
+	source = strings.TrimPrefix(source, "git::")
+	if !strings.Contains(source, "://") {
+		source = "https://" + source
+	}
+
+	return url.Parse(source)
+}
+
+// pinGitSource returns the git source with the ref replaced by the commit sha
+func pinGitSource(source, revision string) (string, error) {
+	var forced string
+	if strings.HasPrefix(source, "git::") {
+		forced = "git::"
+		source = strings.TrimPrefix(source, "git::")
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", err
+	}
+
+	// @note: shallow clones are unable to checkout a commit
+	query := u.Query()
+	query.Del("depth")
+	query.Set("ref", revision)
+	u.RawQuery = query.Encode()
+
+	return forced + u.String(), nil
+}
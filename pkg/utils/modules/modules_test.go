@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package modules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+)
+
+func TestParseGitHubSource(t *testing.T) {
+	cases := []struct {
+		Source    string
+		Found     bool
+		Namespace string
+		Name      string
+		Version   string
+	}{
+		{Source: "https://github.com/appvia/terraform-aws-vpc.git?ref=v1.0.0", Found: true, Namespace: "appvia", Name: "terraform-aws-vpc", Version: "v1.0.0"},
+		{Source: "github.com/appvia/terraform-aws-vpc?ref=main", Found: true, Namespace: "appvia", Name: "terraform-aws-vpc", Version: "main"},
+		{Source: "git::ssh://git@github.com/appvia/terraform-aws-vpc.git", Found: true, Namespace: "appvia", Name: "terraform-aws-vpc"},
+		{Source: "git::https://github.com/appvia/modules.git//vpc?ref=v1.2.0", Found: true, Namespace: "appvia", Name: "modules", Version: "v1.2.0"},
+		{Source: "https://gitlab.com/appvia/terraform-aws-vpc.git?ref=v1.0.0"},
+		{Source: "https://github.com/appvia"},
+		{Source: "s3::https://s3-eu-west-1.amazonaws.com/bucket/module.zip"},
+	}
+	for _, c := range cases {
+		module, found := parseGitHubSource(c.Source)
+		assert.Equal(t, c.Found, found, "case: %s", c.Source)
+		assert.Equal(t, c.Namespace, module.Namespace, "case: %s", c.Source)
+		assert.Equal(t, c.Name, module.Name, "case: %s", c.Source)
+		assert.Equal(t, c.Version, module.Version, "case: %s", c.Source)
+	}
+}
+
+func TestParseRegistrySource(t *testing.T) {
+	module, found := parseRegistrySource("registry.terraform.io/terraform-aws-modules/vpc/aws?version=3.14.0")
+	assert.True(t, found)
+	assert.Equal(t, "terraform-aws-modules", module.Namespace)
+	assert.Equal(t, "vpc", module.Name)
+	assert.Equal(t, "aws", module.Provider)
+	assert.Equal(t, "3.14.0", module.Version)
+	assert.Equal(t, "https://registry.terraform.io", module.Registry)
+
+	_, found = parseRegistrySource("registry.terraform.io/terraform-aws-modules/vpc")
+	assert.False(t, found)
+
+	_, found = parseRegistrySource("https://github.com/terraform-aws-modules/vpc/aws")
+	assert.False(t, found)
+}
+
+func TestPinGitSource(t *testing.T) {
+	cases := map[string]string{
+		"https://github.com/appvia/vpc.git?ref=main":            "https://github.com/appvia/vpc.git?ref=abc123",
+		"https://github.com/appvia/vpc.git":                     "https://github.com/appvia/vpc.git?ref=abc123",
+		"git::https://github.com/appvia/vpc.git?depth=1&ref=v1": "git::https://github.com/appvia/vpc.git?ref=abc123",
+		"git::ssh://git@github.com/appvia/vpc.git//sub?ref=v1":  "git::ssh://git@github.com/appvia/vpc.git//sub?ref=abc123",
+	}
+	for source, expected := range cases {
+		pinned, err := pinGitSource(source, "abc123")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, pinned, "case: %s", source)
+	}
+}
+
+func TestFindLatest(t *testing.T) {
+	latest, found := FindLatest([]string{"v1.0.0", "v1.2.0", "v1.10.0", "v2.0.0-rc1", "main"})
+	assert.True(t, found)
+	assert.Equal(t, "v1.10.0", latest)
+
+	_, found = FindLatest([]string{"main", "develop"})
+	assert.False(t, found)
+}
+
+func TestFindUpdate(t *testing.T) {
+	versions := []string{"v1.0.0", "v1.0.1", "v1.0.2", "v1.1.0", "v1.2.3", "v2.0.0"}
+
+	cases := []struct {
+		Current  string
+		Policy   terraformv1alphav1.ModuleUpdatePolicy
+		Expected string
+	}{
+		{Current: "v1.0.0", Policy: terraformv1alphav1.ModuleUpdatePolicyPatch, Expected: "v1.0.2"},
+		{Current: "v1.0.0", Policy: terraformv1alphav1.ModuleUpdatePolicyMinor, Expected: "v1.2.3"},
+		{Current: "v1.0.0", Policy: terraformv1alphav1.ModuleUpdatePolicyNone},
+		{Current: "v1.2.3", Policy: terraformv1alphav1.ModuleUpdatePolicyMinor},
+		{Current: "main", Policy: terraformv1alphav1.ModuleUpdatePolicyMinor},
+	}
+	for _, c := range cases {
+		update, found := FindUpdate(c.Current, versions, c.Policy)
+		assert.Equal(t, c.Expected != "", found, "case: %s/%s", c.Current, c.Policy)
+		assert.Equal(t, c.Expected, update, "case: %s/%s", c.Current, c.Policy)
+	}
+}
+
+func TestResolveUnsupportedSource(t *testing.T) {
+	reference, err := New("").Resolve(context.Background(), "s3::https://s3-eu-west-1.amazonaws.com/bucket/module.zip")
+	assert.NoError(t, err)
+	assert.Nil(t, reference)
+}
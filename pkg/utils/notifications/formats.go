@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+)
+
+// CloudEventTypePrefix is the prefix of the type of the events when sent as cloudevents
+const CloudEventTypePrefix = "io.appvia.terraform.configuration."
+
+// CloudEvent is a cloudevent in the structured content mode
+type CloudEvent struct {
+	// Data is the event
+	Data Event `json:"data"`
+	// DataContentType is the content type of the data
+	DataContentType string `json:"datacontenttype"`
+	// ID uniquely identifies the event
+	ID string `json:"id"`
+	// Source identifies the context in which the event happened
+	Source string `json:"source"`
+	// SpecVersion is the version of the cloudevents specification
+	SpecVersion string `json:"specversion"`
+	// Subject is the configuration the event relates to
+	Subject string `json:"subject"`
+	// Time is when the event occurred
+	Time string `json:"time"`
+	// Type is the type of the event
+	Type string `json:"type"`
+}
+
+// SlackMessage is a slack compatible incoming webhook message
+type SlackMessage struct {
+	// Text is the content of the message
+	Text string `json:"text"`
+}
+
+// Render returns the content type and payload of the event in the format
+func Render(format terraformv1alphav1.NotificationFormat, source string, event Event) (string, []byte, error) {
+	switch format {
+	case terraformv1alphav1.NotificationFormatCloudEvents:
+		encoded, err := json.Marshal(&CloudEvent{
+			Data:            event,
+			DataContentType: "application/json",
+			ID:              event.ID,
+			Source:          source,
+			SpecVersion:     "1.0",
+			Subject:         fmt.Sprintf("%s/%s", event.Namespace, event.Configuration),
+			Time:            event.Time.UTC().Format(time.RFC3339),
+			Type:            CloudEventTypePrefix + strings.ToLower(string(event.Type)),
+		})
+
+		return "application/cloudevents+json", encoded, err
+
+	case terraformv1alphav1.NotificationFormatSlack:
+		encoded, err := json.Marshal(&SlackMessage{
+			Text: fmt.Sprintf("*%s* configuration %s/%s: %s", event.Type, event.Namespace, event.Configuration, event.Message),
+		})
+
+		return "application/json", encoded, err
+
+	case terraformv1alphav1.NotificationFormatJSON, "":
+		encoded, err := json.Marshal(&event)
+
+		return "application/json", encoded, err
+	}
+
+	return "", nil, fmt.Errorf("unsupported notification format %q", format)
+}
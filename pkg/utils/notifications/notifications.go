@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+)
+
+const (
+	// DefaultAttempts is the default number of attempts at sending an event
+	DefaultAttempts = 3
+	// DefaultBackoff is the default delay before the first retry
+	DefaultBackoff = 2 * time.Second
+	// DefaultSource is the default cloudevents source of the events
+	DefaultSource = "terraform-controller"
+	// DefaultTimeout is the default timeout of a single attempt
+	DefaultTimeout = 10 * time.Second
+)
+
+// Event is a lifecycle event of a configuration sent to the notification sinks
+type Event struct {
+	// Configuration is the name of the configuration
+	Configuration string `json:"configuration"`
+	// Generation is the generation of the configuration the event relates to
+	Generation int64 `json:"generation"`
+	// ID uniquely identifies the event, allowing the receiver to drop duplicates
+	ID string `json:"id"`
+	// Message is a human readable description of the event
+	Message string `json:"message"`
+	// Namespace is the namespace of the configuration
+	Namespace string `json:"namespace"`
+	// Time is when the event occurred
+	Time time.Time `json:"time"`
+	// Type is the type of the event
+	Type terraformv1alphav1.NotificationEvent `json:"type"`
+	// UID is the uid of the configuration
+	UID string `json:"uid"`
+}
+
+// Sink is a http endpoint the events are posted to
+type Sink struct {
+	// Format is the format of the payload
+	Format terraformv1alphav1.NotificationFormat
+	// Name is the name of the sink
+	Name string
+	// URL is the endpoint the event is posted to
+	URL string
+}
+
+// Notifier is used to send the lifecycle events to the notification sinks
+type Notifier interface {
+	// Send posts the event to the sink, retrying on failure
+	Send(ctx context.Context, sink Sink, event Event) error
+}
+
+// Options are the options for the notifier
+type Options struct {
+	// Attempts is the maximum number of attempts at sending an event
+	Attempts int
+	// Backoff is the delay before the first retry, doubled on each attempt
+	Backoff time.Duration
+	// Source is the source of the events when sent as cloudevents
+	Source string
+	// Timeout is the timeout of a single attempt
+	Timeout time.Duration
+}
+
+type notifier struct {
+	// client is the http client used to post the events
+	client *http.Client
+	// options are the options for the notifier
+	options Options
+}
+
+// New returns a notifier which posts the events over http
+func New(options Options) Notifier {
+	if options.Attempts <= 0 {
+		options.Attempts = DefaultAttempts
+	}
+	if options.Backoff <= 0 {
+		options.Backoff = DefaultBackoff
+	}
+	if options.Source == "" {
+		options.Source = DefaultSource
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+
+	return &notifier{
+		client:  &http.Client{Timeout: options.Timeout},
+		options: options,
+	}
+}
+
+// Send posts the event to the sink, retrying on failure
+func (n *notifier) Send(ctx context.Context, sink Sink, event Event) error {
+	if sink.URL == "" {
+		return fmt.Errorf("notification sink %q has no url", sink.Name)
+	}
+
+	contentType, payload, err := Render(sink.Format, n.options.Source, event)
+	if err != nil {
+		return err
+	}
+
+	backoff := n.options.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := n.post(ctx, sink.URL, contentType, payload)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= n.options.Attempts {
+			return fmt.Errorf("failed to send the event to notification sink %q after %d attempt(s), %w", sink.Name, attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends the payload to the url, returning if the failure can be retried
+func (n *notifier) post(ctx context.Context, url, contentType string, payload []byte) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", contentType)
+
+	resp, err := n.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	// @note: we drain the body so the connection can be reused
+	//nolint:errcheck
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return true, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return false, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+}
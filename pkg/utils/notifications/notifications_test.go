@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+)
+
+// receiver is a local http server recording the requests it receives
+type receiver struct {
+	sync.Mutex
+	// bodies are the payloads received
+	bodies [][]byte
+	// contentTypes are the content types received
+	contentTypes []string
+	// responses are the status codes returned in order, the last is repeated
+	responses []int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	r.bodies = append(r.bodies, body)
+	r.contentTypes = append(r.contentTypes, req.Header.Get("Content-Type"))
+
+	code := http.StatusOK
+	if len(r.responses) > 0 {
+		code = r.responses[0]
+		if len(r.responses) > 1 {
+			r.responses = r.responses[1:]
+		}
+	}
+	w.WriteHeader(code)
+}
+
+func newTestEvent() Event {
+	return Event{
+		Configuration: "bucket",
+		Generation:    2,
+		ID:            "uid-DriftDetected-1660000000",
+		Message:       "Drift has been detected in the resource (1 refresh-only change)",
+		Namespace:     "apps",
+		Time:          time.Date(2022, 8, 5, 15, 0, 0, 0, time.UTC),
+		Type:          terraformv1alphav1.NotificationDriftDetected,
+		UID:           "uid",
+	}
+}
+
+func newTestNotifier(t *testing.T, responses ...int) (*receiver, *httptest.Server, Notifier) {
+	r := &receiver{responses: responses}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return r, server, New(Options{Attempts: 3, Backoff: time.Millisecond})
+}
+
+func TestNewDefaults(t *testing.T) {
+	n := New(Options{}).(*notifier)
+
+	assert.Equal(t, DefaultAttempts, n.options.Attempts)
+	assert.Equal(t, DefaultBackoff, n.options.Backoff)
+	assert.Equal(t, DefaultSource, n.options.Source)
+	assert.Equal(t, DefaultTimeout, n.client.Timeout)
+}
+
+func TestSendJSON(t *testing.T) {
+	r, server, n := newTestNotifier(t)
+
+	err := n.Send(context.Background(), Sink{Name: "test", URL: server.URL}, newTestEvent())
+	require.NoError(t, err)
+	require.Len(t, r.bodies, 1)
+	assert.Equal(t, "application/json", r.contentTypes[0])
+
+	event := Event{}
+	require.NoError(t, json.Unmarshal(r.bodies[0], &event))
+	assert.Equal(t, newTestEvent(), event)
+}
+
+func TestSendSlack(t *testing.T) {
+	r, server, n := newTestNotifier(t)
+
+	sink := Sink{Format: terraformv1alphav1.NotificationFormatSlack, Name: "test", URL: server.URL}
+	require.NoError(t, n.Send(context.Background(), sink, newTestEvent()))
+	require.Len(t, r.bodies, 1)
+
+	message := SlackMessage{}
+	require.NoError(t, json.Unmarshal(r.bodies[0], &message))
+	assert.Equal(t, "*DriftDetected* configuration apps/bucket: Drift has been detected in the resource (1 refresh-only change)", message.Text)
+}
+
+func TestSendCloudEvents(t *testing.T) {
+	r, server, n := newTestNotifier(t)
+
+	sink := Sink{Format: terraformv1alphav1.NotificationFormatCloudEvents, Name: "test", URL: server.URL}
+	require.NoError(t, n.Send(context.Background(), sink, newTestEvent()))
+	require.Len(t, r.bodies, 1)
+	assert.Equal(t, "application/cloudevents+json", r.contentTypes[0])
+
+	event := CloudEvent{}
+	require.NoError(t, json.Unmarshal(r.bodies[0], &event))
+	assert.Equal(t, "1.0", event.SpecVersion)
+	assert.Equal(t, "uid-DriftDetected-1660000000", event.ID)
+	assert.Equal(t, DefaultSource, event.Source)
+	assert.Equal(t, "apps/bucket", event.Subject)
+	assert.Equal(t, "2022-08-05T15:00:00Z", event.Time)
+	assert.Equal(t, "io.appvia.terraform.configuration.driftdetected", event.Type)
+	assert.Equal(t, newTestEvent(), event.Data)
+}
+
+func TestSendRetriesOnServerError(t *testing.T) {
+	r, server, n := newTestNotifier(t, http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK)
+
+	require.NoError(t, n.Send(context.Background(), Sink{Name: "test", URL: server.URL}, newTestEvent()))
+	assert.Len(t, r.bodies, 3)
+}
+
+func TestSendGivesUpAfterAttempts(t *testing.T) {
+	r, server, n := newTestNotifier(t, http.StatusBadGateway)
+
+	err := n.Send(context.Background(), Sink{Name: "test", URL: server.URL}, newTestEvent())
+	require.Error(t, err)
+	assert.Equal(t, "failed to send the event to notification sink \"test\" after 3 attempt(s), unexpected status code 502", err.Error())
+	assert.Len(t, r.bodies, 3)
+}
+
+func TestSendDoesNotRetryClientError(t *testing.T) {
+	r, server, n := newTestNotifier(t, http.StatusBadRequest)
+
+	err := n.Send(context.Background(), Sink{Name: "test", URL: server.URL}, newTestEvent())
+	require.Error(t, err)
+	assert.Equal(t, "failed to send the event to notification sink \"test\" after 1 attempt(s), unexpected status code 400", err.Error())
+	assert.Len(t, r.bodies, 1)
+}
+
+func TestSendRetriesConnectionError(t *testing.T) {
+	_, server, n := newTestNotifier(t)
+	server.Close()
+
+	err := n.Send(context.Background(), Sink{Name: "test", URL: server.URL}, newTestEvent())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 3 attempt(s)")
+}
+
+func TestSendNoURL(t *testing.T) {
+	err := New(Options{}).Send(context.Background(), Sink{Name: "test"}, newTestEvent())
+	require.Error(t, err)
+	assert.Equal(t, "notification sink \"test\" has no url", err.Error())
+}
+
+func TestRenderUnsupportedFormat(t *testing.T) {
+	_, _, err := Render("xml", DefaultSource, newTestEvent())
+	require.Error(t, err)
+	assert.Equal(t, "unsupported notification format \"xml\"", err.Error())
+}
@@ -90,3 +90,164 @@ func FindMatchingPolicy(
 
 	return matches[0].(*terraformv1alphav1.Policy).Spec.Constraints.Checkov, nil
 }
+
+// FindMatchingDestroyProtection is called to find the destroy protection policy for a given configuration
+func FindMatchingDestroyProtection(
+	ctx context.Context,
+	configuration *terraformv1alphav1.Configuration,
+	namespace client.Object,
+	list *terraformv1alphav1.PolicyList) (*terraformv1alphav1.DestroyProtectionConstraint, error) {
+
+	return findMatchingConstraint(configuration, namespace, list, func(c *terraformv1alphav1.Constraints) *terraformv1alphav1.DestroyProtectionConstraint {
+		return c.DestroyProtection
+	})
+}
+
+// FindMatchingApplyWindow is called to find the default apply window for a given configuration
+func FindMatchingApplyWindow(
+	ctx context.Context,
+	configuration *terraformv1alphav1.Configuration,
+	namespace client.Object,
+	list *terraformv1alphav1.PolicyList) (*terraformv1alphav1.ApplyWindowConstraint, error) {
+
+	return findMatchingConstraint(configuration, namespace, list, func(c *terraformv1alphav1.Constraints) *terraformv1alphav1.ApplyWindowConstraint {
+		return c.ApplyWindow
+	})
+}
+
+// FindMatchingDriftSchedule is called to find the default drift schedule for a given configuration
+func FindMatchingDriftSchedule(
+	ctx context.Context,
+	configuration *terraformv1alphav1.Configuration,
+	namespace client.Object,
+	list *terraformv1alphav1.PolicyList) (*terraformv1alphav1.DriftScheduleConstraint, error) {
+
+	return findMatchingConstraint(configuration, namespace, list, func(c *terraformv1alphav1.Constraints) *terraformv1alphav1.DriftScheduleConstraint {
+		return c.DriftSchedule
+	})
+}
+
+// FindMatchingConcurrency is called to find the concurrency constraint for a given configuration
+func FindMatchingConcurrency(
+	ctx context.Context,
+	configuration *terraformv1alphav1.Configuration,
+	namespace client.Object,
+	list *terraformv1alphav1.PolicyList) (*terraformv1alphav1.ConcurrencyConstraint, error) {
+
+	return findMatchingConstraint(configuration, namespace, list, func(c *terraformv1alphav1.Constraints) *terraformv1alphav1.ConcurrencyConstraint {
+		return c.Concurrency
+	})
+}
+
+// FindMatchingNotifications is called to find all the notification sinks for a given configuration, unlike the
+// other constraints every matching sink is returned
+func FindMatchingNotifications(
+	ctx context.Context,
+	configuration *terraformv1alphav1.Configuration,
+	namespace client.Object,
+	list *terraformv1alphav1.PolicyList) ([]terraformv1alphav1.NotificationConstraint, error) {
+
+	var matches []terraformv1alphav1.NotificationConstraint
+
+	for i := 0; i < len(list.Items); i++ {
+		if list.Items[i].Spec.Constraints == nil {
+			continue
+		}
+
+		for _, sink := range list.Items[i].Spec.Constraints.Notifications {
+			_, matched, err := selectorWeight(sink.Selector, configuration, namespace)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				matches = append(matches, sink)
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// constraint is a policy constraint which can be scoped to configurations by a selector
+type constraint[T any] interface {
+	*T
+	GetSelector() *terraformv1alphav1.Selector
+}
+
+// findMatchingConstraint is called to find the constraint from the highest weighted policy matching the
+// configuration, the get method returns the constraint from the policy, or nil when it is not defined
+func findMatchingConstraint[T any, C constraint[T]](
+	configuration *terraformv1alphav1.Configuration,
+	namespace client.Object,
+	list *terraformv1alphav1.PolicyList,
+	get func(*terraformv1alphav1.Constraints) C) (C, error) {
+
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+
+	priority := weights.New()
+
+	for i := 0; i < len(list.Items); i++ {
+		if list.Items[i].Spec.Constraints == nil || get(list.Items[i].Spec.Constraints) == nil {
+			continue
+		}
+
+		weight, matched, err := selectorWeight(get(list.Items[i].Spec.Constraints).GetSelector(), configuration, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+		priority.Add(&list.Items[i], weight)
+	}
+
+	if priority.Size() == 0 {
+		return nil, nil
+	}
+
+	matches := priority.Highest()
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("multiple policies match configuration: %s", strings.Join(priority.HighestNames(), ", "))
+	}
+
+	return get(matches[0].(*terraformv1alphav1.Policy).Spec.Constraints), nil
+}
+
+// selectorWeight returns the weight of the selector and if the configuration is matched by it
+func selectorWeight(
+	selector *terraformv1alphav1.Selector,
+	configuration *terraformv1alphav1.Configuration,
+	namespace client.Object) (int, bool, error) {
+
+	var weight int
+
+	if selector == nil {
+		return weight, true, nil
+	}
+
+	if selector.Namespace != nil {
+		s, err := metav1.LabelSelectorAsSelector(selector.Namespace)
+		if err != nil {
+			return 0, false, err
+		}
+		if !s.Empty() && !s.Matches(labels.Set(namespace.GetLabels())) {
+			return 0, false, nil
+		}
+		weight += 10
+	}
+
+	if selector.Resource != nil {
+		s, err := metav1.LabelSelectorAsSelector(selector.Resource)
+		if err != nil {
+			return 0, false, err
+		}
+		if !s.Matches(labels.Set(configuration.GetLabels())) {
+			return 0, false, nil
+		}
+		weight += 20
+	}
+
+	return weight, true, nil
+}
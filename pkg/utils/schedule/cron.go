@@ -0,0 +1,209 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five field cron expression, i.e. minute hour day-of-month month day-of-week
+type Cron struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// anyDayOfMonth indicates the day of month field is a wildcard
+	anyDayOfMonth bool
+	// anyDayOfWeek indicates the day of week field is a wildcard
+	anyDayOfWeek bool
+}
+
+// field defines the bounds and names of a cron field
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// @note: both 0 and 7 are accepted as sunday
+	dayOfWeekField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseCron parses a standard five field cron expression. Each field supports wildcards, lists,
+// ranges and steps, i.e. "0 9-17/2 * * mon-fri"
+func ParseCron(expression string) (*Cron, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in cron expression %q, found %d", expression, len(fields))
+	}
+
+	c := &Cron{
+		anyDayOfMonth: fields[2] == "*" || fields[2] == "?",
+		anyDayOfWeek:  fields[4] == "*" || fields[4] == "?",
+	}
+
+	for i, x := range []struct {
+		bits  *uint64
+		field field
+	}{
+		{&c.minute, minuteField},
+		{&c.hour, hourField},
+		{&c.dayOfMonth, dayOfMonthField},
+		{&c.month, monthField},
+		{&c.dayOfWeek, dayOfWeekField},
+	} {
+		bits, err := parseField(fields[i], x.field)
+		if err != nil {
+			return nil, err
+		}
+		*x.bits = bits
+	}
+	if c.dayOfWeek&(1<<7) != 0 {
+		c.dayOfWeek |= 1
+	}
+
+	return c, nil
+}
+
+// Next returns the first time after t which matches the expression, or a zero time if none is
+// found within the next five years. The expression is evaluated in the location of t.
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + 5
+
+	for t.Year() <= limit {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+
+		case !c.isDayMatch(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// isDayMatch follows the cron convention, when both the day of month and day of week are
+// restricted either may match
+func (c *Cron) isDayMatch(t time.Time) bool {
+	dom := c.dayOfMonth&(1<<uint(t.Day())) != 0
+	dow := c.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case c.anyDayOfMonth && c.anyDayOfWeek:
+		return true
+	case c.anyDayOfMonth:
+		return dow
+	case c.anyDayOfWeek:
+		return dom
+	}
+
+	return dom || dow
+}
+
+// parseField parses a comma separated cron field into a bitmask
+func parseField(value string, f field) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(value, ",") {
+		step := 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			v, err := strconv.Atoi(item[i+1:])
+			if err != nil || v <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", item[i+1:], f.name)
+			}
+			step = v
+			item = item[:i]
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case item == "*" || item == "?":
+		case strings.Contains(item, "-"):
+			items := strings.SplitN(item, "-", 2)
+			var err error
+			if start, err = parseValue(items[0], f); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(items[1], f); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q in %s field", item, f.name)
+			}
+		default:
+			v, err := parseValue(item, f)
+			if err != nil {
+				return 0, err
+			}
+			start = v
+			if step == 1 {
+				end = v
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+// parseValue parses a single value or name within the bounds of the field
+func parseValue(value string, f field) (int, error) {
+	if v, found := f.names[strings.ToLower(value)]; found {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", value, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d in %s field must be between %d and %d", v, f.name, f.min, f.max)
+	}
+
+	return v, nil
+}
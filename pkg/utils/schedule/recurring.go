@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schedule
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Recurring is a recurring schedule, defined by either a cron expression or a fixed interval
+type Recurring struct {
+	// cron is the expression defining the schedule
+	cron *Cron
+	// interval is the time between occurrences when no expression is defined
+	interval time.Duration
+	// location is the time zone the expression is evaluated in
+	location *time.Location
+}
+
+// NewRecurring returns a schedule from the cron expression, or the interval when the expression is
+// empty. The time zone only applies to the expression and defaults to UTC
+func NewRecurring(expression string, interval time.Duration, timezone string) (*Recurring, error) {
+	switch {
+	case expression != "" && interval > 0:
+		return nil, errors.New("schedule and interval are mutually exclusive")
+	case expression == "" && interval <= 0:
+		return nil, errors.New("interval must be greater than zero")
+	case expression == "":
+		return &Recurring{interval: interval}, nil
+	}
+
+	cron, err := ParseCron(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	location := time.UTC
+	if timezone != "" {
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", timezone, err)
+		}
+	}
+
+	return &Recurring{cron: cron, location: location}, nil
+}
+
+// Next returns the time the schedule next occurs after the given time, which is zero if the
+// schedule never occurs
+func (r *Recurring) Next(t time.Time) time.Time {
+	if r.cron == nil {
+		return t.Add(r.interval)
+	}
+
+	return r.cron.Next(t.In(r.location))
+}
+
+// Period returns the time between the next two occurrences of the schedule after the given time
+func (r *Recurring) Period(t time.Time) time.Duration {
+	if r.cron == nil {
+		return r.interval
+	}
+
+	next := r.Next(t)
+	if next.IsZero() {
+		return 0
+	}
+	after := r.Next(next)
+	if after.IsZero() {
+		return 0
+	}
+
+	return after.Sub(next)
+}
+
+// Jitter returns a duration between zero and the maximum, derived from the seed. The same seed always
+// returns the same duration, spreading out events which would otherwise occur at the same time
+func Jitter(seed string, max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	sum := sha256.Sum256([]byte(seed))
+
+	return time.Duration(binary.BigEndian.Uint64(sum[:8]) % uint64(max))
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	cases := map[string]string{
+		"* * * *":       "expected 5 fields in cron expression \"* * * *\", found 4",
+		"60 * * * *":    "value 60 in minute field must be between 0 and 59",
+		"* 9-5 * * *":   "invalid range \"9-5\" in hour field",
+		"* * 0 * *":     "value 0 in day of month field must be between 1 and 31",
+		"* * * foo *":   "invalid value \"foo\" in month field",
+		"*/0 * * * *":   "invalid step \"0\" in minute field",
+		"* * * * mon-x": "invalid value \"x\" in day of week field",
+	}
+	for expression, expected := range cases {
+		_, err := ParseCron(expression)
+		assert.Error(t, err, "case: %s", expression)
+		assert.Equal(t, expected, err.Error(), "case: %s", expression)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// @note: 2022-08-05 is a friday
+	now := time.Date(2022, 8, 5, 15, 0, 0, 0, time.UTC)
+
+	cases := map[string]time.Time{
+		"* * * * *":          time.Date(2022, 8, 5, 15, 1, 0, 0, time.UTC),
+		"*/15 * * * *":       time.Date(2022, 8, 5, 15, 15, 0, 0, time.UTC),
+		"0 9 * * *":          time.Date(2022, 8, 6, 9, 0, 0, 0, time.UTC),
+		"0 9 * * mon-thu":    time.Date(2022, 8, 8, 9, 0, 0, 0, time.UTC),
+		"30 22 * * 0":        time.Date(2022, 8, 7, 22, 30, 0, 0, time.UTC),
+		"30 22 * * 7":        time.Date(2022, 8, 7, 22, 30, 0, 0, time.UTC),
+		"0 0 1 * *":          time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 1 jan *":        time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 13 * fri":       time.Date(2022, 8, 12, 0, 0, 0, 0, time.UTC),
+		"0 8,12 * * *":       time.Date(2022, 8, 6, 8, 0, 0, 0, time.UTC),
+		"0 9-17/4 * * 1-5":   time.Date(2022, 8, 5, 17, 0, 0, 0, time.UTC),
+		"0 0 29 feb *":       time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		"15 15 5 aug fri":    time.Date(2022, 8, 5, 15, 15, 0, 0, time.UTC),
+		"0 15 5 aug *":       time.Date(2023, 8, 5, 15, 0, 0, 0, time.UTC),
+		"0 0 31 2 *":         {},
+		"59 23 31 dec 0-6":   time.Date(2022, 12, 1, 23, 59, 0, 0, time.UTC),
+		"0-59/30 0-23 * * *": time.Date(2022, 8, 5, 15, 30, 0, 0, time.UTC),
+	}
+	for expression, expected := range cases {
+		cron, err := ParseCron(expression)
+		require.NoError(t, err, "case: %s", expression)
+		assert.Equal(t, expected, cron.Next(now), "case: %s", expression)
+	}
+}
+
+func TestNewWindowErrors(t *testing.T) {
+	_, err := NewWindow("0 9 * * *", 0, "")
+	assert.Error(t, err)
+	assert.Equal(t, "window duration must be greater than zero", err.Error())
+
+	_, err = NewWindow("0 9 * * *", time.Hour, "Europe/Nowhere")
+	assert.Error(t, err)
+
+	_, err = NewWindow("bad", time.Hour, "")
+	assert.Error(t, err)
+}
+
+func TestWindowIsOpen(t *testing.T) {
+	// @note: opens at 09:00 monday to thursday for eight hours
+	window, err := NewWindow("0 9 * * mon-thu", 8*time.Hour, "Europe/London")
+	require.NoError(t, err)
+
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	cases := []struct {
+		Now  time.Time
+		Open bool
+		Next time.Time
+	}{
+		{
+			// friday at 3pm
+			Now:  time.Date(2022, 8, 5, 15, 0, 0, 0, london),
+			Next: time.Date(2022, 8, 8, 9, 0, 0, 0, london),
+		},
+		{
+			// thursday at 10am
+			Now:  time.Date(2022, 8, 4, 10, 0, 0, 0, london),
+			Open: true,
+		},
+		{
+			// thursday at 9am, the window has just opened
+			Now:  time.Date(2022, 8, 4, 9, 0, 0, 0, london),
+			Open: true,
+		},
+		{
+			// thursday at 5pm, the window has just closed
+			Now:  time.Date(2022, 8, 4, 17, 0, 0, 0, london),
+			Next: time.Date(2022, 8, 8, 9, 0, 0, 0, london),
+		},
+		{
+			// thursday at 8am in utc is 9am in london
+			Now:  time.Date(2022, 8, 4, 8, 30, 0, 0, time.UTC),
+			Open: true,
+		},
+	}
+	for _, c := range cases {
+		open, next := window.IsOpen(c.Now)
+		assert.Equal(t, c.Open, open, "case: %s", c.Now)
+		assert.True(t, c.Next.Equal(next), "case: %s, expected: %s, got: %s", c.Now, c.Next, next)
+	}
+}
+
+func TestNewRecurringErrors(t *testing.T) {
+	_, err := NewRecurring("0 * * * *", time.Hour, "")
+	assert.EqualError(t, err, "schedule and interval are mutually exclusive")
+
+	_, err = NewRecurring("", 0, "")
+	assert.EqualError(t, err, "interval must be greater than zero")
+
+	_, err = NewRecurring("0 25 * * *", 0, "")
+	assert.EqualError(t, err, "value 25 in hour field must be between 0 and 23")
+
+	_, err = NewRecurring("0 * * * *", 0, "Europe/Nowhere")
+	assert.Error(t, err)
+}
+
+func TestRecurringInterval(t *testing.T) {
+	r, err := NewRecurring("", 168*time.Hour, "")
+	require.NoError(t, err)
+
+	now := time.Date(2022, 6, 1, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, now.Add(168*time.Hour), r.Next(now))
+	assert.Equal(t, 168*time.Hour, r.Period(now))
+}
+
+func TestRecurringCron(t *testing.T) {
+	r, err := NewRecurring("0 * * * *", 0, "Europe/London")
+	require.NoError(t, err)
+
+	now := time.Date(2022, 6, 1, 10, 30, 0, 0, time.UTC)
+	assert.True(t, time.Date(2022, 6, 1, 11, 0, 0, 0, time.UTC).Equal(r.Next(now)))
+	assert.Equal(t, time.Hour, r.Period(now))
+}
+
+func TestJitter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), Jitter("uid", 0))
+	assert.Equal(t, Jitter("uid", time.Hour), Jitter("uid", time.Hour))
+	assert.NotEqual(t, Jitter("uid-1", time.Hour), Jitter("uid-2", time.Hour))
+
+	for _, seed := range []string{"a", "b", "c", "d"} {
+		jitter := Jitter(seed, time.Minute)
+		assert.True(t, jitter >= 0 && jitter < time.Minute)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Window is a recurring window of time, opened by a cron expression and remaining open for a duration
+type Window struct {
+	// cron is the expression which opens the window
+	cron *Cron
+	// duration is how long the window remains open
+	duration time.Duration
+	// location is the time zone the expression is evaluated in
+	location *time.Location
+}
+
+// NewWindow returns a window from the cron expression, duration and time zone, which defaults to UTC
+func NewWindow(expression string, duration time.Duration, timezone string) (*Window, error) {
+	if duration <= 0 {
+		return nil, errors.New("window duration must be greater than zero")
+	}
+
+	cron, err := ParseCron(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	location := time.UTC
+	if timezone != "" {
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", timezone, err)
+		}
+	}
+
+	return &Window{cron: cron, duration: duration, location: location}, nil
+}
+
+// IsOpen returns true if the window is open at the given time, otherwise false and the time the
+// window next opens, which is zero if the window never opens
+func (w *Window) IsOpen(now time.Time) (bool, time.Time) {
+	now = now.In(w.location)
+
+	// @note: the window is open if it was opened within the duration of now
+	opened := w.cron.Next(now.Add(-w.duration))
+	if !opened.IsZero() && !opened.After(now) {
+		return true, time.Time{}
+	}
+
+	return false, w.cron.Next(now)
+}
@@ -17,6 +17,11 @@
 
 package utils
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Contains checks a list has a value in it
 func Contains(v string, l []string) bool {
 	for _, x := range l {
@@ -38,3 +43,12 @@ func ContainsList(v []string, l []string) bool {
 
 	return false
 }
+
+// Truncate joins the list, limiting the number of items and noting how many were omitted
+func Truncate(l []string, max int) string {
+	if len(l) <= max {
+		return strings.Join(l, ", ")
+	}
+
+	return fmt.Sprintf("%s and %d more", strings.Join(l[:max], ", "), len(l)-max)
+}
@@ -44,3 +44,11 @@ func TestContainsList(t *testing.T) {
 	assert.True(t, ContainsList(a, b))
 	assert.False(t, ContainsList([]string{"x"}, b))
 }
+
+func TestTruncate(t *testing.T) {
+	list := []string{"a", "b", "c"}
+
+	assert.Equal(t, "a, b, c", Truncate(list, 3))
+	assert.Equal(t, "a, b and 1 more", Truncate(list, 2))
+	assert.Equal(t, "", Truncate(nil, 2))
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/utils"
+)
+
+// DefaultBackendTimeout is the default timeout of a request to retrieve the state
+const DefaultBackendTimeout = 30 * time.Second
+
+// backendConfigTF is the template used to render a terraform backend block
+var backendConfigTF = `terraform {
+  backend "{{ .Type }}" {
+    {{- if .Configuration }}
+    {{ toHCL .Configuration | nindent 4 }}
+    {{- end }}
+  }
+}
+`
+
+// Backend is the interface to a terraform state backend
+type Backend interface {
+	// Configuration returns the terraform backend configuration for the state
+	Configuration() ([]byte, error)
+	// GetState retrieves the terraform state, returning nil when no state exists
+	GetState(ctx context.Context) (*State, error)
+	// Type returns the type of backend
+	Type() terraformv1alphav1.BackendType
+}
+
+// BackendOptions are the options used to create a backend
+type BackendOptions struct {
+	// Client is the kubernetes client, used by the kubernetes backend
+	Client client.Client
+	// Configuration is the backend specific configuration
+	Configuration map[string]interface{}
+	// Credentials are the environment variables used to authenticate to the backend
+	Credentials map[string]string
+	// Key uniquely identifies the state within the backend
+	Key string
+	// Namespace is the namespace used by the kubernetes backend
+	Namespace string
+	// Timeout is the timeout of a request to retrieve the state from a http based backend
+	Timeout time.Duration
+	// Type is the type of backend
+	Type terraformv1alphav1.BackendType
+	// Workspace is the terraform workspace holding the state
+	Workspace string
+}
+
+// NewBackend returns a backend for the given options
+func NewBackend(options BackendOptions) (Backend, error) {
+	if options.Key == "" {
+		return nil, fmt.Errorf("backend key is required")
+	}
+	if options.Configuration == nil {
+		options.Configuration = make(map[string]interface{})
+	}
+	if options.Credentials == nil {
+		options.Credentials = make(map[string]string)
+	}
+	if options.Workspace == "" {
+		options.Workspace = terraformv1alphav1.DefaultWorkspace
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultBackendTimeout
+	}
+
+	switch options.Type {
+	case terraformv1alphav1.KubernetesBackendType, "":
+		return &kubernetesBackend{options: options}, nil
+	case terraformv1alphav1.S3BackendType:
+		return newS3Backend(options)
+	case terraformv1alphav1.GCSBackendType:
+		return newGCSBackend(options)
+	case terraformv1alphav1.AzureRMBackendType:
+		return newAzureRMBackend(options)
+	case terraformv1alphav1.HTTPBackendType:
+		return newHTTPBackend(options)
+	}
+
+	return nil, fmt.Errorf("backend type: %q is not supported", options.Type)
+}
+
+// renderBackend renders the terraform backend block
+func renderBackend(backendType terraformv1alphav1.BackendType, config map[string]interface{}) ([]byte, error) {
+	return utils.Template(backendConfigTF, map[string]interface{}{
+		"Configuration": config,
+		"Type":          backendType,
+	})
+}
+
+// getString returns the value of the configuration key as a string
+func getString(config map[string]interface{}, key string) string {
+	v, found := config[key]
+	if !found || v == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+// getCredential returns the first credential found from the keys
+func getCredential(credentials map[string]string, keys ...string) string {
+	for _, x := range keys {
+		if v := strings.TrimSpace(credentials[x]); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// copyConfiguration returns a shallow copy of the configuration
+func copyConfiguration(config map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		copied[k] = v
+	}
+
+	return copied
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package terraform
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+)
+
+// azureStorageVersion is the version of the azure storage api used to read the state
+const azureStorageVersion = "2020-10-02"
+
+// azureRMBackend keeps the terraform state in an azure storage account
+type azureRMBackend struct {
+	options BackendOptions
+	// account is the name of the storage account
+	account string
+	// client is the http client used to retrieve the state
+	client *http.Client
+	// container is the name of the storage container
+	container string
+	// endpoint is the blob endpoint of the storage account
+	endpoint string
+	// key is the name of the state blob
+	key string
+}
+
+// newAzureRMBackend returns an azurerm backend
+func newAzureRMBackend(options BackendOptions) (Backend, error) {
+	b := &azureRMBackend{
+		options:   options,
+		account:   getString(options.Configuration, "storage_account_name"),
+		client:    &http.Client{Timeout: options.Timeout},
+		container: getString(options.Configuration, "container_name"),
+		key:       path.Join(getString(options.Configuration, "key"), options.Key, "terraform.tfstate"),
+	}
+	switch {
+	case b.account == "":
+		return nil, errors.New("azurerm backend requires a storage_account_name")
+	case b.container == "":
+		return nil, errors.New("azurerm backend requires a container_name")
+	}
+	b.endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", b.account)
+
+	return b, nil
+}
+
+// Configuration returns the terraform backend configuration for the state
+func (a *azureRMBackend) Configuration() ([]byte, error) {
+	config := copyConfiguration(a.options.Configuration)
+	config["container_name"] = a.container
+	config["key"] = a.key
+	config["storage_account_name"] = a.account
+
+	return renderBackend(terraformv1alphav1.AzureRMBackendType, config)
+}
+
+// stateKey returns the name of the state blob for the workspace, terraform suffixes the key for
+// any workspace other than the default
+func (a *azureRMBackend) stateKey() string {
+	if a.options.Workspace == terraformv1alphav1.DefaultWorkspace {
+		return a.key
+	}
+
+	return a.key + "env:" + a.options.Workspace
+}
+
+// GetState retrieves the terraform state from the storage container
+func (a *azureRMBackend) GetState(ctx context.Context) (*State, error) {
+	accessKey := getCredential(a.options.Credentials, "ARM_ACCESS_KEY")
+	if accessKey == "" {
+		accessKey = getString(a.options.Configuration, "access_key")
+	}
+	sasToken := getCredential(a.options.Credentials, "ARM_SAS_TOKEN")
+	if sasToken == "" {
+		sasToken = getString(a.options.Configuration, "sas_token")
+	}
+
+	url := fmt.Sprintf("%s/%s/%s", a.endpoint, a.container, a.stateKey())
+	if accessKey == "" && sasToken != "" {
+		url = fmt.Sprintf("%s?%s", url, strings.TrimPrefix(sasToken, "?"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureStorageVersion)
+
+	switch {
+	case accessKey != "":
+		signature, err := a.sign(req, accessKey)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", a.account, signature))
+
+	case sasToken == "":
+		return nil, errors.New("azurerm backend requires an ARM_ACCESS_KEY or ARM_SAS_TOKEN to read the state")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected status code: %d retrieving the state from azure storage", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseState(content)
+}
+
+// sign produces the shared key signature for a get request
+func (a *azureRMBackend) sign(req *http.Request, accessKey string) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(accessKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid azure storage access key: %w", err)
+	}
+
+	canonicalized := []string{
+		req.Method,
+		"", // Content-Encoding
+		"", // Content-Language
+		"", // Content-Length
+		"", // Content-MD5
+		"", // Content-Type
+		"", // Date
+		"", // If-Modified-Since
+		"", // If-Match
+		"", // If-None-Match
+		"", // If-Unmodified-Since
+		"", // Range
+		"x-ms-date:" + req.Header.Get("x-ms-date"),
+		"x-ms-version:" + req.Header.Get("x-ms-version"),
+		fmt.Sprintf("/%s/%s/%s", a.account, a.container, a.stateKey()),
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join(canonicalized, "\n")))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Type returns the type of backend
+func (a *azureRMBackend) Type() terraformv1alphav1.BackendType {
+	return terraformv1alphav1.AzureRMBackendType
+}
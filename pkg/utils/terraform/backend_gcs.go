@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package terraform
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+
+	"cloud.google.com/go/storage"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+)
+
+// gcsBackend keeps the terraform state in a google cloud storage bucket
+type gcsBackend struct {
+	options BackendOptions
+	// bucket is the name of the bucket
+	bucket string
+	// prefix is the prefix of the state object
+	prefix string
+}
+
+// newGCSBackend returns a gcs backend
+func newGCSBackend(options BackendOptions) (Backend, error) {
+	b := &gcsBackend{
+		options: options,
+		bucket:  getString(options.Configuration, "bucket"),
+		prefix:  path.Join(getString(options.Configuration, "prefix"), options.Key),
+	}
+	if b.bucket == "" {
+		return nil, errors.New("gcs backend requires a bucket")
+	}
+
+	return b, nil
+}
+
+// Configuration returns the terraform backend configuration for the state
+func (g *gcsBackend) Configuration() ([]byte, error) {
+	config := copyConfiguration(g.options.Configuration)
+	config["bucket"] = g.bucket
+	config["prefix"] = g.prefix
+
+	return renderBackend(terraformv1alphav1.GCSBackendType, config)
+}
+
+// GetState retrieves the terraform state from the bucket
+func (g *gcsBackend) GetState(ctx context.Context) (*State, error) {
+	var options []option.ClientOption
+
+	if endpoint := getString(g.options.Configuration, "storage_custom_endpoint"); endpoint != "" {
+		options = append(options, option.WithEndpoint(endpoint))
+	}
+	switch {
+	case getCredential(g.options.Credentials, "GOOGLE_OAUTH_ACCESS_TOKEN") != "":
+		options = append(options, option.WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: getCredential(g.options.Credentials, "GOOGLE_OAUTH_ACCESS_TOKEN"),
+		})))
+	case getCredential(g.options.Credentials, "GOOGLE_CREDENTIALS") != "":
+		options = append(options, option.WithCredentialsJSON([]byte(getCredential(g.options.Credentials, "GOOGLE_CREDENTIALS"))))
+	}
+
+	client, err := storage.NewClient(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	reader, err := client.Bucket(g.bucket).Object(path.Join(g.prefix, g.options.Workspace+".tfstate")).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseState(content)
+}
+
+// Type returns the type of backend
+func (g *gcsBackend) Type() terraformv1alphav1.BackendType {
+	return terraformv1alphav1.GCSBackendType
+}
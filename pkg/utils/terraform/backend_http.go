@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package terraform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+)
+
+// httpBackend keeps the terraform state behind a http endpoint
+type httpBackend struct {
+	options BackendOptions
+	// address is the address of the state
+	address string
+	// client is the http client used to retrieve the state
+	client *http.Client
+}
+
+// newHTTPBackend returns a http backend
+func newHTTPBackend(options BackendOptions) (Backend, error) {
+	address := getString(options.Configuration, "address")
+	if address == "" {
+		return nil, errors.New("http backend requires an address")
+	}
+	if options.Workspace != terraformv1alphav1.DefaultWorkspace {
+		return nil, errors.New("http backend does not support workspaces")
+	}
+
+	return &httpBackend{
+		options: options,
+		address: fmt.Sprintf("%s/%s", strings.TrimSuffix(address, "/"), options.Key),
+		client:  &http.Client{Timeout: options.Timeout},
+	}, nil
+}
+
+// Configuration returns the terraform backend configuration for the state
+func (h *httpBackend) Configuration() ([]byte, error) {
+	config := copyConfiguration(h.options.Configuration)
+	config["address"] = h.address
+
+	for _, x := range []string{"lock_address", "unlock_address"} {
+		if v := getString(config, x); v != "" {
+			config[x] = fmt.Sprintf("%s/%s", strings.TrimSuffix(v, "/"), h.options.Key)
+		}
+	}
+
+	return renderBackend(terraformv1alphav1.HTTPBackendType, config)
+}
+
+// GetState retrieves the terraform state from the endpoint
+func (h *httpBackend) GetState(ctx context.Context) (*State, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	username := getCredential(h.options.Credentials, "TF_HTTP_USERNAME")
+	if username == "" {
+		username = getString(h.options.Configuration, "username")
+	}
+	password := getCredential(h.options.Credentials, "TF_HTTP_PASSWORD")
+	if password == "" {
+		password = getString(h.options.Configuration, "password")
+	}
+	if username != "" || password != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected status code: %d retrieving the state from %s", resp.StatusCode, h.address)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) == 0 {
+		return nil, nil
+	}
+
+	return ParseState(content)
+}
+
+// Type returns the type of backend
+func (h *httpBackend) Type() terraformv1alphav1.BackendType {
+	return terraformv1alphav1.HTTPBackendType
+}
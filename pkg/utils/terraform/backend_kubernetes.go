@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package terraform
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+)
+
+// kubernetesBackend keeps the terraform state in a secret within the cluster
+type kubernetesBackend struct {
+	options BackendOptions
+}
+
+// Configuration returns the terraform backend configuration for the state
+func (k *kubernetesBackend) Configuration() ([]byte, error) {
+	return NewKubernetesBackend(k.options.Namespace, k.options.Key)
+}
+
+// GetState retrieves the terraform state from the secret
+func (k *kubernetesBackend) GetState(ctx context.Context) (*State, error) {
+	if k.options.Client == nil {
+		return nil, fmt.Errorf("kubernetes backend requires a client")
+	}
+
+	secret := &v1.Secret{}
+	key := client.ObjectKey{Namespace: k.options.Namespace, Name: fmt.Sprintf("tfstate-%s-%s", k.options.Workspace, k.options.Key)}
+	if err := k.options.Client.Get(ctx, key, secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return DecodeState(secret.Data[terraformv1alphav1.TerraformStateSecretKey])
+}
+
+// Type returns the type of backend
+func (k *kubernetesBackend) Type() terraformv1alphav1.BackendType {
+	return terraformv1alphav1.KubernetesBackendType
+}
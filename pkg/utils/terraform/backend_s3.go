@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package terraform
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+)
+
+// s3Backend keeps the terraform state in a s3 compatible bucket
+type s3Backend struct {
+	options BackendOptions
+	// bucket is the name of the bucket
+	bucket string
+	// endpoint is an optional endpoint for s3 compatible storage
+	endpoint string
+	// key is the key of the state object
+	key string
+	// pathStyle indicates the bucket is addressed via the path
+	pathStyle bool
+	// region is the region of the bucket
+	region string
+}
+
+// newS3Backend returns a s3 backend
+func newS3Backend(options BackendOptions) (Backend, error) {
+	b := &s3Backend{
+		options:  options,
+		bucket:   getString(options.Configuration, "bucket"),
+		endpoint: getString(options.Configuration, "endpoint"),
+		key:      path.Join(getString(options.Configuration, "key"), options.Key, "terraform.tfstate"),
+		region:   getString(options.Configuration, "region"),
+	}
+	if b.bucket == "" {
+		return nil, errors.New("s3 backend requires a bucket")
+	}
+	if b.region == "" {
+		b.region = getCredential(options.Credentials, "AWS_REGION", "AWS_DEFAULT_REGION")
+	}
+	if b.region == "" {
+		b.region = "us-east-1"
+	}
+	for _, x := range []string{"force_path_style", "use_path_style"} {
+		if v, err := strconv.ParseBool(getString(options.Configuration, x)); err == nil && v {
+			b.pathStyle = true
+		}
+	}
+
+	return b, nil
+}
+
+// Configuration returns the terraform backend configuration for the state
+func (s *s3Backend) Configuration() ([]byte, error) {
+	config := copyConfiguration(s.options.Configuration)
+	config["bucket"] = s.bucket
+	config["key"] = s.key
+	config["region"] = s.region
+
+	return renderBackend(terraformv1alphav1.S3BackendType, config)
+}
+
+// stateKey returns the key of the state object for the workspace, terraform prefixes the key for
+// any workspace other than the default
+func (s *s3Backend) stateKey() string {
+	if s.options.Workspace == terraformv1alphav1.DefaultWorkspace {
+		return s.key
+	}
+	prefix := getString(s.options.Configuration, "workspace_key_prefix")
+	if prefix == "" {
+		prefix = "env:"
+	}
+
+	return path.Join(prefix, s.options.Workspace, s.key)
+}
+
+// GetState retrieves the terraform state from the bucket
+func (s *s3Backend) GetState(ctx context.Context) (*State, error) {
+	config := aws.NewConfig().WithRegion(s.region)
+	if s.endpoint != "" {
+		config = config.WithEndpoint(s.endpoint)
+	}
+	if s.pathStyle {
+		config = config.WithS3ForcePathStyle(true)
+	}
+	if id := getCredential(s.options.Credentials, "AWS_ACCESS_KEY_ID"); id != "" {
+		config = config.WithCredentials(credentials.NewStaticCredentials(id,
+			getCredential(s.options.Credentials, "AWS_SECRET_ACCESS_KEY"),
+			getCredential(s.options.Credentials, "AWS_SESSION_TOKEN"),
+		))
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s3.New(sess).GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.stateKey()),
+	})
+	if err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) {
+			switch aerr.Code() {
+			case s3.ErrCodeNoSuchKey, "NotFound":
+				return nil, nil
+			}
+		}
+
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseState(content)
+}
+
+// Type returns the type of backend
+func (s *s3Backend) Type() terraformv1alphav1.BackendType {
+	return terraformv1alphav1.S3BackendType
+}
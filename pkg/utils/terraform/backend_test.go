@@ -0,0 +1,416 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package terraform
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
+	"github.com/appvia/terraform-controller/pkg/schema"
+)
+
+var fakeState = `{"version":4,"terraform_version":"1.1.9","outputs":{"bucket":{"value":"test"}},"resources":[{"mode":"managed","type":"aws_s3_bucket","instances":[{}]}]}`
+
+func TestNewBackendUnsupported(t *testing.T) {
+	backend, err := NewBackend(BackendOptions{Type: "unknown", Key: "1234"})
+	assert.Error(t, err)
+	assert.Equal(t, "backend type: \"unknown\" is not supported", err.Error())
+	assert.Nil(t, backend)
+}
+
+func TestNewBackendKeyRequired(t *testing.T) {
+	backend, err := NewBackend(BackendOptions{Type: terraformv1alphav1.KubernetesBackendType})
+	assert.Error(t, err)
+	assert.Nil(t, backend)
+}
+
+func TestNewBackendValidation(t *testing.T) {
+	cases := []struct {
+		Type     terraformv1alphav1.BackendType
+		Expected string
+	}{
+		{Type: terraformv1alphav1.S3BackendType, Expected: "s3 backend requires a bucket"},
+		{Type: terraformv1alphav1.GCSBackendType, Expected: "gcs backend requires a bucket"},
+		{Type: terraformv1alphav1.AzureRMBackendType, Expected: "azurerm backend requires a storage_account_name"},
+		{Type: terraformv1alphav1.HTTPBackendType, Expected: "http backend requires an address"},
+	}
+	for _, c := range cases {
+		_, err := NewBackend(BackendOptions{Type: c.Type, Key: "1234"})
+		assert.Error(t, err)
+		assert.Equal(t, c.Expected, err.Error())
+	}
+}
+
+func TestBackendConfiguration(t *testing.T) {
+	cases := []struct {
+		Type          terraformv1alphav1.BackendType
+		Configuration map[string]interface{}
+		Expected      []string
+	}{
+		{
+			Type:     terraformv1alphav1.KubernetesBackendType,
+			Expected: []string{`backend "kubernetes"`, `namespace         = "terraform-system"`, `secret_suffix     = "1234"`},
+		},
+		{
+			Type:          terraformv1alphav1.S3BackendType,
+			Configuration: map[string]interface{}{"bucket": "state", "region": "eu-west-2", "key": "prefix"},
+			Expected:      []string{`backend "s3"`, `bucket = "state"`, `key = "prefix/1234/terraform.tfstate"`, `region = "eu-west-2"`},
+		},
+		{
+			Type:          terraformv1alphav1.GCSBackendType,
+			Configuration: map[string]interface{}{"bucket": "state"},
+			Expected:      []string{`backend "gcs"`, `bucket = "state"`, `prefix = "1234"`},
+		},
+		{
+			Type:          terraformv1alphav1.AzureRMBackendType,
+			Configuration: map[string]interface{}{"storage_account_name": "account", "container_name": "state"},
+			Expected:      []string{`backend "azurerm"`, `container_name = "state"`, `key = "1234/terraform.tfstate"`, `storage_account_name = "account"`},
+		},
+		{
+			Type:          terraformv1alphav1.HTTPBackendType,
+			Configuration: map[string]interface{}{"address": "https://state.local/states/", "lock_address": "https://state.local/locks"},
+			Expected:      []string{`backend "http"`, `address = "https://state.local/states/1234"`, `lock_address = "https://state.local/locks/1234"`},
+		},
+	}
+
+	for _, c := range cases {
+		backend, err := NewBackend(BackendOptions{
+			Configuration: c.Configuration,
+			Key:           "1234",
+			Namespace:     "terraform-system",
+			Type:          c.Type,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, c.Type, backend.Type())
+
+		config, err := backend.Configuration()
+		assert.NoError(t, err)
+		for _, x := range c.Expected {
+			assert.Contains(t, string(config), x)
+		}
+	}
+}
+
+func TestKubernetesBackendGetState(t *testing.T) {
+	encoded := &bytes.Buffer{}
+	w := gzip.NewWriter(encoded)
+	_, err := w.Write([]byte(fakeState))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	secret := &v1.Secret{}
+	secret.Namespace = "terraform-system"
+	secret.Name = "tfstate-default-1234"
+	secret.Data = map[string][]byte{terraformv1alphav1.TerraformStateSecretKey: encoded.Bytes()}
+
+	backend, err := NewBackend(BackendOptions{
+		Client:    fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(secret).Build(),
+		Key:       "1234",
+		Namespace: "terraform-system",
+		Type:      terraformv1alphav1.KubernetesBackendType,
+	})
+	require.NoError(t, err)
+
+	state, err := backend.GetState(context.TODO())
+	assert.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, 1, state.CountResources())
+	assert.Equal(t, "1.1.9", state.TerraformVersion)
+}
+
+func TestKubernetesBackendGetStateNotFound(t *testing.T) {
+	backend, err := NewBackend(BackendOptions{
+		Client:    fake.NewClientBuilder().WithScheme(schema.GetScheme()).Build(),
+		Key:       "1234",
+		Namespace: "terraform-system",
+	})
+	require.NoError(t, err)
+
+	state, err := backend.GetState(context.TODO())
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestKubernetesBackendGetStateWorkspace(t *testing.T) {
+	encoded, err := Encode([]byte(fakeState))
+	require.NoError(t, err)
+
+	secret := &v1.Secret{}
+	secret.Namespace = "terraform-system"
+	secret.Name = "tfstate-blue-1234"
+	secret.Data = map[string][]byte{terraformv1alphav1.TerraformStateSecretKey: encoded}
+
+	cc := fake.NewClientBuilder().WithScheme(schema.GetScheme()).WithRuntimeObjects(secret).Build()
+	for workspace, expected := range map[string]bool{"blue": true, "green": false, "": false} {
+		backend, err := NewBackend(BackendOptions{
+			Client:    cc,
+			Key:       "1234",
+			Namespace: "terraform-system",
+			Workspace: workspace,
+		})
+		require.NoError(t, err)
+
+		state, err := backend.GetState(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, expected, state != nil, "workspace: %q", workspace)
+	}
+}
+
+func TestBackendWorkspaceStateKey(t *testing.T) {
+	cases := []struct {
+		Configuration map[string]interface{}
+		Type          terraformv1alphav1.BackendType
+		Workspace     string
+		Expected      string
+	}{
+		{
+			Configuration: map[string]interface{}{"bucket": "state"},
+			Type:          terraformv1alphav1.S3BackendType,
+			Expected:      "1234/terraform.tfstate",
+		},
+		{
+			Configuration: map[string]interface{}{"bucket": "state"},
+			Type:          terraformv1alphav1.S3BackendType,
+			Workspace:     "blue",
+			Expected:      "env:/blue/1234/terraform.tfstate",
+		},
+		{
+			Configuration: map[string]interface{}{"bucket": "state", "workspace_key_prefix": "stacks"},
+			Type:          terraformv1alphav1.S3BackendType,
+			Workspace:     "blue",
+			Expected:      "stacks/blue/1234/terraform.tfstate",
+		},
+		{
+			Configuration: map[string]interface{}{"storage_account_name": "account", "container_name": "state"},
+			Type:          terraformv1alphav1.AzureRMBackendType,
+			Expected:      "1234/terraform.tfstate",
+		},
+		{
+			Configuration: map[string]interface{}{"storage_account_name": "account", "container_name": "state"},
+			Type:          terraformv1alphav1.AzureRMBackendType,
+			Workspace:     "blue",
+			Expected:      "1234/terraform.tfstateenv:blue",
+		},
+	}
+	for _, c := range cases {
+		backend, err := NewBackend(BackendOptions{
+			Configuration: c.Configuration,
+			Key:           "1234",
+			Type:          c.Type,
+			Workspace:     c.Workspace,
+		})
+		require.NoError(t, err)
+
+		switch b := backend.(type) {
+		case *s3Backend:
+			assert.Equal(t, c.Expected, b.stateKey())
+		case *azureRMBackend:
+			assert.Equal(t, c.Expected, b.stateKey())
+		}
+	}
+}
+
+func TestHTTPBackendWorkspaceUnsupported(t *testing.T) {
+	backend, err := NewBackend(BackendOptions{
+		Configuration: map[string]interface{}{"address": "https://state.example.com"},
+		Key:           "1234",
+		Type:          terraformv1alphav1.HTTPBackendType,
+		Workspace:     "blue",
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "http backend does not support workspaces", err.Error())
+	assert.Nil(t, backend)
+}
+
+func TestS3BackendGetState(t *testing.T) {
+	// @note: a minimal s3 compatible stand-in, serving objects using path style addressing
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method != http.MethodGet:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/"):
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == "/state/1234/terraform.tfstate":
+			fmt.Fprint(w, fakeState)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+		}
+	}))
+	defer server.Close()
+
+	for key, expected := range map[string]bool{"1234": true, "4321": false} {
+		backend, err := NewBackend(BackendOptions{
+			Configuration: map[string]interface{}{
+				"bucket":           "state",
+				"endpoint":         server.URL,
+				"force_path_style": true,
+			},
+			Credentials: map[string]string{"AWS_ACCESS_KEY_ID": "access", "AWS_SECRET_ACCESS_KEY": "secret"},
+			Key:         key,
+			Type:        terraformv1alphav1.S3BackendType,
+		})
+		require.NoError(t, err)
+
+		state, err := backend.GetState(context.TODO())
+		assert.NoError(t, err)
+		if !expected {
+			assert.Nil(t, state)
+
+			continue
+		}
+		require.NotNil(t, state)
+		assert.True(t, state.HasOutputs())
+	}
+}
+
+func TestAzureRMBackendGetState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path != "/state/1234/terraform.tfstate":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Query().Get("sig") == "token":
+			fmt.Fprint(w, fakeState)
+		case strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey account:") && r.Header.Get("x-ms-version") != "":
+			fmt.Fprint(w, fakeState)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	for _, credentials := range []map[string]string{
+		{"ARM_ACCESS_KEY": "a2V5"},
+		{"ARM_SAS_TOKEN": "?sv=2020-10-02&sig=token"},
+	} {
+		backend, err := NewBackend(BackendOptions{
+			Configuration: map[string]interface{}{"storage_account_name": "account", "container_name": "state"},
+			Credentials:   credentials,
+			Key:           "1234",
+			Type:          terraformv1alphav1.AzureRMBackendType,
+		})
+		require.NoError(t, err)
+		backend.(*azureRMBackend).endpoint = server.URL
+
+		state, err := backend.GetState(context.TODO())
+		assert.NoError(t, err)
+		require.NotNil(t, state)
+		assert.Equal(t, 1, state.CountResources())
+	}
+}
+
+func TestAzureRMBackendGetStateNoCredentials(t *testing.T) {
+	backend, err := NewBackend(BackendOptions{
+		Configuration: map[string]interface{}{"storage_account_name": "account", "container_name": "state"},
+		Key:           "1234",
+		Type:          terraformv1alphav1.AzureRMBackendType,
+	})
+	require.NoError(t, err)
+
+	state, err := backend.GetState(context.TODO())
+	assert.Error(t, err)
+	assert.Nil(t, state)
+}
+
+func TestHTTPBackendGetState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		switch {
+		case username != "user" || password != "pass":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/states/1234":
+			fmt.Fprint(w, fakeState)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	for key, expected := range map[string]bool{"1234": true, "4321": false} {
+		backend, err := NewBackend(BackendOptions{
+			Configuration: map[string]interface{}{"address": server.URL + "/states"},
+			Credentials:   map[string]string{"TF_HTTP_USERNAME": "user", "TF_HTTP_PASSWORD": "pass"},
+			Key:           key,
+			Type:          terraformv1alphav1.HTTPBackendType,
+		})
+		require.NoError(t, err)
+
+		state, err := backend.GetState(context.TODO())
+		assert.NoError(t, err)
+		if !expected {
+			assert.Nil(t, state)
+
+			continue
+		}
+		require.NotNil(t, state)
+		output := state.Outputs["bucket"]
+		assert.Equal(t, "test", output.String())
+	}
+}
+
+func TestHTTPBackendGetStateUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	backend, err := NewBackend(BackendOptions{
+		Configuration: map[string]interface{}{"address": server.URL},
+		Key:           "1234",
+		Type:          terraformv1alphav1.HTTPBackendType,
+	})
+	require.NoError(t, err)
+
+	state, err := backend.GetState(context.TODO())
+	assert.Error(t, err)
+	assert.Nil(t, state)
+}
+
+func TestHTTPBackendGetStateTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	backend, err := NewBackend(BackendOptions{
+		Configuration: map[string]interface{}{"address": server.URL},
+		Key:           "1234",
+		Timeout:       50 * time.Millisecond,
+		Type:          terraformv1alphav1.HTTPBackendType,
+	})
+	require.NoError(t, err)
+
+	state, err := backend.GetState(context.TODO())
+	assert.Error(t, err)
+	assert.Nil(t, state)
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package terraform
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+const (
+	// DriftKindConfig indicates applying the configuration would change the resource
+	DriftKindConfig = "config"
+	// DriftKindRefreshOnly indicates the resource was changed outside of terraform, but applying
+	// the configuration would only update the state
+	DriftKindRefreshOnly = "refresh-only"
+)
+
+const (
+	// maxAttributeLength is the maximum length of an attribute value in the report
+	maxAttributeLength = 256
+	// sensitiveValue is the value recorded in place of a sensitive attribute
+	sensitiveValue = "(sensitive value)"
+	// unknownValue is the value recorded for an attribute only known after the apply
+	unknownValue = "(known after apply)"
+)
+
+// DriftAttribute is an attribute of a resource which has changed
+type DriftAttribute struct {
+	// Name is the name of the attribute
+	Name string `json:"name"`
+	// Before is the value of the attribute before the change
+	Before string `json:"before,omitempty"`
+	// After is the value of the attribute after the change
+	After string `json:"after,omitempty"`
+}
+
+// DriftResource is a resource which has drifted from the configuration
+type DriftResource struct {
+	// Action is the action terraform would perform on the resource
+	Action string `json:"action"`
+	// Address is the absolute address of the resource
+	Address string `json:"address"`
+	// Attributes is a collection of the attributes which have changed
+	Attributes []DriftAttribute `json:"attributes,omitempty"`
+	// Kind is either a config or a refresh-only change
+	Kind string `json:"kind"`
+	// Type is the type of the resource i.e. aws_db_instance
+	Type string `json:"type,omitempty"`
+}
+
+// DriftReport is a report of the resources which have drifted
+type DriftReport struct {
+	// Resources is a collection of the drifted resources
+	Resources []DriftResource `json:"resources"`
+	// TerraformVersion is the version of terraform used
+	TerraformVersion string `json:"terraform_version,omitempty"`
+}
+
+// NewDriftReport produces a drift report from the refresh-only plan of the drift check and the terraform plan
+// run alongside it. Resources the plan would change are config changes, while resources changed outside of
+// terraform which the configuration does not correct are refresh-only changes. The plan is optional, in which
+// case all the drift is reported as refresh-only. Sensitive attribute values are redacted.
+func NewDriftReport(drift, plan *Plan) *DriftReport {
+	report := &DriftReport{
+		Resources:        []DriftResource{},
+		TerraformVersion: drift.TerraformVersion,
+	}
+	changed := make(map[string]bool)
+
+	if plan != nil {
+		for i := 0; i < len(plan.ResourceChanges); i++ {
+			change := &plan.ResourceChanges[i]
+			if change.Mode == "data" {
+				continue
+			}
+			switch action := change.Action(); action {
+			case "no-op", "read":
+				continue
+			default:
+				changed[change.Address] = true
+				report.Resources = append(report.Resources, newDriftResource(change, action, DriftKindConfig))
+			}
+		}
+	}
+
+	for i := 0; i < len(drift.ResourceDrift); i++ {
+		change := &drift.ResourceDrift[i]
+		if change.Mode == "data" || changed[change.Address] {
+			continue
+		}
+		report.Resources = append(report.Resources, newDriftResource(change, change.Action(), DriftKindRefreshOnly))
+	}
+
+	sort.SliceStable(report.Resources, func(i, j int) bool {
+		return report.Resources[i].Address < report.Resources[j].Address
+	})
+
+	return report
+}
+
+// Count returns the number of drifted resources of the given kind
+func (d *DriftReport) Count(kind string) int {
+	var count int
+
+	for _, x := range d.Resources {
+		if x.Kind == kind {
+			count++
+		}
+	}
+
+	return count
+}
+
+// newDriftResource returns a drifted resource from the change
+func newDriftResource(change *ResourceChange, action, kind string) DriftResource {
+	return DriftResource{
+		Action:     action,
+		Address:    change.Address,
+		Attributes: changedAttributes(change),
+		Kind:       kind,
+		Type:       change.Type,
+	}
+}
+
+// changedAttributes returns the top level attributes which differ between the before and after values
+// of the change. Resources being created or deleted have no attributes to compare
+func changedAttributes(change *ResourceChange) []DriftAttribute {
+	before, found := change.Change.Before.(map[string]interface{})
+	if !found {
+		return nil
+	}
+	after, found := change.Change.After.(map[string]interface{})
+	if !found {
+		return nil
+	}
+
+	names := make(map[string]bool)
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	var list []DriftAttribute
+	for name := range names {
+		unknown := isMarked(change.Change.AfterUnknown, name)
+		if !unknown && reflect.DeepEqual(before[name], after[name]) {
+			continue
+		}
+
+		attribute := DriftAttribute{Name: name}
+		switch {
+		case isMarked(change.Change.BeforeSensitive, name):
+			attribute.Before = sensitiveValue
+		default:
+			attribute.Before = formatValue(before[name])
+		}
+		switch {
+		case isMarked(change.Change.AfterSensitive, name):
+			attribute.After = sensitiveValue
+		case unknown:
+			attribute.After = unknownValue
+		default:
+			attribute.After = formatValue(after[name])
+		}
+		list = append(list, attribute)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// isMarked returns true if the attribute, or any value nested within it, is marked in the
+// sensitive or unknown values of the change
+func isMarked(marks interface{}, name string) bool {
+	switch v := marks.(type) {
+	case bool:
+		return v
+	case map[string]interface{}:
+		return hasMark(v[name])
+	}
+
+	return false
+}
+
+// hasMark returns true if the value contains a mark
+func hasMark(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case map[string]interface{}:
+		for _, x := range v {
+			if hasMark(x) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, x := range v {
+			if hasMark(x) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// formatValue returns a json representation of the value, truncated to a maximum length
+func formatValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	if len(encoded) > maxAttributeLength {
+		return string(encoded[:maxAttributeLength]) + "..."
+	}
+
+	return string(encoded)
+}
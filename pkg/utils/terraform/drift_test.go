@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2022  Appvia Ltd <info@appvia.io>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const driftPlan = `{
+  "terraform_version": "1.3.0",
+  "resource_drift": [
+    {
+      "address": "aws_s3_bucket.logs",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "change": {
+        "actions": ["update"],
+        "before": {"bucket": "logs", "tags": {"team": "a"}},
+        "after": {"bucket": "logs", "tags": {"team": "b"}}
+      }
+    },
+    {
+      "address": "aws_db_instance.db",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "change": {
+        "actions": ["update"],
+        "before": {"password": "old", "size": "small"},
+        "after": {"password": "new", "size": "large"},
+        "before_sensitive": {"password": true},
+        "after_sensitive": {"password": true}
+      }
+    }
+  ]
+}`
+
+const configPlan = `{
+  "terraform_version": "1.3.0",
+  "resource_changes": [
+    {
+      "address": "aws_db_instance.db",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "change": {
+        "actions": ["update"],
+        "before": {"password": "new", "size": "large", "arn": "arn:1"},
+        "after": {"password": "old", "size": "small"},
+        "after_unknown": {"arn": true},
+        "before_sensitive": {"password": true},
+        "after_sensitive": {"password": true}
+      }
+    },
+    {
+      "address": "aws_s3_bucket.logs",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "change": {"actions": ["no-op"]}
+    },
+    {
+      "address": "data.aws_caller_identity.current",
+      "mode": "data",
+      "type": "aws_caller_identity",
+      "change": {"actions": ["read"]}
+    }
+  ]
+}`
+
+func TestNewDriftReport(t *testing.T) {
+	drift := &Plan{}
+	require.NoError(t, json.Unmarshal([]byte(driftPlan), drift))
+	plan := &Plan{}
+	require.NoError(t, json.Unmarshal([]byte(configPlan), plan))
+
+	report := NewDriftReport(drift, plan)
+	assert.Equal(t, "1.3.0", report.TerraformVersion)
+	assert.Equal(t, []DriftResource{
+		{
+			Action:  "update",
+			Address: "aws_db_instance.db",
+			Attributes: []DriftAttribute{
+				{Name: "arn", Before: `"arn:1"`, After: unknownValue},
+				{Name: "password", Before: sensitiveValue, After: sensitiveValue},
+				{Name: "size", Before: `"large"`, After: `"small"`},
+			},
+			Kind: DriftKindConfig,
+			Type: "aws_db_instance",
+		},
+		{
+			Action:  "update",
+			Address: "aws_s3_bucket.logs",
+			Attributes: []DriftAttribute{
+				{Name: "tags", Before: `{"team":"a"}`, After: `{"team":"b"}`},
+			},
+			Kind: DriftKindRefreshOnly,
+			Type: "aws_s3_bucket",
+		},
+	}, report.Resources)
+	assert.Equal(t, 1, report.Count(DriftKindConfig))
+	assert.Equal(t, 1, report.Count(DriftKindRefreshOnly))
+}
+
+func TestNewDriftReportWithoutPlan(t *testing.T) {
+	drift := &Plan{}
+	require.NoError(t, json.Unmarshal([]byte(driftPlan), drift))
+
+	report := NewDriftReport(drift, nil)
+	assert.Len(t, report.Resources, 2)
+	assert.Equal(t, 0, report.Count(DriftKindConfig))
+	assert.Equal(t, 2, report.Count(DriftKindRefreshOnly))
+}
+
+func TestNewDriftReportNoChanges(t *testing.T) {
+	report := NewDriftReport(&Plan{}, &Plan{})
+	assert.Empty(t, report.Resources)
+	assert.Equal(t, 0, report.Count(DriftKindConfig))
+}
+
+func TestNewDriftReportCreate(t *testing.T) {
+	plan := &Plan{}
+	require.NoError(t, json.Unmarshal([]byte(`{"resource_changes": [{
+		"address": "aws_s3_bucket.logs",
+		"mode": "managed",
+		"change": {"actions": ["create"], "after": {"bucket": "logs"}}
+	}]}`), plan))
+
+	report := NewDriftReport(&Plan{}, plan)
+	assert.Len(t, report.Resources, 1)
+	assert.Equal(t, "create", report.Resources[0].Action)
+	assert.Empty(t, report.Resources[0].Attributes)
+}
+
+func TestIsMarked(t *testing.T) {
+	assert.True(t, isMarked(true, "any"))
+	assert.False(t, isMarked(nil, "any"))
+	assert.False(t, isMarked(map[string]interface{}{}, "any"))
+	assert.True(t, isMarked(map[string]interface{}{"any": true}, "any"))
+	assert.True(t, isMarked(map[string]interface{}{"any": []interface{}{false, true}}, "any"))
+	assert.True(t, isMarked(map[string]interface{}{"any": map[string]interface{}{"nested": true}}, "any"))
+	assert.False(t, isMarked(map[string]interface{}{"any": map[string]interface{}{"nested": false}}, "any"))
+}
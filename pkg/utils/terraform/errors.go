@@ -23,6 +23,8 @@ type ErrorDetection struct {
 	Regex string
 	// Message is cause of the error
 	Message string
+	// Retryable indicates the failure may be transient and the job can be retried
+	Retryable bool
 }
 
 var (
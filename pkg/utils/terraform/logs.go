@@ -25,8 +25,29 @@ import (
 
 var (
 	changeNotice = regexp.MustCompile("Your infrastructure matches the configuration.")
+	staleNotice  = regexp.MustCompile("Saved plan is stale")
+	lockNotice   = regexp.MustCompile("Error acquiring the state lock")
+	lockInfo     = regexp.MustCompile(`^[^A-Za-z]*(ID|Operation|Who|Created):\s+(.*?)\s*$`)
+	lockID       = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.\-]{0,61}[a-zA-Z0-9])?$`)
 )
 
+// StateLock is the holder of a terraform state lock
+type StateLock struct {
+	// ID is the identifier of the lock
+	ID string
+	// Operation is the terraform operation which acquired the lock
+	Operation string
+	// Who is the identity which acquired the lock
+	Who string
+	// Created is when the lock was acquired
+	Created string
+}
+
+// IsValidLockID returns true if the lock id is safe to pass to terraform and use as a label
+func IsValidLockID(id string) bool {
+	return lockID.MatchString(id)
+}
+
 // FindChangesInLogs is used to scan the logs for the terraform line which informs on changes
 func FindChangesInLogs(in io.Reader) (bool, error) {
 	scan := bufio.NewScanner(in)
@@ -39,3 +60,58 @@ func FindChangesInLogs(in io.Reader) (bool, error) {
 
 	return true, nil
 }
+
+// FindStalePlanInLogs is used to scan the logs for terraform refusing to apply an outdated saved plan
+func FindStalePlanInLogs(in io.Reader) (bool, error) {
+	scan := bufio.NewScanner(in)
+
+	for scan.Scan() {
+		if staleNotice.MatchString(scan.Text()) {
+			return true, nil
+		}
+	}
+
+	return false, scan.Err()
+}
+
+// FindStateLockInLogs is used to scan the logs for terraform failing to acquire the state lock, returning
+// the holder of the lock, or nil when the lock was not the cause of the failure
+func FindStateLockInLogs(in io.Reader) (*StateLock, error) {
+	var lock *StateLock
+
+	scan := bufio.NewScanner(in)
+	for scan.Scan() {
+		if lockNotice.MatchString(scan.Text()) {
+			lock = &StateLock{}
+
+			continue
+		}
+		if lock == nil {
+			continue
+		}
+
+		// @note: the lock info is indented and may be prefixed with the border terraform draws around errors
+		matches := lockInfo.FindStringSubmatch(scan.Text())
+		if len(matches) != 3 {
+			continue
+		}
+		switch matches[1] {
+		case "ID":
+			lock.ID = matches[2]
+		case "Operation":
+			lock.Operation = matches[2]
+		case "Who":
+			lock.Who = matches[2]
+		case "Created":
+			lock.Created = matches[2]
+		}
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	if lock == nil || lock.ID == "" {
+		return nil, nil
+	}
+
+	return lock, nil
+}
@@ -45,3 +45,74 @@ There is nothing
 	assert.NoError(t, err)
 	assert.True(t, found)
 }
+
+func TestFindStalePlanInLogs(t *testing.T) {
+	logs := `
+Acquiring state lock. This may take a few moments...
+Error: Saved plan is stale
+
+The given plan file can no longer be applied because the state was changed
+`
+	found, err := FindStalePlanInLogs(strings.NewReader(logs))
+	assert.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestFindStalePlanInLogsNotStale(t *testing.T) {
+	logs := `
+Apply complete! Resources: 1 added, 0 changed, 0 destroyed.
+`
+	found, err := FindStalePlanInLogs(strings.NewReader(logs))
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestFindStateLockInLogs(t *testing.T) {
+	logs := `
+Acquiring state lock. This may take a few moments...
+╷
+│ Error: Error acquiring the state lock
+│
+│ Error message: lease is already held
+│ Lock Info:
+│   ID:        9db590f1-b6fe-c5f2-2678-8804f089deba
+│   Path:
+│   Operation: OperationTypeApply
+│   Who:       jest@laptop
+│   Version:   1.5.7
+│   Created:   2023-10-01 12:00:00.000000 +0000 UTC
+│   Info:
+╵
+`
+	lock, err := FindStateLockInLogs(strings.NewReader(logs))
+	assert.NoError(t, err)
+	assert.Equal(t, &StateLock{
+		ID:        "9db590f1-b6fe-c5f2-2678-8804f089deba",
+		Operation: "OperationTypeApply",
+		Who:       "jest@laptop",
+		Created:   "2023-10-01 12:00:00.000000 +0000 UTC",
+	}, lock)
+}
+
+func TestFindStateLockInLogsNotLocked(t *testing.T) {
+	logs := `
+Acquiring state lock. This may take a few moments...
+Apply complete! Resources: 1 added, 0 changed, 0 destroyed.
+`
+	lock, err := FindStateLockInLogs(strings.NewReader(logs))
+	assert.NoError(t, err)
+	assert.Nil(t, lock)
+}
+
+func TestIsValidLockID(t *testing.T) {
+	for id, expected := range map[string]bool{
+		"9db590f1-b6fe-c5f2-2678-8804f089deba": true,
+		"1696161600000000":                     true,
+		"":                                     false,
+		"-force":                               false,
+		"9db590f1; rm -rf /":                   false,
+		strings.Repeat("a", 64):                false,
+	} {
+		assert.Equal(t, expected, IsValidLockID(id), "id: %q", id)
+	}
+}
@@ -71,3 +71,65 @@ func (s *State) CountResources() int {
 func (s *State) HasOutputs() bool {
 	return len(s.Outputs) > 0
 }
+
+// ResourceImport is the import of an existing resource within the terraform plan
+type ResourceImport struct {
+	// ID is the identifier of the resource being imported
+	ID string `json:"id,omitempty"`
+}
+
+// ResourceChange is a change to a resource within the terraform plan
+type ResourceChange struct {
+	// Address is the absolute address of the resource
+	Address string `json:"address,omitempty"`
+	// Mode is the mode of the resource i.e. managed or data
+	Mode string `json:"mode,omitempty"`
+	// Type is the type of the resource i.e. aws_db_instance
+	Type string `json:"type,omitempty"`
+	// Change is the change terraform will make
+	Change struct {
+		// Actions is the collection of actions terraform will perform
+		Actions []string `json:"actions,omitempty"`
+		// After is the value of the resource attributes after the change
+		After interface{} `json:"after,omitempty"`
+		// AfterSensitive marks the attributes which are sensitive after the change
+		AfterSensitive interface{} `json:"after_sensitive,omitempty"`
+		// AfterUnknown marks the attributes which are only known after the apply
+		AfterUnknown interface{} `json:"after_unknown,omitempty"`
+		// Before is the value of the resource attributes before the change
+		Before interface{} `json:"before,omitempty"`
+		// BeforeSensitive marks the attributes which were sensitive before the change
+		BeforeSensitive interface{} `json:"before_sensitive,omitempty"`
+		// Importing is present when the resource is being imported into the state
+		Importing *ResourceImport `json:"importing,omitempty"`
+	} `json:"change"`
+}
+
+// IsImporting returns true if the resource is being imported into the state
+func (r *ResourceChange) IsImporting() bool {
+	return r.Change.Importing != nil
+}
+
+// Action returns the action terraform will perform on the resource, with a delete and create
+// of the same resource being a replacement
+func (r *ResourceChange) Action() string {
+	switch len(r.Change.Actions) {
+	case 0:
+		return "no-op"
+	case 1:
+		return r.Change.Actions[0]
+	}
+
+	return "replace"
+}
+
+// Plan is the json representation of a terraform plan
+type Plan struct {
+	// ResourceDrift is a collection of changes made to resources outside of terraform, detected
+	// when refreshing the state
+	ResourceDrift []ResourceChange `json:"resource_drift,omitempty"`
+	// ResourceChanges is a collection of changes to resources
+	ResourceChanges []ResourceChange `json:"resource_changes,omitempty"`
+	// TerraformVersion is the version of terraform used
+	TerraformVersion string `json:"terraform_version,omitempty"`
+}
@@ -21,7 +21,9 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/json"
+	"fmt"
 	"io"
+	"regexp"
 	"text/template"
 
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
@@ -31,6 +33,10 @@ import (
 // TerraformStateOutputsKey is the key for the terraform state outputs
 const TerraformStateOutputsKey = "outputs"
 
+// addressRegex matches the resource and module addresses we permit to be passed to terraform. Note, the
+// addresses are passed on the command line, so quotes and whitespace within instance keys are not permitted
+var addressRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.\-\["\]]*$`)
+
 // backendTF is responsible for creating the kubernetes backend terraform configuration
 var backendTF = `
 terraform {
@@ -50,6 +56,35 @@ var providerTF = `provider "{{ .Provider }}" {
 }
 `
 
+// importsTF is a template for the terraform import blocks
+var importsTF = `{{- range .Imports }}
+import {
+  to = {{ .Address }}
+  id = {{ .ID | replace "${" "$${" | replace "%{" "%%{" | quote }}
+}
+{{- end }}
+`
+
+// IsValidAddress returns true if the resource or module address is safe to pass to terraform
+func IsValidAddress(address string) bool {
+	return addressRegex.MatchString(address)
+}
+
+// Encode compresses the content in the same format as the terraform kubernetes backend
+func Encode(in []byte) ([]byte, error) {
+	encoded := &bytes.Buffer{}
+
+	w := gzip.NewWriter(encoded)
+	if _, err := w.Write(in); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return encoded.Bytes(), nil
+}
+
 // Decode decodes the terraform state returning the json output
 func Decode(state []byte) ([]byte, error) {
 	in, err := gzip.NewReader(bytes.NewReader(state))
@@ -67,14 +102,34 @@ func DecodeState(in []byte) (*State, error) {
 		return nil, err
 	}
 
+	return ParseState(decoded)
+}
+
+// ParseState parses the uncompressed terraform state
+func ParseState(in []byte) (*State, error) {
 	state := &State{}
-	if err := json.NewDecoder(bytes.NewReader(decoded)).Decode(state); err != nil {
+	if err := json.NewDecoder(bytes.NewReader(in)).Decode(state); err != nil {
 		return nil, err
 	}
 
 	return state, nil
 }
 
+// DecodePlan decodes the compressed json output of a terraform plan
+func DecodePlan(in []byte) (*Plan, error) {
+	decoded, err := Decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	if err := json.NewDecoder(bytes.NewReader(decoded)).Decode(plan); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
 // NewTerraformProvider generates a terraform provider configuration
 func NewTerraformProvider(provider string, configuration []byte) ([]byte, error) {
 	// @step: azure requires the configuration for features
@@ -98,6 +153,17 @@ func NewTerraformProvider(provider string, configuration []byte) ([]byte, error)
 	})
 }
 
+// NewTerraformImports generates the terraform import blocks for the existing resources
+func NewTerraformImports(imports []terraformv1alphav1.Import) ([]byte, error) {
+	for _, x := range imports {
+		if !IsValidAddress(x.Address) {
+			return nil, fmt.Errorf("import address %q is not a valid terraform address", x.Address)
+		}
+	}
+
+	return utils.Template(importsTF, map[string]interface{}{"Imports": imports})
+}
+
 // NewKubernetesBackend creates a new kubernetes backend
 func NewKubernetesBackend(namespace, suffux string) ([]byte, error) {
 	tmpl, err := template.New("main").Parse(backendTF)
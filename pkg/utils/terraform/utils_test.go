@@ -18,6 +18,8 @@
 package terraform
 
 import (
+	"bytes"
+	"compress/gzip"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -71,3 +73,85 @@ func TestNewTerraformProvider(t *testing.T) {
 		assert.Equal(t, string(c.Expected), string(x))
 	}
 }
+
+func TestDecodePlan(t *testing.T) {
+	encoded := &bytes.Buffer{}
+	w := gzip.NewWriter(encoded)
+	_, err := w.Write([]byte(`{"terraform_version":"1.1.9","resource_changes":[
+		{"address":"aws_s3_bucket.this","mode":"managed","change":{"actions":["create"]}},
+		{"address":"aws_iam_role.this","mode":"managed","change":{"actions":["delete","create"]}}
+	]}`))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	plan, err := DecodePlan(encoded.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, plan)
+	assert.Equal(t, "1.1.9", plan.TerraformVersion)
+	assert.Len(t, plan.ResourceChanges, 2)
+	assert.Equal(t, "aws_s3_bucket.this", plan.ResourceChanges[0].Address)
+	assert.Equal(t, "create", plan.ResourceChanges[0].Action())
+	assert.Equal(t, "replace", plan.ResourceChanges[1].Action())
+}
+
+func TestDecodePlanBad(t *testing.T) {
+	plan, err := DecodePlan([]byte("not compressed"))
+	assert.Error(t, err)
+	assert.Nil(t, plan)
+}
+
+func TestNewTerraformImports(t *testing.T) {
+	imports := []terraformv1alphav1.Import{
+		{Address: "aws_s3_bucket.this", ID: "my-bucket"},
+		{Address: "module.vpc.aws_vpc.this", ID: "vpc-${1}"},
+	}
+	expected := `
+import {
+  to = aws_s3_bucket.this
+  id = "my-bucket"
+}
+import {
+  to = module.vpc.aws_vpc.this
+  id = "vpc-$${1}"
+}
+`
+	encoded, err := NewTerraformImports(imports)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(encoded))
+}
+
+func TestNewTerraformImportsInvalidAddress(t *testing.T) {
+	encoded, err := NewTerraformImports([]terraformv1alphav1.Import{
+		{Address: "aws_s3_bucket.this\n}\nimport {", ID: "my-bucket"},
+	})
+	assert.Error(t, err)
+	assert.Nil(t, encoded)
+}
+
+func TestIsValidAddress(t *testing.T) {
+	for address, expected := range map[string]bool{
+		"aws_s3_bucket.this":             true,
+		"module.db":                      true,
+		"module.db.aws_db_instance.this": true,
+		`aws_instance.web["blue"]`:       true,
+		"aws_instance.web[0]":            true,
+		"":                               false,
+		"-lock=false":                    false,
+		"aws_instance.web; rm -rf /":     false,
+		"aws_instance.web'":              false,
+		`aws_instance.web["my server"]`:  false,
+		"aws_instance.web$(id)":          false,
+		"aws_instance.web\nmodule.other": false,
+	} {
+		assert.Equal(t, expected, IsValidAddress(address), "address: %q", address)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	encoded, err := Encode([]byte(`{"version": 4}`))
+	assert.NoError(t, err)
+
+	decoded, err := Decode(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"version": 4}`, string(decoded))
+}
@@ -44,7 +44,7 @@ func NewTerraformJob(configuration *terraformv1alphav1.Configuration, namespace,
 	}
 
 	if configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation] != "" {
-		job.Labels[terraformv1alphav1.DriftAnnotation] = configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]
+		job.Labels[terraformv1alphav1.ConfigurationDriftLabel] = configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation]
 	}
 
 	return job
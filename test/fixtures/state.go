@@ -20,12 +20,109 @@ package fixtures
 import (
 	"bytes"
 	"compress/gzip"
+	"fmt"
 
 	v1 "k8s.io/api/core/v1"
 
 	terraformv1alphav1 "github.com/appvia/terraform-controller/pkg/apis/terraform/v1alpha1"
 )
 
+var plan = `
+{
+  "format_version": "1.0",
+  "terraform_version": "1.1.9",
+  "resource_drift": [
+    {
+      "address": "aws_s3_bucket_acl.this",
+      "mode": "managed",
+      "type": "aws_s3_bucket_acl",
+      "name": "this",
+      "change": {
+        "actions": ["update"],
+        "before": {"acl": "private"},
+        "after": {"acl": "public-read"}
+      }
+    }
+  ],
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.this",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "this",
+      "change": {
+        "actions": ["create"]
+      }
+    },
+    {
+      "address": "aws_s3_bucket_policy.this",
+      "mode": "managed",
+      "type": "aws_s3_bucket_policy",
+      "name": "this",
+      "change": {
+        "actions": ["delete"]
+      }
+    }
+  ]
+}
+`
+
+var driftPlan = `
+{
+  "format_version": "1.0",
+  "terraform_version": "1.1.9",
+  "resource_drift": [
+    {
+      "address": "aws_s3_bucket_acl.this",
+      "mode": "managed",
+      "type": "aws_s3_bucket_acl",
+      "name": "this",
+      "change": {
+        "actions": ["update"],
+        "before": {"acl": "private"},
+        "after": {"acl": "public-read"}
+      }
+    },
+    {
+      "address": "aws_s3_bucket_policy.this",
+      "mode": "managed",
+      "type": "aws_s3_bucket_policy",
+      "name": "this",
+      "change": {
+        "actions": ["delete"]
+      }
+    }
+  ]
+}
+`
+
+var driftConfigPlan = `
+{
+  "format_version": "1.0",
+  "terraform_version": "1.1.9",
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket_acl.this",
+      "mode": "managed",
+      "type": "aws_s3_bucket_acl",
+      "name": "this",
+      "change": {
+        "actions": ["no-op"]
+      }
+    },
+    {
+      "address": "aws_s3_bucket_policy.this",
+      "mode": "managed",
+      "type": "aws_s3_bucket_policy",
+      "name": "this",
+      "change": {
+        "actions": ["create"]
+      }
+    }
+  ]
+}
+`
+
 var state = `
 {
 	"terraform_version": "1.1.9",
@@ -93,3 +190,60 @@ func NewTerraformState(configuration *terraformv1alphav1.Configuration) *v1.Secr
 
 	return secret
 }
+
+// NewTerraformPlan returns a fake saved plan for the current generation of the configuration
+func NewTerraformPlan(configuration *terraformv1alphav1.Configuration) *v1.Secret {
+	encoded := &bytes.Buffer{}
+
+	w := gzip.NewWriter(encoded)
+	//nolint:errcheck
+	w.Write([]byte(plan))
+	w.Close()
+
+	secret := &v1.Secret{}
+	secret.Name = configuration.GetTerraformPlanSecretName()
+	secret.Labels = map[string]string{
+		terraformv1alphav1.ConfigurationGenerationLabel: fmt.Sprintf("%d", configuration.GetGeneration()),
+		terraformv1alphav1.ConfigurationUIDLabel:        string(configuration.GetUID()),
+	}
+	secret.Data = map[string][]byte{
+		terraformv1alphav1.TerraformPlanSecretKey:     []byte("plan"),
+		terraformv1alphav1.TerraformPlanJSONSecretKey: encoded.Bytes(),
+	}
+
+	return secret
+}
+
+// NewTerraformDriftPlan returns the output of the refresh-only terraform plan, and the plan run alongside it,
+// for the current drift check
+func NewTerraformDriftPlan(configuration *terraformv1alphav1.Configuration, exitcode string) *v1.Secret {
+	encoded := &bytes.Buffer{}
+
+	w := gzip.NewWriter(encoded)
+	//nolint:errcheck
+	w.Write([]byte(driftPlan))
+	w.Close()
+
+	config := &bytes.Buffer{}
+
+	w = gzip.NewWriter(config)
+	//nolint:errcheck
+	w.Write([]byte(driftConfigPlan))
+	w.Close()
+
+	secret := &v1.Secret{}
+	secret.Name = configuration.GetTerraformDriftPlanSecretName()
+	secret.Labels = map[string]string{
+		terraformv1alphav1.ConfigurationGenerationLabel: fmt.Sprintf("%d", configuration.GetGeneration()),
+		terraformv1alphav1.ConfigurationStageLabel:      terraformv1alphav1.StageTerraformDrift,
+		terraformv1alphav1.ConfigurationUIDLabel:        string(configuration.GetUID()),
+		terraformv1alphav1.ConfigurationDriftLabel:      configuration.GetAnnotations()[terraformv1alphav1.DriftAnnotation],
+	}
+	secret.Data = map[string][]byte{
+		terraformv1alphav1.TerraformDriftConfigSecretKey:   config.Bytes(),
+		terraformv1alphav1.TerraformDriftExitCodeSecretKey: []byte(exitcode),
+		terraformv1alphav1.TerraformPlanJSONSecretKey:      encoded.Bytes(),
+	}
+
+	return secret
+}